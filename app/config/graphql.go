@@ -1,6 +1,10 @@
 package config
 
 import (
+	"net/http"
+	"net/url"
+	"time"
+	"vote/app/middleware"
 	graph "vote/graph/generated"
 	resolver "vote/graph/resolver"
 
@@ -11,6 +15,7 @@ import (
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/vektah/gqlparser/v2/ast"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 )
 
 func graphqlHandler() gin.HandlerFunc {
@@ -18,6 +23,13 @@ func graphqlHandler() gin.HandlerFunc {
 	// Resolver is in the resolver.go file
 	h := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &resolver.Resolver{}}))
 
+	// Subscription 走 WebSocket，需在 GET 之前處理升級請求
+	h.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: checkWebsocketOrigin,
+		},
+	})
 	h.AddTransport(transport.Options{})
 	h.AddTransport(transport.GET{})
 	h.AddTransport(transport.POST{})
@@ -34,6 +46,20 @@ func graphqlHandler() gin.HandlerFunc {
 	}
 }
 
+// checkWebsocketOrigin 只允許前端網址或同一主機建立 WebSocket，避免其他網站帶著 Cookie 訂閱
+func checkWebsocketOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" || origin == middleware.AllowedOrigin {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return u.Host == r.Host
+}
+
 // Defining the Playground handler
 func playgroundHandler() gin.HandlerFunc {
 	h := playground.Handler("GraphQL", "/query")
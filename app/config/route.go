@@ -20,7 +20,9 @@ import (
 
 func Routes(r *gin.Engine, m *persist.RedisStore) {
 	// Graphql
-	r.POST("/query", middleware.JWTAuthMiddleware(true), graphqlHandler())
+	graphql := graphqlHandler()
+	r.POST("/query", middleware.JWTAuthMiddleware(true), graphql)
+	r.GET("/query", middleware.JWTAuthMiddleware(true), graphql)
 	r.GET("/", playgroundHandler())
 
 	// Restful API
@@ -57,6 +59,9 @@ func Routes(r *gin.Engine, m *persist.RedisStore) {
 		middleware.JWTAuthMiddleware(false),
 		controller.NewBallotController().CreateBallots,
 	)
+	r.GET("/v1/receipt/:code", controller.NewBallotController().LookupReceipt)
+	r.GET("/v1/merkle/:vote_id", controller.NewMerkleController().GetRoot)
+	r.GET("/v1/merkle/proof/:code", controller.NewMerkleController().GetReceiptProofs)
 	// User
 	posts := r.Group("/v1/user")
 	{
@@ -110,6 +115,59 @@ func Routes(r *gin.Engine, m *persist.RedisStore) {
 			middleware.RoleMiddleware("vote", "delete"),
 			controller.NewVoteController().DeleteVote,
 		)
+		votes.GET("/:id/results",
+			middleware.RoleMiddleware("vote", "read"),
+			controller.NewVoteController().GetVoteResults,
+		)
+		votes.POST("/:id/results",
+			middleware.RoleMiddleware("vote", "update"),
+			controller.NewVoteController().TallyVote,
+		)
+		votes.POST("/:id/status",
+			middleware.RoleMiddleware("vote", "update"),
+			controller.NewVoteController().TransitionVote,
+		)
+		votes.GET("/:id/transitions",
+			middleware.RoleMiddleware("vote", "read"),
+			controller.NewVoteController().GetVoteTransitions,
+		)
+		votes.PUT("/:id/reminders",
+			middleware.RoleMiddleware("vote", "update"),
+			controller.NewVoteController().SetVoteReminders,
+		)
+		votes.GET("/:id/live",
+			middleware.RoleMiddleware("vote", "read"),
+			controller.NewVoteController().StreamLiveResult,
+		)
+		votes.GET("/:id/reminders",
+			middleware.RoleMiddleware("vote", "read"),
+			controller.NewVoteController().GetVoteReminders,
+		)
+		votes.GET("/:id/ledger",
+			middleware.RoleMiddleware("vote", "read"),
+			controller.NewVoteController().VerifyVoteLedger,
+		)
+		votes.POST("/:id/trustees",
+			middleware.RoleMiddleware("vote", "update"),
+			controller.NewVoteController().SetupTrustees,
+		)
+		votes.GET("/:id/trustees",
+			middleware.RoleMiddleware("vote", "read"),
+			controller.NewVoteController().GetTrustees,
+		)
+		votes.POST("/:id/merkle",
+			middleware.RoleMiddleware("vote", "update"),
+			controller.NewVoteController().PublishMerkleRoot,
+		)
+	}
+
+	// Trustee，以 X-Trustee-Token 驗證
+	trustees := r.Group("/v1/trustee")
+	{
+		trustees.GET("/ceremony", controller.NewTrusteeController().GetCeremony)
+		trustees.PUT("/key", controller.NewTrusteeController().SetTransportKey)
+		trustees.POST("/deal", controller.NewTrusteeController().Deal)
+		trustees.POST("/decrypt", controller.NewTrusteeController().Decrypt)
 	}
 
 	// Question
@@ -173,6 +231,34 @@ func Routes(r *gin.Engine, m *persist.RedisStore) {
 			middleware.RoleMiddleware("password", "read"),
 			controller.NewPasswordController().DecryptPassword,
 		)
+		passwords.GET("/export/:vote_id",
+			middleware.RoleMiddleware("password", "read"),
+			controller.NewPasswordController().ExportPasswordSheet,
+		)
+		passwords.GET("/download/:vote_id",
+			middleware.RoleMiddleware("password", "read"),
+			controller.NewPasswordController().ExportPasswords,
+		)
+		passwords.POST("/import/:vote_id",
+			middleware.RoleMiddleware("password", "create"),
+			controller.NewPasswordController().ImportPasswords,
+		)
+		passwords.POST("/revoke/:vote_id",
+			middleware.RoleMiddleware("password", "update"),
+			controller.NewPasswordController().RevokePasswords,
+		)
+		passwords.PUT("/expiry/:vote_id",
+			middleware.RoleMiddleware("password", "update"),
+			controller.NewPasswordController().SetPasswordExpiry,
+		)
+		passwords.GET("/audit/:vote_id",
+			middleware.RoleMiddleware("password", "read"),
+			controller.NewPasswordController().GetCredentialAudits,
+		)
+		passwords.GET("/audit/:vote_id/stats",
+			middleware.RoleMiddleware("password", "read"),
+			controller.NewPasswordController().GetCredentialAuditStats,
+		)
 		passwords.GET("/list/:vote_id",
 			middleware.RoleMiddleware("password", "read"),
 			controller.NewPasswordController().SelectAllPasswords,
@@ -182,4 +268,42 @@ func Routes(r *gin.Engine, m *persist.RedisStore) {
 			controller.NewPasswordController().UpdatePasswordStatus,
 		)
 	}
+
+	// Voter roll
+	voterRoll := r.Group("/v1/voter-roll", middleware.JWTAuthMiddleware(true))
+	{
+		voterRoll.POST("/:vote_id",
+			middleware.RoleMiddleware("password", "create"),
+			controller.NewVoterRollController().CreateVoters,
+		)
+		voterRoll.GET("/:vote_id",
+			middleware.RoleMiddleware("password", "read"),
+			controller.NewVoterRollController().GetVoters,
+		)
+		voterRoll.POST("/:vote_id/send",
+			middleware.RoleMiddleware("password", "update"),
+			controller.NewVoterRollController().SendCredentials,
+		)
+		voterRoll.POST("/:vote_id/voters/:voter_id/resend",
+			middleware.RoleMiddleware("password", "update"),
+			controller.NewVoterRollController().ResendCredential,
+		)
+		voterRoll.POST("/:vote_id/voters/:voter_id/reissue",
+			middleware.RoleMiddleware("password", "update"),
+			controller.NewVoterRollController().ReissueCredential,
+		)
+	}
+
+	// Mail queue
+	mails := r.Group("/v1/mail", middleware.JWTAuthMiddleware(true))
+	{
+		mails.GET("/progress/:vote_id",
+			middleware.RoleMiddleware("mail", "read"),
+			controller.NewMailController().GetProgress,
+		)
+		mails.POST("/retry/:vote_id",
+			middleware.RoleMiddleware("mail", "update"),
+			controller.NewMailController().RetryDead,
+		)
+	}
 }
@@ -3,11 +3,16 @@ package controller
 import (
 	"vote/app/enum"
 	"vote/app/middleware"
+	"vote/app/model"
 	"vote/app/service"
 
+	"encoding/json"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
 type BallotController struct {
@@ -21,14 +26,21 @@ func NewBallotController() BallotController {
 // @Summary
 // @tags 投票
 // @Summary 建立投票
-// @Description 建立投票
+// @Description 建立投票，成功時回傳回條代碼 receipt，可在開票後查詢選票是否已計入。加密投票需送出 model.EncryptedBallot。
+// @Description 帶上 Idempotency-Key 時，以相同的鍵與內容重送會回傳原本的回條，並帶有 Idempotent-Replayed 標頭。
+// @Description 投票開放重新投票時，已投票者再次送出會取代先前的選票，只計入最後一次
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "冪等鍵，16 到 255 個字元，建議使用 UUID"
 // @Success 200 {string} string "ok"
 // @Router /ballot/create [post]
 func (b BallotController) CreateBallots(c *gin.Context) {
-	var ballots map[uint64]map[uint64]bool
-	if err := c.ShouldBindJSON(&ballots); err != nil {
+	// 選票格式取決於投票是否為加密模式，先保留原始內容
+	body, err := c.GetRawData()
+	if err == nil && !json.Valid(body) {
+		err = errors.New("body is not valid JSON")
+	}
+	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"status": -1,
 			"msg":    "Invalid JSON format: " + err.Error(),
@@ -36,6 +48,15 @@ func (b BallotController) CreateBallots(c *gin.Context) {
 		})
 		return
 	}
+	idempotency, err := service.NewBallotIdempotency(c.GetHeader("Idempotency-Key"), body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    err.Error(),
+			"data":   nil,
+		})
+		return
+	}
 
 	token, err := c.Cookie("voter-token")
 	if err != nil {
@@ -54,35 +75,161 @@ func (b BallotController) CreateBallots(c *gin.Context) {
 		return
 	}
 
+	vote, err := service.NewVoteService().GetVote(claims.VoteID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"code": -1,
+			"msg":  "Vote not found: " + err.Error(),
+		})
+		return
+	}
+
+	// 已投票時只有以相同冪等鍵重送的請求會取得原本的回條，投票結束後重送也一樣；開放重新投票時可再次送出
 	ballotService := service.NewBallotService()
 	voter := claims.ID
-	if hasVoted, err := ballotService.CheckIfVoterHasVoted(voter); err != nil {
+	receipt, err := ballotService.PreviousBallot(voter, idempotency)
+	if errors.Is(err, service.ErrAlreadyVoted) && vote.AllowRevote {
+		err = nil
+	}
+	if err != nil {
+		handleBallotError(c, err)
+		return
+	}
+	if receipt != nil {
+		c.Header("Idempotent-Replayed", "true")
+		ballotCreated(c, claims, receipt)
+		return
+	}
+
+	// 檢查投票是否在開放時間內，結束前登入者可在寬限期內送出
+	if err := service.CheckBallotWindow(*vote, claims.LoginAt(), time.Now()); err != nil {
+		handleVoteWindowError(c, err)
+		return
+	}
+
+	if vote.Encrypted {
+		var ballot model.EncryptedBallot
+		if err := json.Unmarshal(body, &ballot); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status": -1,
+				"msg":    "Invalid JSON format: " + err.Error(),
+				"data":   nil,
+			})
+			return
+		}
+		receipt, err = ballotService.CreateEncryptedBallots(voter, *vote, ballot, idempotency)
+	} else {
+		var ballots model.BallotSelections
+		if err := json.Unmarshal(body, &ballots); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status": -1,
+				"msg":    "Invalid JSON format: " + err.Error(),
+				"data":   nil,
+			})
+			return
+		}
+		receipt, err = ballotService.CreateBallots(voter, *vote, ballots, idempotency)
+	}
+	if err != nil {
+		handleBallotError(c, err)
+		return
+	}
+
+	ballotCreated(c, claims, receipt)
+}
+
+// ballotCreated 回傳投票成功與回條代碼
+func ballotCreated(c *gin.Context, claims *middleware.VoterClaims, receipt *model.BallotReceipt) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":  0,
+		"msg":     "Vote successfully",
+		"voter":   claims.ID,
+		"voteId":  claims.VoteID,
+		"receipt": receipt.Code,
+	})
+}
+
+// handleBallotError 依送出選票的錯誤回傳對應的狀態碼
+func handleBallotError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrVoteClosed) {
+		handleVoteWindowError(c, err)
+		return
+	}
+	if errors.Is(err, service.ErrAlreadyVoted) {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"code": -1,
-			"msg":  "Failed to check if voter has voted: " + err.Error(),
+			"msg":  "Voter has already voted.",
 		})
 		return
-	} else if hasVoted {
-		c.JSON(http.StatusBadRequest, gin.H{
+	}
+	if errors.Is(err, service.ErrIdempotencyKeyReused) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
 			"code": -1,
-			"msg":  "Voter has already voted.",
+			"msg":  "Failed to create ballots: " + err.Error(),
 		})
 		return
 	}
+	if errors.Is(err, service.ErrCredentialUnavailable) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"code": -1,
+			"msg":  "Failed to create ballots: " + err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, service.ErrVoteNotEncrypted) {
+		c.JSON(http.StatusConflict, gin.H{
+			"code": -1,
+			"msg":  "Failed to create ballots: " + err.Error(),
+		})
+		return
+	}
+	var ballotErr *service.BallotError
+	if errors.As(err, &ballotErr) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid ballot: " + ballotErr.Error(),
+			"data":   ballotErr.Errors,
+		})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"status": -1,
+		"msg":    "Failed to create ballots: " + err.Error(),
+		"data":   nil,
+	})
+}
 
-	if err := ballotService.CreateBallots(voter, ballots); err != nil {
+// LookupReceipt 以回條代碼查詢選票是否已計入開票結果
+// @Summary
+// @tags 投票
+// @Summary 查詢投票回條
+// @Description 公開查詢，持有回條代碼者可確認選票已計入開票結果，不會顯示投票內容
+// @Produce json
+// @Param code path string true "回條代碼"
+// @Success 200 {object} model.BallotReceiptLookup "ok"
+// @Router /receipt/{code} [get]
+func (b BallotController) LookupReceipt(c *gin.Context) {
+	lookup, err := service.NewBallotService().LookupReceipt(c.Param("code"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status": -1,
+			"msg":    "Receipt not found",
+			"data":   nil,
+		})
+		return
+	}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status": -1,
-			"msg":    "Failed to create ballots: " + err.Error(),
+			"msg":    "Failed to look up receipt: " + err.Error(),
 			"data":   nil,
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"status":  0,
-		"msg":     "Vote successfully",
-		"voter":   claims.ID,
-		"voteId":  claims.VoteID,
+		"status": 0,
+		"msg":    "successfully look up receipt",
+		"data":   lookup,
 	})
 }
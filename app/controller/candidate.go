@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"vote/app/database"
@@ -167,6 +168,14 @@ func (ca CandidateController) CreateCandidate(c *gin.Context) {
 
 	candidateService := service.NewCandidateService()
 	candidate, err := candidateService.CreateCandidate(form)
+	if errors.Is(err, service.ErrVoteLocked) {
+		c.JSON(http.StatusConflict, gin.H{
+			"status": -1,
+			"msg":    "Failed to create candidate: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status": -1,
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"net/http"
+	"vote/app/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type MailController struct {
+}
+
+func NewMailController() MailController {
+	return MailController{}
+}
+
+// GetProgress 取得投票郵件的寄送進度
+// @Summary
+// @tags 郵件
+// @Summary 取得郵件寄送進度
+// @Description 依狀態統計投票郵件佇列的數量，僅限管理員
+// @Produce json
+// @Param vote_id path string true "投票ID"
+// @Param template query string false "郵件範本，例如 invitation"
+// @Success 200 {object} model.MailProgress "ok"
+// @Router /mail/progress/{vote_id} [get]
+func (m MailController) GetProgress(c *gin.Context) {
+	voteId, err := uuid.Parse(c.Param("vote_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid vote ID",
+			"data":   nil,
+		})
+		return
+	}
+
+	progress, err := service.NewMailQueueService().GetProgress(voteId, c.Query("template"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to select mail progress: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "successfully select mail progress",
+		"data":   progress,
+	})
+}
+
+// RetryDead 重新寄送超過重試次數的郵件
+// @Summary
+// @tags 郵件
+// @Summary 重新寄送失敗的郵件
+// @Description 將投票中超過重試次數的郵件重新排入佇列，僅限管理員
+// @Produce json
+// @Param vote_id path string true "投票ID"
+// @Success 200 {object} map[string]int64 "ok"
+// @Router /mail/retry/{vote_id} [post]
+func (m MailController) RetryDead(c *gin.Context) {
+	voteId, err := uuid.Parse(c.Param("vote_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid vote ID",
+			"data":   nil,
+		})
+		return
+	}
+
+	requeued, err := service.NewMailQueueService().RetryDead(voteId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to retry mails: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "successfully retry mails",
+		"data": gin.H{
+			"requeued": requeued,
+		},
+	})
+}
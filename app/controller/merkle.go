@@ -0,0 +1,106 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"vote/app/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type MerkleController struct {
+}
+
+func NewMerkleController() MerkleController {
+	return MerkleController{}
+}
+
+// GetRoot 取得投票已公布的 Merkle 樹根與簽章
+// @Summary
+// @tags 驗證
+// @Summary 取得選票 Merkle 樹根
+// @Description 公開查詢，回傳樹根、選票數、簽章、簽署者地址與區塊鏈交易
+// @Produce json
+// @Param vote_id path string true "投票ID"
+// @Success 200 {object} model.MerkleRoot "ok"
+// @Router /merkle/{vote_id} [get]
+func (m MerkleController) GetRoot(c *gin.Context) {
+	voteId, err := uuid.Parse(c.Param("vote_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid vote ID",
+			"data":   nil,
+		})
+		return
+	}
+
+	record, err := service.NewMerkleService().GetRoot(voteId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status": -1,
+			"msg":    "Merkle root not published",
+			"data":   nil,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to get merkle root: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "Successfully get merkle root",
+		"data":   record,
+	})
+}
+
+// GetReceiptProofs 以回條代碼取得選票的包含證明
+// @Summary
+// @tags 驗證
+// @Summary 取得選票包含證明
+// @Description 公開查詢，回傳樹根、簽章與回條中每張選票的包含證明，可交由 cmd/verifyMerkle 離線驗證，不會顯示投票內容
+// @Produce json
+// @Param code path string true "回條代碼"
+// @Success 200 {object} model.MerkleProofBundle "ok"
+// @Router /merkle/proof/{code} [get]
+func (m MerkleController) GetReceiptProofs(c *gin.Context) {
+	bundle, err := service.NewMerkleService().GetReceiptProofs(c.Param("code"))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status": -1,
+			"msg":    "Receipt not found or merkle root not published",
+			"data":   nil,
+		})
+		return
+	}
+	if errors.Is(err, service.ErrReceiptSuperseded) {
+		c.JSON(http.StatusGone, gin.H{
+			"status": -1,
+			"msg":    err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to get merkle proofs: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "Successfully get merkle proofs",
+		"data":   bundle,
+	})
+}
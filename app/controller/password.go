@@ -1,9 +1,11 @@
 package controller
 
 import (
-	"encoding/json"
-	"io"
+	"bytes"
+	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"vote/app/database"
 	"vote/app/model"
 	"vote/app/service"
@@ -11,6 +13,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
 )
 
 type PasswordController struct {
@@ -165,45 +169,61 @@ func (p PasswordController) SelectAllPasswords(c *gin.Context) {
 // @Summary
 // @tags 密碼
 // @Summary 解密密碼
-// @Description 解密密碼
+// @Description 解密指定投票中的密碼，僅限管理員或投票建立者
 // @Accept json
 // @Produce json
+// @Param body body model.PasswordDecrypt true "投票ID與密碼ID"
 // @Success 200 {string} string "ok"
 // @Router /password/decrypt [post]
 func (p PasswordController) DecryptPassword(c *gin.Context) {
-	jsonData, _ := io.ReadAll(c.Request.Body)
-	// json to Array
-	var passwords []string
-	err := json.Unmarshal(jsonData, &passwords)
-	if err != nil {
+	var form model.PasswordDecrypt
+	if err := c.ShouldBindJSON(&form); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"status": -1,
-			"msg":    "Invalid JSON format: " + err.Error(),
+			"msg":    "Invalid request: " + utils.ValidationErrorMessage(err),
 			"data":   nil,
 		})
 		return
 	}
-	if len(passwords) == 0 {
-		c.JSON(http.StatusBadRequest, gin.H{
+
+	userId := c.MustGet("id").(uint64)
+	isAdmin, err := database.CheckIfAdmin(userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
 			"status": -1,
-			"msg":    "No password provided",
+			"msg":    "Failed to check user role: " + err.Error(),
 			"data":   nil,
 		})
 		return
 	}
 
-	decrypts := make(map[string]string)
-	for _, password := range passwords {
-		decrypt, err := (&utils.Password{}).Decrypt(password)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"status": -1,
-				"msg":    "Failed to decrypt password [" + password + "]: " + err.Error(),
-				"data":   nil,
-			})
-			return
-		}
-		decrypts[password] = decrypt
+	vote, err := service.NewVoteService().GetVote(form.VoteID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status": -1,
+			"msg":    "Failed to select vote: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	if !isAdmin && vote.UserID != userId {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status": -1,
+			"msg":    "Permission denied",
+			"data":   nil,
+		})
+		return
+	}
+
+	decrypts, err := service.NewPasswordService().DecryptPassword(form.VoteID, form.IDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to decrypt password: " + err.Error(),
+			"data":   nil,
+		})
+		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -213,6 +233,326 @@ func (p PasswordController) DecryptPassword(c *gin.Context) {
 	})
 }
 
+// ExportPasswordSheet 匯出可列印的密碼條
+// @Summary
+// @tags 密碼
+// @Summary 匯出可列印的密碼條
+// @Description 將投票的密碼輸出為 PDF，每張密碼條包含投票標題、密碼與登入用的 QR code
+// @Produce application/pdf
+// @Param vote_id path string true "投票ID"
+// @Param per_page query int false "每頁密碼條數量" default(8)
+// @Param status query string false "密碼狀態 active、inactive 或 all" default(active)
+// @Success 200 {file} file "ok"
+// @Router /password/export/{vote_id} [get]
+func (p PasswordController) ExportPasswordSheet(c *gin.Context) {
+	var options model.PasswordExport
+	if err := c.ShouldBindQuery(&options); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid request: " + utils.ValidationErrorMessage(err),
+			"data":   nil,
+		})
+		return
+	}
+
+	vote, ok := ownVote(c, "vote_id")
+	if !ok {
+		return
+	}
+
+	var buffer bytes.Buffer
+	if err := service.NewPasswordService().ExportPasswordSheet(&buffer, *vote, options); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to export passwords: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"passwords-%s.pdf\"", vote.Uuid))
+	c.Data(http.StatusOK, "application/pdf", buffer.Bytes())
+}
+
+// ExportPasswords 匯出密碼
+// @Summary
+// @tags 密碼
+// @Summary 匯出密碼
+// @Description 將投票的密碼解密後匯出為 CSV 或 XLSX，僅限管理員或投票建立者
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param vote_id path string true "投票ID"
+// @Param format query string false "檔案格式 csv 或 xlsx" default(csv)
+// @Param status query string false "密碼狀態 active、inactive 或 all" default(all)
+// @Success 200 {file} file "ok"
+// @Router /password/download/{vote_id} [get]
+func (p PasswordController) ExportPasswords(c *gin.Context) {
+	var options model.PasswordDownload
+	if err := c.ShouldBindQuery(&options); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid request: " + utils.ValidationErrorMessage(err),
+			"data":   nil,
+		})
+		return
+	}
+
+	vote, ok := ownVote(c, "vote_id")
+	if !ok {
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if options.Format == "xlsx" {
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"passwords-%s.%s\"", vote.Uuid, options.Format))
+	c.Status(http.StatusOK)
+
+	// 已開始輸出內容，發生錯誤時只能記錄並中斷
+	if err := service.NewPasswordService().ExportPasswords(c.Writer, vote.Uuid, options); err != nil {
+		utils.Logger().WithFields(logrus.Fields{
+			"name": "Password",
+		}).Error("export error: ", err)
+		c.Abort()
+	}
+}
+
+// ImportPasswords 匯入預先發放的密碼
+// @Summary
+// @tags 密碼
+// @Summary 匯入預先發放的密碼
+// @Description 上傳 CSV 檔案匯入密碼，第一欄為密碼，回傳每一列的錯誤報告
+// @Accept multipart/form-data
+// @Produce json
+// @Param vote_id path string true "投票ID"
+// @Param file formData file true "CSV 檔案"
+// @Param active formData bool false "匯入後是否啟用"
+// @Success 200 {object} model.PasswordImportReport "ok"
+// @Router /password/import/{vote_id} [post]
+func (p PasswordController) ImportPasswords(c *gin.Context) {
+	vote, ok := ownVote(c, "vote_id")
+	if !ok {
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "CSV file is required: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Failed to open file: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+	defer file.Close()
+
+	active, _ := strconv.ParseBool(c.PostForm("active"))
+	report, err := service.NewPasswordService().ImportPasswords(vote.Uuid, file, active)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to import passwords: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "successfully import passwords",
+		"data":   report,
+	})
+}
+
+// RevokePasswords 撤銷密碼
+// @Summary
+// @tags 密碼
+// @Summary 撤銷密碼
+// @Description 撤銷遺失或外流的密碼，已投票的密碼不能撤銷
+// @Accept json
+// @Produce json
+// @Param vote_id path string true "投票ID"
+// @Param json body model.PasswordRevoke true "json"
+// @Success 200 {string} string "ok"
+// @Router /password/revoke/{vote_id} [post]
+func (p PasswordController) RevokePasswords(c *gin.Context) {
+	var form model.PasswordRevoke
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid request: " + utils.ValidationErrorMessage(err),
+			"data":   nil,
+		})
+		return
+	}
+
+	vote, ok := ownVote(c, "vote_id")
+	if !ok {
+		return
+	}
+
+	userId := c.MustGet("id").(uint64)
+	err := service.NewPasswordService().RevokePasswords(vote.Uuid, form.IDs, &userId, form.Reason)
+	if err != nil {
+		c.JSON(credentialErrorStatus(err), gin.H{
+			"status": -1,
+			"msg":    "Failed to revoke passwords: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "successfully revoke passwords",
+		"data":   nil,
+	})
+}
+
+// SetPasswordExpiry 設定密碼過期時間
+// @Summary
+// @tags 密碼
+// @Summary 設定密碼過期時間
+// @Description 設定或取消密碼的過期時間，過期的密碼無法登入與投票
+// @Accept json
+// @Produce json
+// @Param vote_id path string true "投票ID"
+// @Param json body model.PasswordExpiry true "json"
+// @Success 200 {string} string "ok"
+// @Router /password/expiry/{vote_id} [put]
+func (p PasswordController) SetPasswordExpiry(c *gin.Context) {
+	var form model.PasswordExpiry
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid request: " + utils.ValidationErrorMessage(err),
+			"data":   nil,
+		})
+		return
+	}
+
+	vote, ok := ownVote(c, "vote_id")
+	if !ok {
+		return
+	}
+
+	userId := c.MustGet("id").(uint64)
+	err := service.NewPasswordService().SetPasswordExpiry(vote.Uuid, form.IDs, form.ExpiresAt, &userId)
+	if err != nil {
+		c.JSON(credentialErrorStatus(err), gin.H{
+			"status": -1,
+			"msg":    "Failed to set password expiry: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "successfully set password expiry",
+		"data":   nil,
+	})
+}
+
+// GetCredentialAudits 取得密碼稽核紀錄
+// @Summary
+// @tags 密碼
+// @Summary 取得密碼稽核紀錄
+// @Description 取得密碼的啟用、投票、撤銷、過期與重新發放紀錄
+// @Produce json
+// @Param vote_id path string true "投票ID"
+// @Param password_id query int false "密碼ID"
+// @Param voter_id query int false "名冊投票者ID"
+// @Success 200 {array} model.CredentialAudit "ok"
+// @Router /password/audit/{vote_id} [get]
+func (p PasswordController) GetCredentialAudits(c *gin.Context) {
+	var query model.CredentialAuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid request: " + utils.ValidationErrorMessage(err),
+			"data":   nil,
+		})
+		return
+	}
+
+	vote, ok := ownVote(c, "vote_id")
+	if !ok {
+		return
+	}
+
+	audits, err := service.NewPasswordService().GetCredentialAudits(vote.Uuid, query)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to select credential audits: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "successfully select credential audits",
+		"data":   audits,
+	})
+}
+
+// GetCredentialAuditStats 取得密碼稽核統計
+// @Summary
+// @tags 密碼
+// @Summary 取得密碼稽核統計
+// @Description 取得各狀態的密碼數、已投票人數、選票送出次數，以及開放重新投票時被取代的選票次數
+// @Produce json
+// @Param vote_id path string true "投票ID"
+// @Success 200 {object} model.CredentialAuditStats "ok"
+// @Router /password/audit/{vote_id}/stats [get]
+func (p PasswordController) GetCredentialAuditStats(c *gin.Context) {
+	vote, ok := ownVote(c, "vote_id")
+	if !ok {
+		return
+	}
+
+	stats, err := service.NewPasswordService().GetCredentialAuditStats(vote.Uuid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to select credential audit stats: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "successfully select credential audit stats",
+		"data":   stats,
+	})
+}
+
+// credentialErrorStatus 密碼狀態變更錯誤對應的 HTTP 狀態碼
+func credentialErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrCredentialUsed):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
 // ChangePasswordStatus 更新密碼狀態
 // @Summary
 // @tags 密碼
@@ -276,7 +616,7 @@ func (p PasswordController) UpdatePasswordStatus(c *gin.Context) {
 		return
 	}
 	passwordService := service.NewPasswordService()
-	err = passwordService.UpdatePasswordStatus(voteUUID, form.Passwords, form.Status)
+	err = passwordService.UpdatePasswordStatus(voteUUID, form.Passwords, form.Status, &userId)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status": -1,
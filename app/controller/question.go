@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"vote/app/database"
@@ -267,7 +268,13 @@ func (q QuestionController) CreateQuestion(c *gin.Context) {
 	}
 
 	question, err := service.NewQuestionService().CreateQuestion(form)
-	if err != nil {
+	if errors.Is(err, service.ErrVoteLocked) {
+		c.JSON(http.StatusConflict, gin.H{
+			"status": -1,
+			"msg":    "Failed to create question: " + err.Error(),
+			"data":   nil,
+		})
+	} else if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status": -1,
 			"msg":    "Failed to create question: " + err.Error(),
@@ -83,6 +83,9 @@ func (r RbacController) Initial(c *gin.Context) {
 		if _, err = enforcer.AddPolicy(admin, "user", act); err != nil {
 			break
 		}
+		if _, err = enforcer.AddPolicy(admin, "mail", act); err != nil {
+			break
+		}
 	}
 	enforcer.AddRoleForUser(admin, creator)
 	
@@ -0,0 +1,224 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"vote/app/model"
+	"vote/app/service"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type TrusteeController struct {
+}
+
+func NewTrusteeController() TrusteeController {
+	return TrusteeController{}
+}
+
+// GetCeremony 取得受託人的金鑰產生與解密進度
+// @Summary
+// @tags 受託人
+// @Summary 取得金鑰產生與解密進度
+// @Description 以 X-Trustee-Token 驗證，回傳所有受託人的公開資料、發給自己的加密分片，投票結束且寬限期過後另外回傳合計密文
+// @Produce json
+// @Param X-Trustee-Token header string true "受託人權杖"
+// @Success 200 {object} model.TrusteeCeremony "ok"
+// @Router /trustee/ceremony [get]
+func (t TrusteeController) GetCeremony(c *gin.Context) {
+	trustee, ok := trusteeFromToken(c)
+	if !ok {
+		return
+	}
+
+	ceremony, err := service.NewTrusteeService().GetCeremony(*trustee)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to get ceremony: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "Successfully get ceremony",
+		"data":   ceremony,
+	})
+}
+
+// SetTransportKey 上傳受託人的傳輸公鑰
+// @Summary
+// @tags 受託人
+// @Summary 上傳傳輸公鑰
+// @Description 其他受託人以此公鑰加密發給此受託人的金鑰分片，開始發送分片後不可再更換
+// @Accept json
+// @Produce json
+// @Param X-Trustee-Token header string true "受託人權杖"
+// @Param key body model.TrusteeKey true "傳輸公鑰"
+// @Success 200 {string} string "ok"
+// @Router /trustee/key [put]
+func (t TrusteeController) SetTransportKey(c *gin.Context) {
+	trustee, ok := trusteeFromToken(c)
+	if !ok {
+		return
+	}
+
+	var form model.TrusteeKey
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid JSON format: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	if err := service.NewTrusteeService().SetTransportKey(*trustee, form.TransportKey); err != nil {
+		handleCeremonyError(c, "Failed to set transport key: ", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "Successfully set transport key",
+		"data":   nil,
+	})
+}
+
+// Deal 送出受託人的承諾與加密分片
+// @Summary
+// @tags 受託人
+// @Summary 送出金鑰分片
+// @Description 所有受託人都上傳傳輸公鑰後才可送出，最後一位受託人送出後產生選舉公鑰
+// @Accept json
+// @Produce json
+// @Param X-Trustee-Token header string true "受託人權杖"
+// @Param deal body model.TrusteeDeal true "承諾與加密分片"
+// @Success 200 {string} string "ok"
+// @Router /trustee/deal [post]
+func (t TrusteeController) Deal(c *gin.Context) {
+	trustee, ok := trusteeFromToken(c)
+	if !ok {
+		return
+	}
+
+	var form model.TrusteeDeal
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid JSON format: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	if err := service.NewTrusteeService().Deal(*trustee, form); err != nil {
+		handleCeremonyError(c, "Failed to deal shares: ", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "Successfully deal shares",
+		"data":   nil,
+	})
+}
+
+// Decrypt 送出受託人的部分解密
+// @Summary
+// @tags 受託人
+// @Summary 送出部分解密
+// @Description 投票結束且寬限期過後，受託人對每位候選人的合計密文部分解密並附上證明，達到門檻時自動開票並回傳結果
+// @Accept json
+// @Produce json
+// @Param X-Trustee-Token header string true "受託人權杖"
+// @Param partials body model.TrusteeDecrypt true "部分解密"
+// @Success 200 {object} model.VoteResult "ok"
+// @Router /trustee/decrypt [post]
+func (t TrusteeController) Decrypt(c *gin.Context) {
+	trustee, ok := trusteeFromToken(c)
+	if !ok {
+		return
+	}
+
+	var form model.TrusteeDecrypt
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid JSON format: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	result, err := service.NewTrusteeService().SubmitPartials(*trustee, form)
+	if err != nil {
+		handleCeremonyError(c, "Failed to decrypt: ", err)
+		return
+	}
+
+	msg := "Partial decryption accepted, waiting for more trustees"
+	if result != nil {
+		msg = "Successfully decrypt and tally vote"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    msg,
+		"data":   result,
+	})
+}
+
+// trusteeFromToken 以 X-Trustee-Token 取得受託人，失敗時寫入錯誤回應
+func trusteeFromToken(c *gin.Context) (*model.Trustee, bool) {
+	token := c.GetHeader("X-Trustee-Token")
+	if token == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status": -1,
+			"msg":    "X-Trustee-Token header is required",
+			"data":   nil,
+		})
+		return nil, false
+	}
+
+	trustee, err := service.NewTrusteeService().TrusteeByToken(token)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status": -1,
+			"msg":    "Invalid trustee token",
+			"data":   nil,
+		})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to find trustee: " + err.Error(),
+			"data":   nil,
+		})
+		return nil, false
+	}
+
+	return trustee, true
+}
+
+// handleCeremonyError 步驟不符合進度時回傳 409，其餘為 500
+func handleCeremonyError(c *gin.Context, prefix string, err error) {
+	var ceremonyErr *service.CeremonyError
+	if errors.As(err, &ceremonyErr) {
+		c.JSON(http.StatusConflict, gin.H{
+			"status": -1,
+			"msg":    prefix + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{
+		"status": -1,
+		"msg":    prefix + err.Error(),
+		"data":   nil,
+	})
+}
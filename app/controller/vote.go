@@ -3,7 +3,9 @@ package controller
 import (
 	"encoding/json"
 	"io"
+	"errors"
 	"net/http"
+	"time"
 
 	// "strconv"
 	"vote/app/database"
@@ -237,6 +239,14 @@ func (v VoteController) UpdateVote(c *gin.Context) {
 
 	// 檢查用戶是否是管理員
 	vote, updateErr := service.NewVoteService().UpdateVote(voteId, form)
+	if errors.Is(updateErr, service.ErrAllowRevoteLocked) {
+		c.JSON(http.StatusConflict, gin.H{
+			"status": -1,
+			"msg":    updateErr.Error(),
+			"data":   nil,
+		})
+		return
+	}
 	if updateErr != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"status": -1,
@@ -326,3 +336,500 @@ func (v VoteController) DeleteVote(c *gin.Context) {
 		"data":   deletedVotes,
 	})
 }
+
+// TallyVote 開票，計算並儲存投票結果
+// @Summary
+// @tags 投票
+// @Summary 開票
+// @Description 計算投票場次每個問題的得票數、得票率、空白票與未投票數，並儲存結果；寬限期結束前不能開票，開票後公布選票的 Merkle 樹根
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "投票ID"
+// @Success 200 {object} model.VoteResult "ok"
+// @Router /v1/vote/{id}/results [post]
+func (v VoteController) TallyVote(c *gin.Context) {
+	voteOne, ok := ownVote(c, "id")
+	if !ok {
+		return
+	}
+
+	userId := c.MustGet("id").(uint64)
+	result, err := service.NewTallyService().TallyVote(voteOne.Uuid, &userId)
+	var transitionErr *service.TransitionError
+	if errors.As(err, &transitionErr) {
+		c.JSON(http.StatusConflict, gin.H{
+			"status": -1,
+			"msg":    "Failed to tally vote: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to tally vote: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "Successfully tally vote",
+		"data":   result,
+	})
+}
+
+// GetVoteResults 取得投票結果
+// @Summary
+// @tags 投票
+// @Summary 取得投票結果
+// @Description 取得已儲存的投票結果
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "投票ID"
+// @Success 200 {object} model.VoteResult "ok"
+// @Router /v1/vote/{id}/results [get]
+func (v VoteController) GetVoteResults(c *gin.Context) {
+	voteOne, ok := ownVote(c, "id")
+	if !ok {
+		return
+	}
+
+	result, err := service.NewTallyService().GetVoteResult(voteOne.Uuid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status": -1,
+			"msg":    "Vote result not found: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "Successfully get vote result",
+		"data":   result,
+	})
+}
+
+// TransitionVote 變更投票狀態
+// @Summary
+// @tags 投票
+// @Summary 變更投票狀態
+// @Description 依投票生命週期變更狀態：draft、scheduled、open、closed、tallied、published、archived
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "投票ID"
+// @Param status body model.VoteTransitionCreate true "新狀態"
+// @Success 200 {object} model.Vote "ok"
+// @Router /v1/vote/{id}/status [post]
+func (v VoteController) TransitionVote(c *gin.Context) {
+	voteOne, ok := ownVote(c, "id")
+	if !ok {
+		return
+	}
+
+	var form model.VoteTransitionCreate
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid params: " + utils.ValidationErrorMessage(err),
+			"data":   nil,
+		})
+		return
+	}
+
+	userId := c.MustGet("id").(uint64)
+	vote, err := service.NewVoteService().TransitionVote(voteOne.Uuid, *form.Status, &userId)
+	var transitionErr *service.TransitionError
+	if errors.As(err, &transitionErr) {
+		c.JSON(http.StatusConflict, gin.H{
+			"status": -1,
+			"msg":    "Failed to change vote status: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to change vote status: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "Successfully change vote status",
+		"data":   vote,
+	})
+}
+
+// GetVoteTransitions 取得投票狀態轉換紀錄
+// @Summary
+// @tags 投票
+// @Summary 取得投票狀態轉換紀錄
+// @Description 取得投票每次狀態轉換的操作者與時間
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "投票ID"
+// @Success 200 {array} model.VoteTransition "ok"
+// @Router /v1/vote/{id}/transitions [get]
+func (v VoteController) GetVoteTransitions(c *gin.Context) {
+	voteOne, ok := ownVote(c, "id")
+	if !ok {
+		return
+	}
+
+	transitions, err := service.NewVoteService().GetVoteTransitions(voteOne.Uuid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to get vote transitions: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "Successfully get vote transitions",
+		"data":   transitions,
+	})
+}
+
+// StreamLiveResult 以 Server-Sent Events 推送投票的即時統計
+// @Summary
+// @tags 投票
+// @Summary 訂閱投票即時統計
+// @Description 以 SSE 推送投票率，投票開放即時結果且未加密時一併推送各候選人目前的票數；result 事件為統計，ping 事件維持連線
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Param id path string true "投票ID"
+// @Success 200 {object} model.LiveResult "ok"
+// @Router /v1/vote/{id}/live [get]
+func (v VoteController) StreamLiveResult(c *gin.Context) {
+	voteOne, ok := ownVote(c, "id")
+	if !ok {
+		return
+	}
+
+	updates, err := service.SubscribeLiveResult(c.Request.Context(), voteOne.Uuid)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrLiveUnavailable) {
+			status = http.StatusServiceUnavailable
+		}
+		c.JSON(status, gin.H{
+			"status": -1,
+			"msg":    "Failed to subscribe live results: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	// 避免反向代理暫存事件
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	ping := time.NewTicker(15 * time.Second)
+	defer ping.Stop()
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case result, ok := <-updates:
+			if !ok {
+				return false
+			}
+			c.SSEvent("result", result)
+		case <-ping.C:
+			c.SSEvent("ping", time.Now().Unix())
+		}
+		return true
+	})
+}
+
+// SetVoteReminders 設定投票結束前的提醒
+// @Summary
+// @tags 投票
+// @Summary 設定投票提醒
+// @Description 設定投票結束前寄給尚未投票者的提醒時間，例如 24h、1h，已執行的提醒會保留
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "投票ID"
+// @Param reminders body model.VoteReminderSet true "提醒時間"
+// @Success 200 {array} model.VoteReminder "ok"
+// @Router /v1/vote/{id}/reminders [put]
+func (v VoteController) SetVoteReminders(c *gin.Context) {
+	voteOne, ok := ownVote(c, "id")
+	if !ok {
+		return
+	}
+
+	var form model.VoteReminderSet
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid params: " + utils.ValidationErrorMessage(err),
+			"data":   nil,
+		})
+		return
+	}
+
+	reminders, err := service.NewReminderService().SetReminders(*voteOne, form.Offsets)
+	if errors.Is(err, service.ErrVoteClosed) {
+		c.JSON(http.StatusConflict, gin.H{
+			"status": -1,
+			"msg":    "Failed to set vote reminders: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Failed to set vote reminders: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "Successfully set vote reminders",
+		"data":   reminders,
+	})
+}
+
+// GetVoteReminders 取得投票提醒與執行紀錄
+// @Summary
+// @tags 投票
+// @Summary 取得投票提醒
+// @Description 取得投票提醒時間，以及已執行提醒的寄送與略過人數
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "投票ID"
+// @Success 200 {array} model.VoteReminder "ok"
+// @Router /v1/vote/{id}/reminders [get]
+func (v VoteController) GetVoteReminders(c *gin.Context) {
+	voteOne, ok := ownVote(c, "id")
+	if !ok {
+		return
+	}
+
+	reminders, err := service.NewReminderService().GetReminders(voteOne.Uuid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to get vote reminders: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "Successfully get vote reminders",
+		"data":   reminders,
+	})
+}
+
+// VerifyVoteLedger 驗證投票的選票雜湊鏈
+// @Summary
+// @tags 投票
+// @Summary 驗證選票雜湊鏈
+// @Description 由資料庫重新計算選票雜湊鏈，回傳最後一筆雜湊與第一筆被竄改或遺失的紀錄
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "投票ID"
+// @Success 200 {object} model.LedgerReport "ok"
+// @Router /v1/vote/{id}/ledger [get]
+func (v VoteController) VerifyVoteLedger(c *gin.Context) {
+	voteOne, ok := ownVote(c, "id")
+	if !ok {
+		return
+	}
+
+	report, err := service.NewLedgerService().Verify(voteOne.Uuid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to verify ledger: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "Successfully verify ledger",
+		"data":   report,
+	})
+}
+
+// SetupTrustees 將投票設為加密模式並建立受託人
+// @Summary
+// @tags 投票
+// @Summary 設定加密投票的受託人
+// @Description 僅限草稿投票，選票將以受託人共同產生的公鑰加密，開票需 threshold 位受託人部分解密。回傳的權杖只會出現一次
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "投票ID"
+// @Param trustees body model.TrusteeSetup true "門檻與受託人名稱"
+// @Success 200 {object} []model.TrusteeCreated "ok"
+// @Router /v1/vote/{id}/trustees [post]
+func (v VoteController) SetupTrustees(c *gin.Context) {
+	voteOne, ok := ownVote(c, "id")
+	if !ok {
+		return
+	}
+
+	var form model.TrusteeSetup
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid JSON format: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	trustees, err := service.NewTrusteeService().SetupTrustees(voteOne.Uuid, form)
+	if err != nil {
+		handleCeremonyError(c, "Failed to set up trustees: ", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "Successfully set up trustees",
+		"data":   trustees,
+	})
+}
+
+// GetTrustees 取得加密投票的受託人與金鑰產生進度
+// @Summary
+// @tags 投票
+// @Summary 取得受託人
+// @Description 回傳受託人的公開資料：傳輸公鑰、承諾與是否已部分解密
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "投票ID"
+// @Success 200 {object} []model.Trustee "ok"
+// @Router /v1/vote/{id}/trustees [get]
+func (v VoteController) GetTrustees(c *gin.Context) {
+	voteOne, ok := ownVote(c, "id")
+	if !ok {
+		return
+	}
+
+	trustees, err := service.NewTrusteeService().GetTrustees(voteOne.Uuid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to get trustees: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "Successfully get trustees",
+		"data":   trustees,
+	})
+}
+
+// PublishMerkleRoot 公布投票選票的 Merkle 樹根
+// @Summary
+// @tags 投票
+// @Summary 公布選票 Merkle 樹根
+// @Description 投票結束後以後端驗證金鑰簽署所有選票的 Merkle 樹根，設定 ETH_RPC_URL 時另外寫入區塊鏈；開票時會自動公布，失敗時可重新呼叫
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "投票ID"
+// @Success 200 {object} model.MerkleRoot "ok"
+// @Router /v1/vote/{id}/merkle [post]
+func (v VoteController) PublishMerkleRoot(c *gin.Context) {
+	voteOne, ok := ownVote(c, "id")
+	if !ok {
+		return
+	}
+
+	record, err := service.NewMerkleService().Publish(c.Request.Context(), voteOne.Uuid)
+	if errors.Is(err, service.ErrMerkleVoteOpen) {
+		c.JSON(http.StatusConflict, gin.H{
+			"status": -1,
+			"msg":    err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to publish merkle root: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "Successfully publish merkle root",
+		"data":   record,
+	})
+}
+
+// ownVote 從路徑參數 param 取得投票，並檢查目前使用者是否為管理員或投票建立者。
+// 檢查失敗時會直接寫入回應並回傳 false。
+func ownVote(c *gin.Context, param string) (*model.Vote, bool) {
+	voteId, err := uuid.Parse(c.Param(param))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid UUID format: " + err.Error(),
+			"data":   nil,
+		})
+		return nil, false
+	}
+
+	voteOne, err := service.NewVoteService().GetVote(voteId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status": -1,
+			"msg":    "Vote not found " + err.Error(),
+			"data":   nil,
+		})
+		return nil, false
+	}
+
+	userId := c.MustGet("id").(uint64)
+	isAdmin, err := database.CheckIfAdmin(userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to check user role: " + err.Error(),
+			"data":   nil,
+		})
+		return nil, false
+	}
+
+	if !isAdmin && voteOne.UserID != userId {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status": -1,
+			"msg":    "Permission denied",
+			"data":   nil,
+		})
+		return nil, false
+	}
+
+	return voteOne, true
+}
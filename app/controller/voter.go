@@ -2,9 +2,11 @@ package controller
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
+	"vote/app/enum"
 	"vote/app/middleware"
 	"vote/app/model"
 	"vote/app/service"
@@ -48,6 +50,17 @@ func (a VoterController) VoterLogin(c *gin.Context) {
 		return
 	}
 
+	// 檢查投票是否在開放時間內
+	vote, err := service.NewVoteService().GetVote(voteUUID)
+	if err != nil {
+		utils.HandleError(c, http.StatusNotFound, -1, "Vote not found", err)
+		return
+	}
+	if err := service.CheckVoteOpen(*vote, time.Now()); err != nil {
+		handleVoteWindowError(c, err)
+		return
+	}
+
 	// 定義用於並行處理的結果結構
 	type passwordResult struct {
 		password *model.Password
@@ -70,17 +83,9 @@ func (a VoterController) VoterLogin(c *gin.Context) {
 	votedCh := make(chan votedResult, 1)
 	tokenCh := make(chan tokenResult, 1)
 
-	// 密碼加密與驗證 - 單一goroutine處理整個流程
+	// 密碼驗證 - 以摘要查詢密碼
 	go func() {
-		// 密碼加密
-		passwordEncrypt, err := (&utils.Password{}).Encrypt(form.Password)
-		if err != nil {
-			passwordCh <- passwordResult{nil, fmt.Errorf("failed to encrypt password: %w", err)}
-			return
-		}
-
-		// 密碼檢查
-		password, err := service.NewPasswordService().SelectOnePassword(voteUUID, passwordEncrypt)
+		password, err := service.NewPasswordService().SelectOnePassword(voteUUID, form.Password)
 		if err != nil {
 			passwordCh <- passwordResult{nil, fmt.Errorf("failed to validate password: %w", err)}
 			return
@@ -123,14 +128,15 @@ func (a VoterController) VoterLogin(c *gin.Context) {
 		isVoted = res.isVoted
 	}
 
-	if isVoted {
+	// 開放重新投票時，已投票者仍可登入再次送出選票
+	if isVoted && !vote.AllowRevote {
 		utils.HandleError(c, http.StatusBadRequest, -1, "Voter has already voted", nil)
 		return
 	}
 
 	// 產生Token
 	go func() {
-		tokenString, refreshToken, err := middleware.GenVoterToken(voter, voteUUID, isVoted)
+		tokenString, refreshToken, err := middleware.GenVoterToken(voter, voteUUID, isVoted, time.Now())
 		tokenCh <- tokenResult{tokenString, refreshToken, err}
 	}()
 
@@ -198,8 +204,8 @@ func (a VoterController) CheckAuth(c *gin.Context) {
 			return
 		}
 
-		// 重新產生token
-		tokenString, _, err := middleware.GenVoterToken(claims.ID, claims.VoteID, hasVoted)
+		// 重新產生token，保留原本的登入時間
+		tokenString, _, err := middleware.GenVoterToken(claims.ID, claims.VoteID, hasVoted, claims.LoginAt())
 		if err != nil {
 			resultCh <- authResult{hasVoted, "", fmt.Errorf("failed to generate token: %w", err)}
 			return
@@ -284,8 +290,14 @@ func (a VoterController) CheckIsVoted(c *gin.Context) {
 	resultCh := make(chan checkResult, 1)
 
 	go func() {
+		vote, err := service.NewVoteService().GetVote(claims.VoteID)
+		if err != nil {
+			resultCh <- checkResult{false, err}
+			return
+		}
 		hasVoted, err := service.NewBallotService().CheckIfVoterHasVoted(claims.ID)
-		resultCh <- checkResult{hasVoted, err}
+		// 開放重新投票時，已投票者仍可再次送出選票
+		resultCh <- checkResult{hasVoted && !vote.AllowRevote, err}
 	}()
 
 	// 處理結果並添加超時控制
@@ -310,3 +322,13 @@ func (a VoterController) CheckIsVoted(c *gin.Context) {
 		})
 	}
 }
+
+// handleVoteWindowError 回應投票尚未開始或已經結束的錯誤
+func handleVoteWindowError(c *gin.Context, err error) {
+	code := enum.VoteClosed
+	if errors.Is(err, service.ErrVoteNotOpen) {
+		code = enum.VoteNotOpen
+	}
+
+	utils.HandleError(c, http.StatusForbidden, int(code), "Vote is not open", err)
+}
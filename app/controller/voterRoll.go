@@ -0,0 +1,241 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"vote/app/model"
+	"vote/app/service"
+	"vote/app/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type VoterRollController struct {
+}
+
+func NewVoterRollController() VoterRollController {
+	return VoterRollController{}
+}
+
+// CreateVoters 建立投票者名冊並發放密碼
+// @Summary
+// @tags 投票者名冊
+// @Summary 建立投票者名冊
+// @Description 匯入具名投票者，並為每位投票者發放一組密碼
+// @Accept json
+// @Produce json
+// @Param vote_id path string true "投票ID"
+// @Param json body model.VoterRollCreate true "json"
+// @Success 200 {array} model.Voter "ok"
+// @Router /voter-roll/{vote_id} [post]
+func (v VoterRollController) CreateVoters(c *gin.Context) {
+	var form model.VoterRollCreate
+	if err := c.ShouldBindJSON(&form); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid request: " + utils.ValidationErrorMessage(err),
+			"data":   nil,
+		})
+		return
+	}
+
+	vote, ok := ownVote(c, "vote_id")
+	if !ok {
+		return
+	}
+
+	voters, err := service.NewVoterRollService().CreateVoters(vote.Uuid, form)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Failed to create voters: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "successfully create voters",
+		"data":   voters,
+	})
+}
+
+// GetVoters 取得投票者名冊與寄送狀態
+// @Summary
+// @tags 投票者名冊
+// @Summary 取得投票者名冊
+// @Description 取得投票者名冊與每位投票者的密碼寄送狀態
+// @Produce json
+// @Param vote_id path string true "投票ID"
+// @Success 200 {array} model.Voter "ok"
+// @Router /voter-roll/{vote_id} [get]
+func (v VoterRollController) GetVoters(c *gin.Context) {
+	vote, ok := ownVote(c, "vote_id")
+	if !ok {
+		return
+	}
+
+	voters, err := service.NewVoterRollService().GetVoters(vote.Uuid)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to select voters: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "successfully select voters",
+		"data":   voters,
+	})
+}
+
+// SendCredentials 寄送密碼給名冊中的投票者
+// @Summary
+// @tags 投票者名冊
+// @Summary 寄送密碼
+// @Description 將密碼郵件排入佇列寄給尚未寄送成功的投票者，寄送結果可由名冊或郵件進度查詢
+// @Produce json
+// @Param vote_id path string true "投票ID"
+// @Success 202 {object} map[string]int "ok"
+// @Router /voter-roll/{vote_id}/send [post]
+func (v VoterRollController) SendCredentials(c *gin.Context) {
+	vote, ok := ownVote(c, "vote_id")
+	if !ok {
+		return
+	}
+
+	queued, err := service.NewVoterRollService().SendCredentials(*vote)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to send credentials: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"status": 0,
+		"msg":    "credentials are queued",
+		"data": gin.H{
+			"queued": queued,
+		},
+	})
+}
+
+// ResendCredential 重新寄送密碼給單一投票者
+// @Summary
+// @tags 投票者名冊
+// @Summary 重新寄送密碼
+// @Description 將密碼郵件排入佇列重新寄給單一投票者，已在佇列中時不重複排入；寄送結果可由名冊查詢
+// @Produce json
+// @Param vote_id path string true "投票ID"
+// @Param voter_id path string true "投票者ID"
+// @Success 202 {object} model.Voter "ok"
+// @Router /voter-roll/{vote_id}/voters/{voter_id}/resend [post]
+func (v VoterRollController) ResendCredential(c *gin.Context) {
+	voterId, err := strconv.ParseUint(c.Param("voter_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid voter ID",
+			"data":   nil,
+		})
+		return
+	}
+
+	vote, ok := ownVote(c, "vote_id")
+	if !ok {
+		return
+	}
+
+	voter, queued, err := service.NewVoterRollService().ResendCredential(*vote, voterId)
+	if voter == nil {
+		c.JSON(http.StatusNotFound, gin.H{
+			"status": -1,
+			"msg":    "Failed to select voter: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status": -1,
+			"msg":    "Failed to send credential: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	msg := "credential is queued"
+	if queued == 0 {
+		msg = "credential is already queued"
+	}
+	c.JSON(http.StatusAccepted, gin.H{
+		"status": 0,
+		"msg":    msg,
+		"data":   voter,
+	})
+}
+
+// ReissueCredential 撤銷投票者目前的密碼並發放新密碼
+// @Summary
+// @tags 投票者名冊
+// @Summary 重新發放密碼
+// @Description 撤銷遺失的密碼並為同一位投票者發放新密碼，已投票者不能重新發放；新密碼需再寄送
+// @Accept json
+// @Produce json
+// @Param vote_id path string true "投票ID"
+// @Param voter_id path string true "投票者ID"
+// @Param json body model.VoterReissue false "json"
+// @Success 200 {object} model.Voter "ok"
+// @Router /voter-roll/{vote_id}/voters/{voter_id}/reissue [post]
+func (v VoterRollController) ReissueCredential(c *gin.Context) {
+	voterId, err := strconv.ParseUint(c.Param("voter_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status": -1,
+			"msg":    "Invalid voter ID",
+			"data":   nil,
+		})
+		return
+	}
+
+	var form model.VoterReissue
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&form); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status": -1,
+				"msg":    "Invalid request: " + utils.ValidationErrorMessage(err),
+				"data":   nil,
+			})
+			return
+		}
+	}
+
+	vote, ok := ownVote(c, "vote_id")
+	if !ok {
+		return
+	}
+
+	userId := c.MustGet("id").(uint64)
+	voter, err := service.NewPasswordService().ReissueCredential(vote.Uuid, voterId, &userId, form.Reason)
+	if err != nil {
+		c.JSON(credentialErrorStatus(err), gin.H{
+			"status": -1,
+			"msg":    "Failed to reissue credential: " + err.Error(),
+			"data":   nil,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": 0,
+		"msg":    "successfully reissue credential",
+		"data":   voter,
+	})
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateVoteResultsTable00009, downCreateVoteResultsTable00009)
+}
+
+func upCreateVoteResultsTable00009(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	return database.SqlSession.Migrator().CreateTable(&model.VoteResult{})
+}
+
+func downCreateVoteResultsTable00009(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	return database.SqlSession.Migrator().DropTable(&model.VoteResult{})
+}
@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddRankedVotingColumns00010, downAddRankedVotingColumns00010)
+}
+
+func upAddRankedVotingColumns00010(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	migrator := database.SqlSession.Migrator()
+	if !migrator.HasColumn(&model.Question{}, "Method") {
+		if err := migrator.AddColumn(&model.Question{}, "Method"); err != nil {
+			return err
+		}
+	}
+	if !migrator.HasColumn(&model.BallotSelect{}, "Rank") {
+		if err := migrator.AddColumn(&model.BallotSelect{}, "Rank"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func downAddRankedVotingColumns00010(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	migrator := database.SqlSession.Migrator()
+	if err := migrator.DropColumn(&model.BallotSelect{}, "Rank"); err != nil {
+		return err
+	}
+
+	return migrator.DropColumn(&model.Question{}, "Method")
+}
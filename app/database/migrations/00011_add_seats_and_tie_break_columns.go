@@ -0,0 +1,41 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddSeatsAndTieBreakColumns00011, downAddSeatsAndTieBreakColumns00011)
+}
+
+func upAddSeatsAndTieBreakColumns00011(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	migrator := database.SqlSession.Migrator()
+	if !migrator.HasColumn(&model.Question{}, "Seats") {
+		if err := migrator.AddColumn(&model.Question{}, "Seats"); err != nil {
+			return err
+		}
+	}
+	if !migrator.HasColumn(&model.Vote{}, "TieBreak") {
+		if err := migrator.AddColumn(&model.Vote{}, "TieBreak"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func downAddSeatsAndTieBreakColumns00011(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	migrator := database.SqlSession.Migrator()
+	if err := migrator.DropColumn(&model.Vote{}, "TieBreak"); err != nil {
+		return err
+	}
+
+	return migrator.DropColumn(&model.Question{}, "Seats")
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddScoreVotingColumns00012, downAddScoreVotingColumns00012)
+}
+
+func upAddScoreVotingColumns00012(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	migrator := database.SqlSession.Migrator()
+	if !migrator.HasColumn(&model.Question{}, "MaxScore") {
+		if err := migrator.AddColumn(&model.Question{}, "MaxScore"); err != nil {
+			return err
+		}
+	}
+	if !migrator.HasColumn(&model.BallotSelect{}, "Value") {
+		if err := migrator.AddColumn(&model.BallotSelect{}, "Value"); err != nil {
+			return err
+		}
+		// 既有的勾選紀錄視為 1 票
+		if err := database.SqlSession.Exec("UPDATE ballot_selects SET value = 1 WHERE rank = 0").Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func downAddScoreVotingColumns00012(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	migrator := database.SqlSession.Migrator()
+	if err := migrator.DropColumn(&model.BallotSelect{}, "Value"); err != nil {
+		return err
+	}
+
+	return migrator.DropColumn(&model.Question{}, "MaxScore")
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddSelectionLimitColumns00013, downAddSelectionLimitColumns00013)
+}
+
+func upAddSelectionLimitColumns00013(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	migrator := database.SqlSession.Migrator()
+	for _, field := range []string{"MinSelections", "MaxSelections"} {
+		if !migrator.HasColumn(&model.Question{}, field) {
+			if err := migrator.AddColumn(&model.Question{}, field); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func downAddSelectionLimitColumns00013(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	migrator := database.SqlSession.Migrator()
+	if err := migrator.DropColumn(&model.Question{}, "MaxSelections"); err != nil {
+		return err
+	}
+
+	return migrator.DropColumn(&model.Question{}, "MinSelections")
+}
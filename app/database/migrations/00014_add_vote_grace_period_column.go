@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddVoteGracePeriodColumn00014, downAddVoteGracePeriodColumn00014)
+}
+
+func upAddVoteGracePeriodColumn00014(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	migrator := database.SqlSession.Migrator()
+	if !migrator.HasColumn(&model.Vote{}, "GracePeriod") {
+		return migrator.AddColumn(&model.Vote{}, "GracePeriod")
+	}
+
+	return nil
+}
+
+func downAddVoteGracePeriodColumn00014(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	return database.SqlSession.Migrator().DropColumn(&model.Vote{}, "GracePeriod")
+}
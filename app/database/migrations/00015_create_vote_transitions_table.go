@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateVoteTransitionsTable00015, downCreateVoteTransitionsTable00015)
+}
+
+func upCreateVoteTransitionsTable00015(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	if err := database.SqlSession.Migrator().CreateTable(&model.VoteTransition{}); err != nil {
+		return err
+	}
+
+	// 既有投票依時間與是否已開票推算狀態
+	return database.SqlSession.Exec(`
+		UPDATE votes SET status = CASE
+			WHEN EXISTS (SELECT 1 FROM vote_results WHERE vote_results.vote_id = votes.uuid) THEN ?
+			WHEN now() < start_time THEN ?
+			WHEN now() < end_time THEN ?
+			ELSE ?
+		END`,
+		enum.Tallied, enum.Scheduled, enum.Open, enum.Closed,
+	).Error
+}
+
+func downCreateVoteTransitionsTable00015(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	if err := database.SqlSession.Exec("UPDATE votes SET status = 0").Error; err != nil {
+		return err
+	}
+
+	return database.SqlSession.Migrator().DropTable(&model.VoteTransition{})
+}
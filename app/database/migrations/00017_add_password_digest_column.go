@@ -0,0 +1,77 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"vote/app/database"
+	"vote/app/model"
+	"vote/app/utils"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddPasswordDigestColumn00017, downAddPasswordDigestColumn00017)
+}
+
+func upAddPasswordDigestColumn00017(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	migrator := database.SqlSession.Migrator()
+	if migrator.HasColumn(&model.Password{}, "Digest") {
+		return nil
+	}
+
+	// 先建立可為空的欄位，回填後再加上限制
+	if err := database.SqlSession.Exec("ALTER TABLE passwords ADD COLUMN digest varchar(64)").Error; err != nil {
+		return err
+	}
+
+	var passwords []model.Password
+	err := database.SqlSession.Select([]string{"id", "vote_id", "password"}).Order("id ASC").Find(&passwords).Error
+	if err != nil {
+		return err
+	}
+
+	passwordUtil := &utils.Password{}
+	seen := make(map[string]struct{}, len(passwords))
+	for _, password := range passwords {
+		plain, err := passwordUtil.Decrypt(password.Password)
+		if err != nil {
+			return fmt.Errorf("password %d: %w", password.ID, err)
+		}
+		digest, err := passwordUtil.Digest(password.VoteID.String(), plain)
+		if err != nil {
+			return err
+		}
+
+		// 同一投票中重複的密碼無法區分投票者，保留第一筆並停用其餘的密碼
+		updates := map[string]any{"digest": digest}
+		key := password.VoteID.String() + digest
+		if _, ok := seen[key]; ok {
+			updates = map[string]any{"digest": fmt.Sprintf("duplicate:%d", password.ID), "status": false}
+		}
+		seen[key] = struct{}{}
+
+		err = database.SqlSession.Model(&model.Password{}).Where("id = ?", password.ID).Updates(updates).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := database.SqlSession.Exec("ALTER TABLE passwords ALTER COLUMN digest SET NOT NULL").Error; err != nil {
+		return err
+	}
+
+	return migrator.CreateIndex(&model.Password{}, "idx_passwords_vote_digest")
+}
+
+func downAddPasswordDigestColumn00017(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	migrator := database.SqlSession.Migrator()
+	if err := migrator.DropIndex(&model.Password{}, "idx_passwords_vote_digest"); err != nil {
+		return err
+	}
+
+	return migrator.DropColumn(&model.Password{}, "Digest")
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateMailJobsTable00019, downCreateMailJobsTable00019)
+}
+
+func upCreateMailJobsTable00019(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	return database.SqlSession.Migrator().CreateTable(&model.MailJob{})
+}
+
+func downCreateMailJobsTable00019(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	return database.SqlSession.Migrator().DropTable(&model.MailJob{})
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateVoteRemindersTable00020, downCreateVoteRemindersTable00020)
+}
+
+func upCreateVoteRemindersTable00020(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	return database.SqlSession.Migrator().CreateTable(&model.VoteReminder{})
+}
+
+func downCreateVoteRemindersTable00020(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	return database.SqlSession.Migrator().DropTable(&model.VoteReminder{})
+}
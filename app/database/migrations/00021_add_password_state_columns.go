@@ -0,0 +1,67 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddPasswordStateColumns00021, downAddPasswordStateColumns00021)
+}
+
+func upAddPasswordStateColumns00021(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	migrator := database.SqlSession.Migrator()
+	if !migrator.HasColumn(&model.Password{}, "State") {
+		if err := migrator.AddColumn(&model.Password{}, "State"); err != nil {
+			return err
+		}
+
+		// 依原本的狀態回填：已有選票者為已投票，重複而停用的密碼為已撤銷
+		statements := []string{
+			"UPDATE passwords SET state = 'activated' WHERE status = true",
+			"UPDATE passwords SET state = 'revoked' WHERE digest LIKE 'duplicate:%'",
+		}
+		// 新建立的資料庫中選票已不記錄密碼
+		if migrator.HasColumn("ballots", "password_id") {
+			statements = append(statements, "UPDATE passwords SET state = 'used', status = true WHERE EXISTS (SELECT 1 FROM ballots WHERE ballots.password_id = passwords.id)")
+		}
+		for _, statement := range statements {
+			if err := database.SqlSession.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		if err := migrator.CreateIndex(&model.Password{}, "State"); err != nil {
+			return err
+		}
+	}
+	if !migrator.HasColumn(&model.Password{}, "ExpiresAt") {
+		if err := migrator.AddColumn(&model.Password{}, "ExpiresAt"); err != nil {
+			return err
+		}
+	}
+
+	return migrator.CreateTable(&model.CredentialAudit{})
+}
+
+func downAddPasswordStateColumns00021(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	migrator := database.SqlSession.Migrator()
+	if err := migrator.DropTable(&model.CredentialAudit{}); err != nil {
+		return err
+	}
+	if migrator.HasColumn(&model.Password{}, "ExpiresAt") {
+		if err := migrator.DropColumn(&model.Password{}, "ExpiresAt"); err != nil {
+			return err
+		}
+	}
+	if migrator.HasColumn(&model.Password{}, "State") {
+		return migrator.DropColumn(&model.Password{}, "State")
+	}
+
+	return nil
+}
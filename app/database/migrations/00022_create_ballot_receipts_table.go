@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateBallotReceiptsTable00022, downCreateBallotReceiptsTable00022)
+}
+
+func upCreateBallotReceiptsTable00022(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	migrator := database.SqlSession.Migrator()
+	if err := migrator.CreateTable(&model.BallotReceipt{}); err != nil {
+		return err
+	}
+	if migrator.HasColumn(&model.Ballot{}, "ReceiptID") {
+		return nil
+	}
+	if err := migrator.AddColumn(&model.Ballot{}, "ReceiptID"); err != nil {
+		return err
+	}
+
+	return migrator.CreateIndex(&model.Ballot{}, "ReceiptID")
+}
+
+func downCreateBallotReceiptsTable00022(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	migrator := database.SqlSession.Migrator()
+	if migrator.HasColumn(&model.Ballot{}, "ReceiptID") {
+		if err := migrator.DropColumn(&model.Ballot{}, "ReceiptID"); err != nil {
+			return err
+		}
+	}
+
+	return migrator.DropTable(&model.BallotReceipt{})
+}
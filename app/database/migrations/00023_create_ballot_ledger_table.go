@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+	"vote/app/service"
+
+	"github.com/pressly/goose/v3"
+	"gorm.io/gorm"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateBallotLedgerTable00023, downCreateBallotLedgerTable00023)
+}
+
+func upCreateBallotLedgerTable00023(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	if err := database.SqlSession.Migrator().CreateTable(&model.LedgerEntry{}); err != nil {
+		return err
+	}
+
+	// 既有的選票依 ID 順序寫入雜湊鏈
+	return database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		_, err := service.NewLedgerService().Backfill(tx)
+		return err
+	})
+}
+
+func downCreateBallotLedgerTable00023(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	return database.SqlSession.Migrator().DropTable(&model.LedgerEntry{})
+}
@@ -0,0 +1,24 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateVoteMerkleRootsTable00024, downCreateVoteMerkleRootsTable00024)
+}
+
+func upCreateVoteMerkleRootsTable00024(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	return database.SqlSession.Migrator().CreateTable(&model.MerkleRoot{})
+}
+
+func downCreateVoteMerkleRootsTable00024(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	return database.SqlSession.Migrator().DropTable(&model.MerkleRoot{})
+}
@@ -0,0 +1,66 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+	"gorm.io/gorm"
+)
+
+func init() {
+	goose.AddMigrationContext(upCreateParticipationsTable00025, downCreateParticipationsTable00025)
+}
+
+func upCreateParticipationsTable00025(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	return database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		migrator := tx.Migrator()
+		if err := migrator.CreateTable(&model.Participation{}); err != nil {
+			return err
+		}
+
+		if migrator.HasColumn("ballots", "password_id") {
+			// 由選票回填已投票紀錄後移除選票與密碼的關聯
+			err := tx.Exec(`INSERT INTO participations (password_id, vote_id, voted_at)
+				SELECT ballots.password_id, questions.vote_id, date_trunc('hour', MIN(ballots.created_at))
+				FROM ballots JOIN questions ON questions.id = ballots.question_id
+				GROUP BY ballots.password_id, questions.vote_id
+				ON CONFLICT (password_id) DO NOTHING`).Error
+			if err != nil {
+				return err
+			}
+			if err := migrator.DropColumn("ballots", "password_id"); err != nil {
+				return err
+			}
+		}
+
+		// 時間只保留到小時，投票紀錄的順序改由已投票紀錄取代
+		statements := []string{
+			"UPDATE ballots SET created_at = date_trunc('hour', created_at), updated_at = date_trunc('hour', updated_at)",
+			"DELETE FROM credential_audits WHERE action = 'use'",
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func downCreateParticipationsTable00025(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	// 選票與密碼的對應已移除，無法還原，只恢復欄位
+	migrator := database.SqlSession.Migrator()
+	if !migrator.HasColumn("ballots", "password_id") {
+		if err := database.SqlSession.Exec("ALTER TABLE ballots ADD COLUMN password_id bigint").Error; err != nil {
+			return err
+		}
+	}
+
+	return migrator.DropTable(&model.Participation{})
+}
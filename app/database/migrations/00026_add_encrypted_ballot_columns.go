@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddEncryptedBallotColumns00026, downAddEncryptedBallotColumns00026)
+}
+
+// encryptedBallotColumns 加密投票新增的欄位
+var encryptedBallotColumns = []struct {
+	model any
+	field string
+}{
+	{&model.Vote{}, "Encrypted"},
+	{&model.Vote{}, "Threshold"},
+	{&model.Vote{}, "PublicKey"},
+	{&model.Ballot{}, "Proof"},
+	{&model.BallotSelect{}, "Ciphertext"},
+	{&model.BallotSelect{}, "Proof"},
+}
+
+func upAddEncryptedBallotColumns00026(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	migrator := database.SqlSession.Migrator()
+	for _, column := range encryptedBallotColumns {
+		if migrator.HasColumn(column.model, column.field) {
+			continue
+		}
+		if err := migrator.AddColumn(column.model, column.field); err != nil {
+			return err
+		}
+	}
+
+	return migrator.CreateTable(&model.Trustee{}, &model.TrusteeShare{}, &model.PartialDecryption{})
+}
+
+func downAddEncryptedBallotColumns00026(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	migrator := database.SqlSession.Migrator()
+	if err := migrator.DropTable(&model.PartialDecryption{}, &model.TrusteeShare{}, &model.Trustee{}); err != nil {
+		return err
+	}
+	for _, column := range encryptedBallotColumns {
+		if !migrator.HasColumn(column.model, column.field) {
+			continue
+		}
+		if err := migrator.DropColumn(column.model, column.field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
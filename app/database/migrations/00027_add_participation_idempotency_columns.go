@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddParticipationIdempotencyColumns00027, downAddParticipationIdempotencyColumns00027)
+}
+
+// participationIdempotencyColumns 送出選票的冪等鍵欄位
+var participationIdempotencyColumns = []string{"IdempotencyDigest", "SealedReceipt"}
+
+func upAddParticipationIdempotencyColumns00027(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	migrator := database.SqlSession.Migrator()
+	for _, field := range participationIdempotencyColumns {
+		if migrator.HasColumn(&model.Participation{}, field) {
+			continue
+		}
+		if err := migrator.AddColumn(&model.Participation{}, field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func downAddParticipationIdempotencyColumns00027(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	migrator := database.SqlSession.Migrator()
+	for _, field := range participationIdempotencyColumns {
+		if !migrator.HasColumn(&model.Participation{}, field) {
+			continue
+		}
+		if err := migrator.DropColumn(&model.Participation{}, field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
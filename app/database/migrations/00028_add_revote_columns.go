@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddRevoteColumns00028, downAddRevoteColumns00028)
+}
+
+// revoteColumns 重新投票新增的欄位
+var revoteColumns = []struct {
+	model any
+	field string
+}{
+	{&model.Vote{}, "AllowRevote"},
+	{&model.BallotReceipt{}, "SupersedeDigest"},
+	{&model.BallotReceipt{}, "Superseded"},
+}
+
+func upAddRevoteColumns00028(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	migrator := database.SqlSession.Migrator()
+	for _, column := range revoteColumns {
+		if migrator.HasColumn(column.model, column.field) {
+			continue
+		}
+		if err := migrator.AddColumn(column.model, column.field); err != nil {
+			return err
+		}
+	}
+	if migrator.HasIndex(&model.BallotReceipt{}, "SupersedeDigest") {
+		return nil
+	}
+
+	return migrator.CreateIndex(&model.BallotReceipt{}, "SupersedeDigest")
+}
+
+func downAddRevoteColumns00028(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	migrator := database.SqlSession.Migrator()
+	for _, column := range revoteColumns {
+		if !migrator.HasColumn(column.model, column.field) {
+			continue
+		}
+		if err := migrator.DropColumn(column.model, column.field); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
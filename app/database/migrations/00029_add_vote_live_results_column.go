@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddVoteLiveResultsColumn00029, downAddVoteLiveResultsColumn00029)
+}
+
+func upAddVoteLiveResultsColumn00029(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	migrator := database.SqlSession.Migrator()
+	if !migrator.HasColumn(&model.Vote{}, "LiveResults") {
+		return migrator.AddColumn(&model.Vote{}, "LiveResults")
+	}
+
+	return nil
+}
+
+func downAddVoteLiveResultsColumn00029(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	return database.SqlSession.Migrator().DropColumn(&model.Vote{}, "LiveResults")
+}
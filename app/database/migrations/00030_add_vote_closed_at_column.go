@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddVoteClosedAtColumn00030, downAddVoteClosedAtColumn00030)
+}
+
+func upAddVoteClosedAtColumn00030(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	migrator := database.SqlSession.Migrator()
+	if !migrator.HasColumn(&model.Vote{}, "ClosedAt") {
+		if err := migrator.AddColumn(&model.Vote{}, "ClosedAt"); err != nil {
+			return err
+		}
+	}
+
+	// 已結束的投票以轉換紀錄補上結束時間
+	return database.SqlSession.Exec(`UPDATE votes SET closed_at = (
+		SELECT MAX(vote_transitions.created_at) FROM vote_transitions
+		WHERE vote_transitions.vote_id = votes.uuid AND vote_transitions.to_status = ?
+	) WHERE closed_at IS NULL`, enum.Closed).Error
+}
+
+func downAddVoteClosedAtColumn00030(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	return database.SqlSession.Migrator().DropColumn(&model.Vote{}, "ClosedAt")
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upCoarsenReceiptAndLedgerTimes00031, downCoarsenReceiptAndLedgerTimes00031)
+}
+
+func upCoarsenReceiptAndLedgerTimes00031(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	// 回條與雜湊鏈紀錄對應到選票，時間與選票相同只保留到小時
+	if err := database.SqlSession.Exec("UPDATE ballot_receipts SET created_at = date_trunc('hour', created_at)").Error; err != nil {
+		return err
+	}
+
+	return database.SqlSession.Exec("UPDATE ballot_ledger SET created_at = date_trunc('hour', created_at)").Error
+}
+
+func downCoarsenReceiptAndLedgerTimes00031(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	// 原本的時間已無法還原
+	return nil
+}
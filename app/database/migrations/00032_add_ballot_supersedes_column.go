@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upAddBallotSupersedesColumn00032, downAddBallotSupersedesColumn00032)
+}
+
+func upAddBallotSupersedesColumn00032(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is applied.
+	migrator := database.SqlSession.Migrator()
+	if migrator.HasColumn(&model.Ballot{}, "SupersedesID") {
+		return nil
+	}
+	if err := migrator.AddColumn(&model.Ballot{}, "SupersedesID"); err != nil {
+		return err
+	}
+
+	return migrator.CreateIndex(&model.Ballot{}, "SupersedesID")
+}
+
+func downAddBallotSupersedesColumn00032(ctx context.Context, tx *sql.Tx) error {
+	// This code is executed when the migration is rolled back.
+	return database.SqlSession.Migrator().DropColumn(&model.Ballot{}, "SupersedesID")
+}
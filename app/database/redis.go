@@ -0,0 +1,25 @@
+package database
+
+import (
+	"os"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var RedisClient *redis.Client
+
+// InitializeRedis 建立 Redis 連線，位址由 REDIS_ADDR 設定，預設 127.0.0.1:6379
+func InitializeRedis() *redis.Client {
+	addr := os.Getenv("REDIS_ADDR")
+	if addr == "" {
+		addr = "127.0.0.1:6379"
+	}
+
+	RedisClient = redis.NewClient(&redis.Options{
+		Network: "tcp",
+		Addr:    addr,
+		DB:      0,
+	})
+
+	return RedisClient
+}
@@ -0,0 +1,80 @@
+package elgamal
+
+import (
+	"errors"
+	"io"
+	"math/big"
+)
+
+// ErrPlaintextRange 解密結果不在預期的範圍內
+var ErrPlaintextRange = errors.New("elgamal: plaintext out of range")
+
+// Ciphertext 指數 ElGamal 密文：A = G^r，B = G^m · H^r，H 為公鑰
+type Ciphertext struct {
+	A *Int `json:"a"`
+	B *Int `json:"b"`
+}
+
+// Encrypt 以公鑰加密整數 m，同時回傳隨機數 r 供產生證明
+func Encrypt(publicKey *big.Int, m int64, random io.Reader) (*Ciphertext, *big.Int, error) {
+	r, err := RandomScalar(random)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return EncryptWith(publicKey, m, r), r, nil
+}
+
+// EncryptWith 以指定的隨機數加密
+func EncryptWith(publicKey *big.Int, m int64, r *big.Int) *Ciphertext {
+	return &Ciphertext{
+		A: NewInt(exp(G, r)),
+		B: NewInt(mul(gPow(m), exp(publicKey, r))),
+	}
+}
+
+// Identity 加密 0 且隨機數為 0 的密文，作為相乘的起點
+func Identity() *Ciphertext {
+	return &Ciphertext{A: NewInt(big.NewInt(1)), B: NewInt(big.NewInt(1))}
+}
+
+// Valid 檢查兩個分量都是子群中的元素
+func (c *Ciphertext) Valid() bool {
+	return c != nil && c.A != nil && c.B != nil && IsElement(c.A.Big()) && IsElement(c.B.Big())
+}
+
+// Mul 密文相乘，結果為明文相加的密文
+func (c *Ciphertext) Mul(other *Ciphertext) *Ciphertext {
+	return &Ciphertext{
+		A: NewInt(mul(c.A.Big(), other.A.Big())),
+		B: NewInt(mul(c.B.Big(), other.B.Big())),
+	}
+}
+
+// Sum 將多個密文相乘，結果為明文總和的密文
+func Sum(ciphertexts []*Ciphertext) *Ciphertext {
+	sum := Identity()
+	for _, ciphertext := range ciphertexts {
+		sum = sum.Mul(ciphertext)
+	}
+
+	return sum
+}
+
+// Decrypt 以完整私鑰解密，max 為明文可能的最大值
+func Decrypt(secret *big.Int, c *Ciphertext, max int64) (int64, error) {
+	return DiscreteLog(mul(c.B.Big(), inverse(exp(c.A.Big(), secret))), max)
+}
+
+// DiscreteLog 由 G^m 找出 0 到 max 之間的 m，票數不大時逐一比對即可
+func DiscreteLog(gm *big.Int, max int64) (int64, error) {
+	current := big.NewInt(1)
+	for m := int64(0); m <= max; m++ {
+		if current.Cmp(gm) == 0 {
+			return m, nil
+		}
+		current = mul(current, G)
+	}
+
+	return 0, ErrPlaintextRange
+}
@@ -0,0 +1,157 @@
+// Package elgamal 以指數 ElGamal 加密選票，提供同態相加、零知識證明與門檻解密。
+// 運算在 RFC 3526 第 14 組 2048 位元安全質數的 Q 階子群中進行，只使用標準函式庫。
+package elgamal
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+)
+
+var (
+	// P RFC 3526 第 14 組的安全質數，P = 2Q + 1
+	P = mustHex("FFFFFFFFFFFFFFFFC90FDAA22168C234C4C6628B80DC1CD1" +
+		"29024E088A67CC74020BBEA63B139B22514A08798E3404DD" +
+		"EF9519B3CD3A431B302B0A6DF25F14374FE1356D6D51C245" +
+		"E485B576625E7EC6F44C42E9A637ED6B0BFF5CB6F406B7ED" +
+		"EE386BFB5A899FA5AE9F24117C4B1FE649286651ECE45B3D" +
+		"C2007CB8A163BF0598DA48361C55D39A69163FA8FD24CF5F" +
+		"83655D23DCA3AD961C62F356208552BB9ED529077096966D" +
+		"670C354E4ABC9804F1746C08CA18217C32905E462E36CE3B" +
+		"E39E772C180E86039B2783A2EC07A28FB5C55DF06F4C52C9" +
+		"DE2BCBF6955817183995497CEA956AE515D2261898FA0510" +
+		"15728E5A8AACAA68FFFFFFFFFFFFFFFF")
+	// Q 子群的階
+	Q = new(big.Int).Rsh(new(big.Int).Sub(P, big.NewInt(1)), 1)
+	// G 子群的生成元，P ≡ 7 (mod 8) 時 2 為二次剩餘
+	G = big.NewInt(2)
+)
+
+// elementSize 群元素序列化的位元組數
+var elementSize = (P.BitLen() + 7) / 8
+
+func mustHex(s string) *big.Int {
+	n, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("elgamal: invalid constant")
+	}
+
+	return n
+}
+
+// Int 以十六進位字串序列化的大整數，避免 JSON 數字失去精度
+type Int big.Int
+
+// NewInt 將 big.Int 轉為 Int
+func NewInt(n *big.Int) *Int {
+	return (*Int)(n)
+}
+
+// Big 轉回 big.Int，回傳值與 i 共用內容
+func (i *Int) Big() *big.Int {
+	return (*big.Int)(i)
+}
+
+func (i *Int) String() string {
+	return i.Big().Text(16)
+}
+
+func (i *Int) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.Big().Text(16))
+}
+
+func (i *Int) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("big integer must be a hex string: %w", err)
+	}
+	if _, ok := i.Big().SetString(text, 16); !ok {
+		return fmt.Errorf("invalid hex integer %q", text)
+	}
+
+	return nil
+}
+
+// IsElement 檢查是否為子群中的元素（不含 1 以外的特殊值檢查）
+func IsElement(n *big.Int) bool {
+	if n == nil || n.Sign() <= 0 || n.Cmp(P) >= 0 {
+		return false
+	}
+
+	return exp(n, Q).Cmp(one) == 0
+}
+
+// isScalar 檢查是否為 0 到 Q-1 之間的指數
+func isScalar(n *big.Int) bool {
+	return n != nil && n.Sign() >= 0 && n.Cmp(Q) < 0
+}
+
+// RandomScalar 產生 1 到 Q-1 之間的隨機指數
+func RandomScalar(random io.Reader) (*big.Int, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+	n, err := rand.Int(random, new(big.Int).Sub(Q, one))
+	if err != nil {
+		return nil, err
+	}
+
+	return n.Add(n, one), nil
+}
+
+// GenerateKey 產生金鑰，回傳私鑰 x 與公鑰 G^x
+func GenerateKey(random io.Reader) (*big.Int, *big.Int, error) {
+	secret, err := RandomScalar(random)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return secret, exp(G, secret), nil
+}
+
+var one = big.NewInt(1)
+
+func exp(base *big.Int, exponent *big.Int) *big.Int {
+	return new(big.Int).Exp(base, exponent, P)
+}
+
+func mul(a *big.Int, b *big.Int) *big.Int {
+	n := new(big.Int).Mul(a, b)
+
+	return n.Mod(n, P)
+}
+
+func inverse(a *big.Int) *big.Int {
+	return new(big.Int).ModInverse(a, P)
+}
+
+// gPow 計算 G^m，m 可為負數
+func gPow(m int64) *big.Int {
+	exponent := big.NewInt(m)
+
+	return exp(G, exponent.Mod(exponent, Q))
+}
+
+// hashToScalar 以 sha256 將各段資料雜湊成指數，每段資料前加上長度避免歧義
+func hashToScalar(parts ...[]byte) *big.Int {
+	hash := sha256.New()
+	var length [8]byte
+	for _, part := range parts {
+		binary.BigEndian.PutUint64(length[:], uint64(len(part)))
+		hash.Write(length[:])
+		hash.Write(part)
+	}
+
+	n := new(big.Int).SetBytes(hash.Sum(nil))
+
+	return n.Mod(n, Q)
+}
+
+// elementBytes 將群元素轉為固定長度的位元組
+func elementBytes(n *big.Int) []byte {
+	return n.FillBytes(make([]byte, elementSize))
+}
@@ -0,0 +1,159 @@
+package elgamal
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"strconv"
+)
+
+// RangeProof 析取 Chaum-Pedersen 證明：密文的明文是允許值其中之一，但不透露是哪一個。
+// 只保存每個允許值的挑戰值與回應，驗證時重新計算承諾。
+type RangeProof struct {
+	Challenges []*Int `json:"challenges"`
+	Responses  []*Int `json:"responses"`
+}
+
+// ProveRange 產生密文 ct（明文 m、隨機數 r）屬於 values 的證明，context 綁定證明的用途
+func ProveRange(publicKey *big.Int, ct *Ciphertext, r *big.Int, m int64, values []int64, context []byte, random io.Reader) (*RangeProof, error) {
+	actual := -1
+	for j, value := range values {
+		if value == m {
+			actual = j
+			break
+		}
+	}
+	if actual < 0 {
+		return nil, errors.New("elgamal: plaintext is not an allowed value")
+	}
+
+	commitmentsA := make([]*big.Int, len(values))
+	commitmentsB := make([]*big.Int, len(values))
+	proof := &RangeProof{
+		Challenges: make([]*Int, len(values)),
+		Responses:  make([]*Int, len(values)),
+	}
+
+	// 其他允許值以隨機挑戰值與回應模擬
+	simulated := new(big.Int)
+	for j, value := range values {
+		if j == actual {
+			continue
+		}
+		challenge, err := RandomScalar(random)
+		if err != nil {
+			return nil, err
+		}
+		response, err := RandomScalar(random)
+		if err != nil {
+			return nil, err
+		}
+		commitmentsA[j], commitmentsB[j] = rangeCommitment(publicKey, ct, value, challenge, response)
+		proof.Challenges[j] = NewInt(challenge)
+		proof.Responses[j] = NewInt(response)
+		simulated.Add(simulated, challenge)
+	}
+
+	w, err := RandomScalar(random)
+	if err != nil {
+		return nil, err
+	}
+	commitmentsA[actual] = exp(G, w)
+	commitmentsB[actual] = exp(publicKey, w)
+
+	challenge := rangeChallenge(publicKey, ct, values, context, commitmentsA, commitmentsB)
+	challenge.Sub(challenge, simulated).Mod(challenge, Q)
+	response := new(big.Int).Mul(challenge, r)
+	response.Add(response, w).Mod(response, Q)
+	proof.Challenges[actual] = NewInt(challenge)
+	proof.Responses[actual] = NewInt(response)
+
+	return proof, nil
+}
+
+// VerifyRange 驗證密文的明文屬於 values
+func VerifyRange(publicKey *big.Int, ct *Ciphertext, values []int64, context []byte, proof *RangeProof) bool {
+	if proof == nil || len(values) == 0 || len(proof.Challenges) != len(values) || len(proof.Responses) != len(values) {
+		return false
+	}
+	if !ct.Valid() {
+		return false
+	}
+
+	commitmentsA := make([]*big.Int, len(values))
+	commitmentsB := make([]*big.Int, len(values))
+	sum := new(big.Int)
+	for j, value := range values {
+		if proof.Challenges[j] == nil || proof.Responses[j] == nil {
+			return false
+		}
+		challenge, response := proof.Challenges[j].Big(), proof.Responses[j].Big()
+		if !isScalar(challenge) || !isScalar(response) {
+			return false
+		}
+		commitmentsA[j], commitmentsB[j] = rangeCommitment(publicKey, ct, value, challenge, response)
+		sum.Add(sum, challenge)
+	}
+	sum.Mod(sum, Q)
+
+	return sum.Cmp(rangeChallenge(publicKey, ct, values, context, commitmentsA, commitmentsB)) == 0
+}
+
+// rangeCommitment 由挑戰值與回應反推承諾：G^s · A^-c 與 H^s · (B / G^v)^-c
+func rangeCommitment(publicKey *big.Int, ct *Ciphertext, value int64, challenge *big.Int, response *big.Int) (*big.Int, *big.Int) {
+	negated := new(big.Int).Sub(Q, challenge)
+	a := mul(exp(G, response), exp(ct.A.Big(), negated))
+	b := mul(exp(publicKey, response), exp(mul(ct.B.Big(), gPow(-value)), negated))
+
+	return a, b
+}
+
+func rangeChallenge(publicKey *big.Int, ct *Ciphertext, values []int64, context []byte, commitmentsA []*big.Int, commitmentsB []*big.Int) *big.Int {
+	parts := [][]byte{[]byte("range"), context, elementBytes(publicKey), elementBytes(ct.A.Big()), elementBytes(ct.B.Big())}
+	for j, value := range values {
+		parts = append(parts, []byte(strconv.FormatInt(value, 10)), elementBytes(commitmentsA[j]), elementBytes(commitmentsB[j]))
+	}
+
+	return hashToScalar(parts...)
+}
+
+// EqualityProof Chaum-Pedersen 證明：log_G(Y) = log_A(D)，用於證明部分解密使用的是對應的金鑰分片
+type EqualityProof struct {
+	Challenge *Int `json:"challenge"`
+	Response  *Int `json:"response"`
+}
+
+// ProveEquality 證明 Y = G^x 與 D = A^x 使用相同的 x
+func ProveEquality(x *big.Int, y *big.Int, a *big.Int, d *big.Int, context []byte, random io.Reader) (*EqualityProof, error) {
+	w, err := RandomScalar(random)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge := equalityChallenge(y, a, d, context, exp(G, w), exp(a, w))
+	response := new(big.Int).Mul(challenge, x)
+	response.Add(response, w).Mod(response, Q)
+
+	return &EqualityProof{Challenge: NewInt(challenge), Response: NewInt(response)}, nil
+}
+
+// VerifyEquality 驗證 log_G(Y) = log_A(D)
+func VerifyEquality(y *big.Int, a *big.Int, d *big.Int, context []byte, proof *EqualityProof) bool {
+	if proof == nil || proof.Challenge == nil || proof.Response == nil {
+		return false
+	}
+	challenge, response := proof.Challenge.Big(), proof.Response.Big()
+	if !isScalar(challenge) || !isScalar(response) || !IsElement(y) || !IsElement(a) || !IsElement(d) {
+		return false
+	}
+
+	negated := new(big.Int).Sub(Q, challenge)
+	t1 := mul(exp(G, response), exp(y, negated))
+	t2 := mul(exp(a, response), exp(d, negated))
+
+	return challenge.Cmp(equalityChallenge(y, a, d, context, t1, t2)) == 0
+}
+
+func equalityChallenge(y *big.Int, a *big.Int, d *big.Int, context []byte, t1 *big.Int, t2 *big.Int) *big.Int {
+	return hashToScalar([]byte("equality"), context, elementBytes(y), elementBytes(a), elementBytes(d), elementBytes(t1), elementBytes(t2))
+}
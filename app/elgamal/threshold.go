@@ -0,0 +1,207 @@
+package elgamal
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"math/big"
+)
+
+// ErrShareInvalid 收到的金鑰分片與發送者公布的承諾不符
+var ErrShareInvalid = errors.New("elgamal: share does not match the dealer's commitments")
+
+// Polynomial 分散式金鑰產生時每位受託人的秘密多項式，常數項為其私鑰的貢獻
+type Polynomial []*big.Int
+
+// NewPolynomial 產生 threshold-1 次的隨機多項式，任意 threshold 個分片即可還原常數項
+func NewPolynomial(threshold int, random io.Reader) (Polynomial, error) {
+	if threshold < 1 {
+		return nil, errors.New("elgamal: threshold must be at least 1")
+	}
+
+	polynomial := make(Polynomial, threshold)
+	for k := range polynomial {
+		coefficient, err := RandomScalar(random)
+		if err != nil {
+			return nil, err
+		}
+		polynomial[k] = coefficient
+	}
+
+	return polynomial, nil
+}
+
+// Evaluate 計算受託人編號 index 的分片 f(index) mod Q
+func (p Polynomial) Evaluate(index int64) *big.Int {
+	x := big.NewInt(index)
+	result := new(big.Int)
+	for k := len(p) - 1; k >= 0; k-- {
+		result.Mul(result, x).Add(result, p[k]).Mod(result, Q)
+	}
+
+	return result
+}
+
+// Commitments 公布的係數承諾 G^a_k，其他人可據此驗證分片
+func (p Polynomial) Commitments() []*Int {
+	commitments := make([]*Int, len(p))
+	for k, coefficient := range p {
+		commitments[k] = NewInt(exp(G, coefficient))
+	}
+
+	return commitments
+}
+
+// commitmentAt 由承諾計算 G^f(index)
+func commitmentAt(commitments []*Int, index int64) *big.Int {
+	x := big.NewInt(index)
+	power := big.NewInt(1)
+	result := big.NewInt(1)
+	for _, commitment := range commitments {
+		result = mul(result, exp(commitment.Big(), power))
+		power = new(big.Int).Mul(power, x)
+		power.Mod(power, Q)
+	}
+
+	return result
+}
+
+// ValidCommitments 檢查承諾的數量與每個值都是子群元素
+func ValidCommitments(commitments []*Int, threshold int) bool {
+	if len(commitments) != threshold {
+		return false
+	}
+	for _, commitment := range commitments {
+		if commitment == nil || !IsElement(commitment.Big()) || commitment.Big().Cmp(one) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// VerifyShare 驗證 G^share 是否等於由承諾計算的 G^f(index)
+func VerifyShare(commitments []*Int, index int64, share *big.Int) bool {
+	return isScalar(share) && exp(G, share).Cmp(commitmentAt(commitments, index)) == 0
+}
+
+// JointPublicKey 所有受託人承諾常數項的乘積，即選舉公鑰
+func JointPublicKey(dealers [][]*Int) *big.Int {
+	key := big.NewInt(1)
+	for _, commitments := range dealers {
+		key = mul(key, commitments[0].Big())
+	}
+
+	return key
+}
+
+// VerificationKey 受託人 index 合併後金鑰分片的公開驗證值 G^x_index
+func VerificationKey(dealers [][]*Int, index int64) *big.Int {
+	key := big.NewInt(1)
+	for _, commitments := range dealers {
+		key = mul(key, commitmentAt(commitments, index))
+	}
+
+	return key
+}
+
+// CombineShares 將收到的所有分片相加，得到受託人合併後的金鑰分片
+func CombineShares(shares []*big.Int) *big.Int {
+	sum := new(big.Int)
+	for _, share := range shares {
+		sum.Add(sum, share)
+	}
+
+	return sum.Mod(sum, Q)
+}
+
+// PartialDecrypt 以金鑰分片計算部分解密 A^x
+func PartialDecrypt(share *big.Int, ct *Ciphertext) *big.Int {
+	return exp(ct.A.Big(), share)
+}
+
+// LagrangeCoefficient 在 0 點還原秘密時，受託人 index 的拉格朗日係數
+func LagrangeCoefficient(indices []int64, index int64) *big.Int {
+	numerator := big.NewInt(1)
+	denominator := big.NewInt(1)
+	for _, other := range indices {
+		if other == index {
+			continue
+		}
+		numerator.Mul(numerator, big.NewInt(other)).Mod(numerator, Q)
+		denominator.Mul(denominator, big.NewInt(other-index)).Mod(denominator, Q)
+	}
+
+	return numerator.Mul(numerator, denominator.ModInverse(denominator, Q)).Mod(numerator, Q)
+}
+
+// Combine 以至少門檻數量的部分解密還原 G^m，partials 的鍵為受託人編號
+func Combine(ct *Ciphertext, partials map[int64]*big.Int) *big.Int {
+	indices := make([]int64, 0, len(partials))
+	for index := range partials {
+		indices = append(indices, index)
+	}
+
+	mask := big.NewInt(1)
+	for _, index := range indices {
+		mask = mul(mask, exp(partials[index], LagrangeCoefficient(indices, index)))
+	}
+
+	return mul(ct.B.Big(), inverse(mask))
+}
+
+// SealedShare 以接收者傳輸公鑰加密的金鑰分片：R = G^k，Data 以 sha256(H^k) 為金鑰的 AES-GCM 密文
+type SealedShare struct {
+	R    *Int   `json:"r"`
+	Data []byte `json:"data"`
+}
+
+// SealShare 以接收者的傳輸公鑰加密分片，context 綁定發送者與接收者
+func SealShare(transportKey *big.Int, share *big.Int, context []byte, random io.Reader) (*SealedShare, error) {
+	k, err := RandomScalar(random)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := shareCipher(exp(transportKey, k), context)
+	if err != nil {
+		return nil, err
+	}
+	// 每次加密的金鑰都不同，nonce 固定為 0 即可
+	nonce := make([]byte, aead.NonceSize())
+
+	return &SealedShare{
+		R:    NewInt(exp(G, k)),
+		Data: aead.Seal(nil, nonce, share.FillBytes(make([]byte, elementSize)), context),
+	}, nil
+}
+
+// OpenShare 以傳輸私鑰解開分片
+func OpenShare(transportSecret *big.Int, sealed *SealedShare, context []byte) (*big.Int, error) {
+	if sealed == nil || sealed.R == nil || !IsElement(sealed.R.Big()) {
+		return nil, errors.New("elgamal: invalid sealed share")
+	}
+
+	aead, err := shareCipher(exp(sealed.R.Big(), transportSecret), context)
+	if err != nil {
+		return nil, err
+	}
+	plain, err := aead.Open(nil, make([]byte, aead.NonceSize()), sealed.Data, context)
+	if err != nil {
+		return nil, err
+	}
+
+	return new(big.Int).SetBytes(plain), nil
+}
+
+func shareCipher(secret *big.Int, context []byte) (cipher.AEAD, error) {
+	key := sha256.Sum256(append(append([]byte("share|"), context...), elementBytes(secret)...))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
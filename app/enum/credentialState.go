@@ -0,0 +1,52 @@
+package enum
+
+// CredentialState 投票密碼的生命週期狀態
+type CredentialState string
+
+const (
+	// 已發放，尚未啟用
+	CredentialIssued CredentialState = "issued"
+	// 已啟用，可登入投票
+	CredentialActivated CredentialState = "activated"
+	// 已投票
+	CredentialUsed CredentialState = "used"
+	// 已撤銷，例如密碼遺失後重新發放
+	CredentialRevoked CredentialState = "revoked"
+	// 已過期
+	CredentialExpired CredentialState = "expired"
+)
+
+// credentialStateTransitions 每個狀態允許轉換的下一個狀態
+var credentialStateTransitions = map[CredentialState][]CredentialState{
+	CredentialIssued:    {CredentialActivated, CredentialRevoked, CredentialExpired},
+	CredentialActivated: {CredentialIssued, CredentialUsed, CredentialRevoked, CredentialExpired},
+	CredentialExpired:   {CredentialRevoked},
+}
+
+// CanTransitionTo 是否允許轉換至 next 狀態
+func (s CredentialState) CanTransitionTo(next CredentialState) bool {
+	for _, allowed := range credentialStateTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CanLogin 是否可登入，已投票者仍可登入查看投票狀態
+func (s CredentialState) CanLogin() bool {
+	return s == CredentialActivated || s == CredentialUsed
+}
+
+// CredentialAction 密碼稽核紀錄的操作
+type CredentialAction string
+
+const (
+	CredentialActivate   CredentialAction = "activate"
+	CredentialDeactivate CredentialAction = "deactivate"
+	CredentialRevoke     CredentialAction = "revoke"
+	CredentialExpire     CredentialAction = "expire"
+	CredentialReissue    CredentialAction = "reissue"
+	CredentialSetExpiry  CredentialAction = "set_expiry"
+)
@@ -0,0 +1,13 @@
+package enum
+
+// DeliveryStatus 寄送密碼給投票者的狀態
+type DeliveryStatus string
+
+const (
+	// 尚未寄送
+	DeliveryPending DeliveryStatus = "pending"
+	// 寄送成功
+	DeliverySent DeliveryStatus = "sent"
+	// 寄送失敗，可重新寄送
+	DeliveryFailed DeliveryStatus = "failed"
+)
@@ -0,0 +1,19 @@
+package enum
+
+// MailJobStatus 郵件佇列中工作的狀態
+type MailJobStatus string
+
+const (
+	// 等待寄送
+	MailQueued MailJobStatus = "queued"
+	// 寄送中，由工作者鎖定
+	MailSending MailJobStatus = "sending"
+	// 寄送失敗，等待重試
+	MailRetrying MailJobStatus = "retrying"
+	// 寄送成功
+	MailSent MailJobStatus = "sent"
+	// 超過重試次數，不再寄送
+	MailDead MailJobStatus = "dead"
+	// 寄送前已不需要寄送，例如收到提醒前已投票
+	MailCanceled MailJobStatus = "canceled"
+)
@@ -7,4 +7,8 @@ const (
 	UserNotLoggedIn  StatusCode = 10001 
 	// Voter not logged in
 	VoterNotLoggedIn StatusCode = 10002
+	// Vote has not started yet
+	VoteNotOpen      StatusCode = 10003
+	// Vote has ended
+	VoteClosed       StatusCode = 10004
 )
@@ -0,0 +1,52 @@
+package enum
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// TieBreak 計票時遇到平手的處理規則
+type TieBreak string
+
+const (
+	// 由最近一輪往前比較票數
+	Backward TieBreak = "backward"
+	// 由第一輪往後比較票數
+	Forward TieBreak = "forward"
+	// 直接依候選人建立順序
+	CandidateOrder TieBreak = "candidate"
+	// 以投票場次 UUID 為種子的固定亂數順序
+	Seeded TieBreak = "seeded"
+)
+
+// IsValid 是否為支援的平手處理規則
+func (t TieBreak) IsValid() bool {
+	switch t {
+	case Backward, Forward, CandidateOrder, Seeded:
+		return true
+	}
+
+	return false
+}
+
+// UnmarshalGQL 實作 graphql.Unmarshaler
+func (t *TieBreak) UnmarshalGQL(v any) error {
+	value, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("tie break must be a string")
+	}
+
+	tieBreak := TieBreak(value)
+	if !tieBreak.IsValid() {
+		return fmt.Errorf("%s is not a valid tie break", value)
+	}
+	*t = tieBreak
+
+	return nil
+}
+
+// MarshalGQL 實作 graphql.Marshaler
+func (t TieBreak) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(string(t)))
+}
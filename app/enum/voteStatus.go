@@ -0,0 +1,130 @@
+package enum
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// VoteStatus 投票場次的生命週期狀態
+type VoteStatus int
+
+const (
+	// 草稿，可自由編輯
+	Draft VoteStatus = iota
+	// 已排程，等待開始時間
+	Scheduled
+	// 開放投票
+	Open
+	// 已結束投票，等待開票
+	Closed
+	// 已開票
+	Tallied
+	// 已公布結果
+	Published
+	// 已封存
+	Archived
+)
+
+var voteStatusNames = []string{"draft", "scheduled", "open", "closed", "tallied", "published", "archived"}
+
+// voteStatusTransitions 每個狀態允許轉換的下一個狀態
+var voteStatusTransitions = map[VoteStatus][]VoteStatus{
+	Draft:     {Scheduled, Archived},
+	Scheduled: {Draft, Open, Archived},
+	Open:      {Closed},
+	Closed:    {Tallied},
+	Tallied:   {Published},
+	Published: {Archived},
+}
+
+// IsValid 是否為支援的投票狀態
+func (s VoteStatus) IsValid() bool {
+	return s >= Draft && s <= Archived
+}
+
+// IsEditable 問題與候選人是否仍可編輯，投票開始後即不可再修改
+func (s VoteStatus) IsEditable() bool {
+	return s == Draft || s == Scheduled
+}
+
+// CanTransitionTo 是否允許轉換至 next 狀態
+func (s VoteStatus) CanTransitionTo(next VoteStatus) bool {
+	for _, allowed := range voteStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s VoteStatus) String() string {
+	if !s.IsValid() {
+		return strconv.Itoa(int(s))
+	}
+
+	return voteStatusNames[s]
+}
+
+// ParseVoteStatus 由名稱取得投票狀態
+func ParseVoteStatus(name string) (VoteStatus, error) {
+	for i, statusName := range voteStatusNames {
+		if statusName == name {
+			return VoteStatus(i), nil
+		}
+	}
+
+	return Draft, fmt.Errorf("%s is not a valid vote status", name)
+}
+
+// MarshalJSON 以狀態名稱輸出
+func (s VoteStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.String())
+}
+
+// UnmarshalJSON 接受狀態名稱，或舊版的數字狀態
+func (s *VoteStatus) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		status, err := ParseVoteStatus(name)
+		if err != nil {
+			return err
+		}
+		*s = status
+		return nil
+	}
+
+	var value int
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("vote status must be a string or an integer")
+	}
+	if !VoteStatus(value).IsValid() {
+		return fmt.Errorf("%d is not a valid vote status", value)
+	}
+	*s = VoteStatus(value)
+
+	return nil
+}
+
+// UnmarshalGQL 實作 graphql.Unmarshaler
+func (s *VoteStatus) UnmarshalGQL(v any) error {
+	value, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("vote status must be a string")
+	}
+
+	status, err := ParseVoteStatus(value)
+	if err != nil {
+		return err
+	}
+	*s = status
+
+	return nil
+}
+
+// MarshalGQL 實作 graphql.Marshaler
+func (s VoteStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(s.String()))
+}
@@ -0,0 +1,67 @@
+package enum
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+type VotingMethod string
+
+const (
+	// 勾選制，最多可勾選與席次相同數量的候選人
+	Plurality VotingMethod = "plurality"
+	// 認可制，可勾選任意數量的候選人
+	Approval VotingMethod = "approval"
+	// 波達計數，依排序給分
+	Borda VotingMethod = "borda"
+	// 評分制，每位候選人給予 0 到最高分之間的分數
+	Score VotingMethod = "score"
+	// 排序複選制（Instant-runoff voting），逐輪淘汰最低票
+	IRV VotingMethod = "irv"
+	// 單記可讓渡投票制（Single transferable vote），用於多席次的排序選舉
+	STV VotingMethod = "stv"
+	// Condorcet 成對比較，無 Condorcet 贏家時以 Schulze 方法排序
+	Condorcet VotingMethod = "condorcet"
+)
+
+// IsValid 是否為支援的投票方式
+func (m VotingMethod) IsValid() bool {
+	switch m {
+	case Plurality, Approval, Borda, Score, IRV, STV, Condorcet:
+		return true
+	}
+
+	return false
+}
+
+// IsRanked 是否為需要排序的投票方式
+func (m VotingMethod) IsRanked() bool {
+	return m == IRV || m == STV || m == Condorcet || m == Borda
+}
+
+// SupportsEncryption 是否可用於加密投票，加密選票只能表示每位候選人勾選與否
+func (m VotingMethod) SupportsEncryption() bool {
+	return m == Plurality || m == Approval
+}
+
+// UnmarshalGQL 實作 graphql.Unmarshaler
+func (m *VotingMethod) UnmarshalGQL(v any) error {
+	value, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("voting method must be a string")
+	}
+
+	method := VotingMethod(value)
+	if !method.IsValid() {
+		return fmt.Errorf("%s is not a valid voting method", value)
+	}
+	*m = method
+
+	return nil
+}
+
+// MarshalGQL 實作 graphql.Marshaler
+func (m VotingMethod) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(string(m)))
+}
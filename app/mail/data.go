@@ -0,0 +1,63 @@
+package mail
+
+import (
+	"fmt"
+	"time"
+)
+
+// InvitationData 邀請投票郵件，內含投票者的密碼
+type InvitationData struct {
+	Name      string
+	VoteTitle string
+	StartTime time.Time
+	EndTime   time.Time
+	Password  string
+	LoginURL  string
+}
+
+// ReminderData 提醒尚未投票的郵件
+type ReminderData struct {
+	Name      string
+	VoteTitle string
+	EndTime   time.Time
+	LoginURL  string
+}
+
+// ResultsData 公布投票結果的郵件
+type ResultsData struct {
+	Name       string
+	VoteTitle  string
+	ResultsURL string
+}
+
+// PasswordResetData 重設帳號密碼的郵件
+type PasswordResetData struct {
+	Name     string
+	ResetURL string
+	Expires  time.Time
+}
+
+// VoteStatusData 通知投票建立者投票狀態變更的郵件
+type VoteStatusData struct {
+	Name      string
+	VoteTitle string
+	Status    string
+}
+
+// NewData 依範本名稱建立對應的資料結構，供佇列中的 JSON 資料還原
+func NewData(name string) (any, error) {
+	switch name {
+	case Invitation:
+		return &InvitationData{}, nil
+	case Reminder:
+		return &ReminderData{}, nil
+	case Results:
+		return &ResultsData{}, nil
+	case PasswordReset:
+		return &PasswordResetData{}, nil
+	case VoteStatus:
+		return &VoteStatusData{}, nil
+	default:
+		return nil, fmt.Errorf("unknown mail template %s", name)
+	}
+}
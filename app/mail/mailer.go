@@ -0,0 +1,104 @@
+package mail
+
+import (
+	"os"
+	"sync"
+)
+
+// Mailer 組合範本與寄送方式
+type Mailer struct {
+	From      string
+	Language  string
+	Renderer  *Renderer
+	Transport Transport
+}
+
+var (
+	defaultMailer *Mailer
+	defaultErr    error
+	defaultOnce   sync.Once
+	defaultMu     sync.RWMutex
+)
+
+// NewMailer 依照環境變數建立 Mailer
+func NewMailer() (*Mailer, error) {
+	i18nPath := os.Getenv("I18N_PATH")
+	if i18nPath == "" {
+		i18nPath = "i18n"
+	}
+	renderer, err := NewRenderer(i18nPath)
+	if err != nil {
+		return nil, err
+	}
+
+	transport, err := NewTransport()
+	if err != nil {
+		return nil, err
+	}
+
+	language := os.Getenv("MAIL_LANGUAGE")
+	if language == "" {
+		language = "en"
+	}
+
+	return &Mailer{
+		From:      os.Getenv("SMTP_FROM"),
+		Language:  language,
+		Renderer:  renderer,
+		Transport: transport,
+	}, nil
+}
+
+// Default 取得共用的 Mailer，第一次呼叫時依照環境變數建立
+func Default() (*Mailer, error) {
+	defaultMu.RLock()
+	if defaultMailer != nil {
+		defer defaultMu.RUnlock()
+		return defaultMailer, nil
+	}
+	defaultMu.RUnlock()
+
+	defaultOnce.Do(func() {
+		mailer, err := NewMailer()
+		defaultMu.Lock()
+		defer defaultMu.Unlock()
+		if defaultMailer == nil {
+			defaultMailer, defaultErr = mailer, err
+		}
+	})
+
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultMailer, defaultErr
+}
+
+// SetDefault 替換共用的 Mailer，測試時可改用記憶體寄送
+func SetDefault(mailer *Mailer) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultMailer, defaultErr = mailer, nil
+}
+
+// Send 寄送已組好的郵件，未指定寄件者時使用預設寄件者
+func (m *Mailer) Send(message Message) error {
+	if message.From == "" {
+		message.From = m.From
+	}
+
+	return m.Transport.Send(message)
+}
+
+// SendTemplate 以範本產生郵件並寄送，language 為空時使用預設語言
+func (m *Mailer) SendTemplate(to string, name string, language string, data any) error {
+	if language == "" {
+		language = m.Language
+	}
+
+	message, err := m.Renderer.Render(name, language, data)
+	if err != nil {
+		return err
+	}
+	message.To = []string{to}
+
+	return m.Send(message)
+}
@@ -0,0 +1,144 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/gogf/gf/i18n/gi18n"
+)
+
+// 郵件範本名稱
+const (
+	Invitation    = "invitation"
+	Reminder      = "reminder"
+	Results       = "results"
+	PasswordReset = "password_reset"
+	VoteStatus    = "vote_status"
+)
+
+//go:embed templates
+var templateFS embed.FS
+
+// Renderer 以 templates 目錄中的範本與 i18n 翻譯產生郵件內容。
+// 每個範本包含 <name>.txt（需定義 subject 區塊）與 <name>.html。
+type Renderer struct {
+	i18n  *gi18n.Manager
+	texts map[string]*texttemplate.Template
+	htmls map[string]*htmltemplate.Template
+}
+
+// NewRenderer 建立範本渲染器，i18nPath 為翻譯檔所在目錄
+func NewRenderer(i18nPath string) (*Renderer, error) {
+	manager := gi18n.New()
+	if err := manager.SetPath(i18nPath); err != nil {
+		return nil, err
+	}
+
+	r := &Renderer{
+		i18n:  manager,
+		texts: map[string]*texttemplate.Template{},
+		htmls: map[string]*htmltemplate.Template{},
+	}
+
+	// 解析時先放入空的翻譯函式，實際翻譯在渲染時依語言替換
+	placeholder := map[string]any{
+		"t":    func(key string, args ...any) string { return key },
+		"date": formatDate,
+		"dict": dict,
+	}
+	for _, name := range []string{Invitation, Reminder, Results, PasswordReset, VoteStatus} {
+		text, err := texttemplate.New(name+".txt").Funcs(placeholder).ParseFS(templateFS, "templates/"+name+".txt")
+		if err != nil {
+			return nil, err
+		}
+		if text.Lookup("subject") == nil {
+			return nil, fmt.Errorf("mail template %s has no subject", name)
+		}
+		html, err := htmltemplate.New(name+".html").Funcs(placeholder).ParseFS(templateFS, "templates/layout.html", "templates/"+name+".html")
+		if err != nil {
+			return nil, err
+		}
+
+		r.texts[name] = text
+		r.htmls[name] = html
+	}
+
+	return r, nil
+}
+
+// Render 以指定語言渲染範本，回傳不含收件者的郵件
+func (r *Renderer) Render(name string, language string, data any) (Message, error) {
+	text, ok := r.texts[name]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown mail template %s", name)
+	}
+
+	ctx := gi18n.WithLanguage(context.Background(), language)
+	funcs := map[string]any{
+		"t": func(key string, args ...any) string {
+			if len(args) == 0 {
+				return r.i18n.Translate(ctx, key)
+			}
+			return r.i18n.TranslateFormat(ctx, key, args...)
+		},
+		"date": formatDate,
+		"dict": dict,
+	}
+
+	textClone, err := text.Clone()
+	if err != nil {
+		return Message{}, err
+	}
+	textClone.Funcs(funcs)
+	htmlClone, err := r.htmls[name].Clone()
+	if err != nil {
+		return Message{}, err
+	}
+	htmlClone.Funcs(funcs)
+
+	var subject, body, html bytes.Buffer
+	if err := textClone.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return Message{}, err
+	}
+	if err := textClone.Execute(&body, data); err != nil {
+		return Message{}, err
+	}
+	if err := htmlClone.Execute(&html, data); err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		Subject: strings.Join(strings.Fields(subject.String()), " "),
+		Text:    strings.TrimSpace(body.String()) + "\n",
+		HTML:    html.String(),
+	}, nil
+}
+
+// formatDate 郵件中統一的時間格式
+func formatDate(t time.Time) string {
+	return t.Format("2006-01-02 15:04 MST")
+}
+
+// dict 將成對的參數組成 map，供子範本使用
+func dict(pairs ...any) (map[string]any, error) {
+	if len(pairs)%2 != 0 {
+		return nil, fmt.Errorf("dict requires key value pairs")
+	}
+
+	values := make(map[string]any, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		key, ok := pairs[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("dict key must be a string")
+		}
+		values[key] = pairs[i+1]
+	}
+
+	return values, nil
+}
@@ -0,0 +1,125 @@
+package mail
+
+import (
+	"fmt"
+	"net/mail"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Transport 郵件的寄送方式，正式環境使用 SMTP，測試可改用記憶體或檔案
+type Transport interface {
+	Send(message Message) error
+}
+
+// SMTPTransport 透過 SMTP 伺服器寄送
+type SMTPTransport struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+}
+
+func (t SMTPTransport) Send(message Message) error {
+	data, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+
+	from, err := mail.ParseAddress(message.From)
+	if err != nil {
+		return err
+	}
+	recipients := make([]string, len(message.To))
+	for i, to := range message.To {
+		address, err := mail.ParseAddress(to)
+		if err != nil {
+			return err
+		}
+		recipients[i] = address.Address
+	}
+
+	var auth smtp.Auth
+	if t.Username != "" {
+		auth = smtp.PlainAuth("", t.Username, t.Password, t.Host)
+	}
+
+	return smtp.SendMail(t.Host+":"+t.Port, auth, from.Address, recipients, data)
+}
+
+// MemoryTransport 將郵件保留在記憶體中，供測試檢查
+type MemoryTransport struct {
+	mu       sync.Mutex
+	messages []Message
+}
+
+func (t *MemoryTransport) Send(message Message) error {
+	if err := message.Validate(); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.messages = append(t.messages, message)
+
+	return nil
+}
+
+// Messages 取得目前收到的所有郵件
+func (t *MemoryTransport) Messages() []Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]Message(nil), t.messages...)
+}
+
+// FileTransport 將郵件寫成 .eml 檔案，方便在本機開發時預覽
+type FileTransport struct {
+	Dir string
+
+	mu    sync.Mutex
+	count int
+}
+
+func (t *FileTransport) Send(message Message) error {
+	data, err := message.Bytes()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(t.Dir, 0o755); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.count++
+	name := fmt.Sprintf("%s-%04d.eml", time.Now().Format("20060102-150405"), t.count)
+	t.mu.Unlock()
+
+	return os.WriteFile(filepath.Join(t.Dir, name), data, 0o644)
+}
+
+// NewTransport 依照 MAIL_TRANSPORT 環境變數建立寄送方式，預設為 SMTP
+func NewTransport() (Transport, error) {
+	switch transport := os.Getenv("MAIL_TRANSPORT"); transport {
+	case "", "smtp":
+		return SMTPTransport{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     os.Getenv("SMTP_PORT"),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+		}, nil
+	case "file":
+		dir := os.Getenv("MAIL_FILE_DIR")
+		if dir == "" {
+			dir = "storage/mail"
+		}
+		return &FileTransport{Dir: dir}, nil
+	case "memory":
+		return &MemoryTransport{}, nil
+	default:
+		return nil, fmt.Errorf("unsupported mail transport %s", transport)
+	}
+}
@@ -34,6 +34,15 @@ type VoterClaims struct {
 	jwt.RegisteredClaims
 }
 
+// LoginAt 回傳投票者登入的時間，舊的令牌沒有簽發時間時視為現在
+func (c VoterClaims) LoginAt() time.Time {
+	if c.IssuedAt == nil {
+		return time.Now()
+	}
+
+	return c.IssuedAt.Time
+}
+
 // BallotClaims 投票者的選票 JWT 令牌
 type BallotClaims struct {
 	ID						uint64 			 `json:"id"`
@@ -59,13 +68,14 @@ func GenUserToken(Id uint64, account string, roles []string) (string, string, er
 	return GenToken(accessClaims)
 }
 
-// GenVoterToken 生成投票者 JWT 令牌
-func GenVoterToken(Id uint64, voteId uuid.UUID, isVoted bool) (string, string, error) {
+// GenVoterToken 生成投票者 JWT 令牌，issuedAt 為投票者登入的時間，用來判斷投票結束後的寬限期
+func GenVoterToken(Id uint64, voteId uuid.UUID, isVoted bool, issuedAt time.Time) (string, string, error) {
 	accessClaims := VoterClaims{
 		ID:      Id,
 		VoteID:  voteId,
 		IsVoted: isVoted,
 		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(issuedAt),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenExpireDuration)),
 			Issuer:    os.Getenv("APP_NAME"),
 		},
@@ -1,17 +1,26 @@
 package model
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
+	"vote/app/elgamal"
 )
 
 func (Ballot) TableName() string {
 	return "ballots"
 }
 
+// Ballot 選票不記錄投票者，是否已投票改由 Participation 紀錄；時間只保留到小時
 type Ballot struct {
 	ID        	  uint64    	 		`gorm:"primary_key;auto_increment" json:"id"`
-	PasswordID    uint64    	 		`gorm:"index;not null;" json:"password_id"`
 	QuestionID	  uint64    	 		`gorm:"index;not null;" json:"question_id"`
+	// 投票回條，同一次送出的選票共用一張回條
+	ReceiptID     *uint64       		`gorm:"index;" json:"-"`
+	// 重新投票時這張選票取代的回條，寫入雜湊鏈讓回條的取代紀錄無法被竄改
+	SupersedesID  *uint64       		`gorm:"index;" json:"-"`
+	// 加密選票：所有候選人密文相乘後，選擇數量在問題限制內的證明
+	Proof         *elgamal.RangeProof `gorm:"type:jsonb;serializer:json;" json:"proof,omitempty"`
 	CreatedAt 	  time.Time 	 		`gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt 	  time.Time 	 		`gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
 	BallotSelects []BallotSelect 	`gorm:"foreignKey:BallotID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"ballot_selects,omitempty"`
@@ -20,3 +29,51 @@ type Ballot struct {
 type BallotCreate struct {
 	Selections map[string][]string `json:"selections" binding:"required"`
 }
+
+// EncryptedBallot 加密投票模式的選票，每個問題的每位候選人都要有一個密文
+type EncryptedBallot struct {
+	Questions []EncryptedQuestion `json:"questions"`
+}
+
+// EncryptedQuestion 單一問題的加密選擇
+type EncryptedQuestion struct {
+	QuestionID uint64               `json:"question_id"`
+	Selections []EncryptedSelection `json:"selections"`
+	// 所有候選人密文相乘後，選擇數量為 0 或在問題限制內的證明
+	Proof *elgamal.RangeProof `json:"proof"`
+}
+
+// EncryptedSelection 單一候選人的密文，以及明文為 0 或 1 的證明
+type EncryptedSelection struct {
+	CandidateID uint64              `json:"candidate_id"`
+	Ciphertext  *elgamal.Ciphertext `json:"ciphertext"`
+	Proof       *elgamal.RangeProof `json:"proof"`
+}
+
+
+// BallotSelections 投票者送出的選票，格式為 問題ID -> 候選人ID -> 標記
+type BallotSelections map[uint64]map[uint64]BallotMark
+
+// BallotMark 選票上對單一候選人的標記。
+// 勾選制可使用 true/false，排序制使用數字表示順位，評分制則為分數。
+type BallotMark int
+
+// UnmarshalJSON 接受布林值或整數
+func (m *BallotMark) UnmarshalJSON(data []byte) error {
+	var checked bool
+	if err := json.Unmarshal(data, &checked); err == nil {
+		*m = 0
+		if checked {
+			*m = 1
+		}
+		return nil
+	}
+
+	var value int
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("ballot mark must be a boolean or an integer: %s", string(data))
+	}
+	*m = BallotMark(value)
+
+	return nil
+}
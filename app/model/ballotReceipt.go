@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+	"vote/app/enum"
+
+	"github.com/google/uuid"
+)
+
+func (BallotReceipt) TableName() string {
+	return "ballot_receipts"
+}
+
+// BallotReceipt 投票回條，投票者可用回條代碼確認選票已被計入，代碼不會透露投票內容。
+// 建立時間與選票相同只保留到小時，避免由回條推得投票時間。
+type BallotReceipt struct {
+	ID     uint64    `gorm:"primary_key;auto_increment" json:"-"`
+	VoteID uuid.UUID `gorm:"type:uuid;index;not null;" json:"vote_id"`
+	Code   string    `gorm:"size:64;not null;uniqueIndex;" json:"code"`
+	// 開放重新投票時，密碼目前有效回條上的密碼摘要，被取代時清除，每組密碼最多只有一張有效回條
+	SupersedeDigest *string `gorm:"size:64;uniqueIndex;" json:"-"`
+	// 已被同一組密碼之後的選票取代，不計入開票
+	Superseded bool      `gorm:"not null;default:false;" json:"superseded"`
+	CreatedAt  time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	Ballots    []Ballot  `gorm:"foreignKey:ReceiptID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"-"`
+}
+
+// BallotReceiptLookup 公開查詢回條的結果
+type BallotReceiptLookup struct {
+	Code       string          `json:"code"`
+	VoteID     uuid.UUID       `json:"vote_id"`
+	VoteTitle  string          `json:"vote_title"`
+	VoteStatus enum.VoteStatus `json:"vote_status"`
+	// 選票包含的問題數
+	Questions int64 `json:"questions"`
+	// 選票是否已被重新投票取代
+	Superseded bool `json:"superseded"`
+	// 選票是否已計入開票結果
+	Included  bool       `json:"included"`
+	TalliedAt *time.Time `json:"tallied_at"`
+}
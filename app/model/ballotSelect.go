@@ -1,5 +1,7 @@
 package model
 
+import "vote/app/elgamal"
+
 func (BallotSelect) TableName() string {
 	return "ballot_selects"
 }
@@ -8,4 +10,9 @@ type BallotSelect struct {
 	ID        	  uint64    	`gorm:"primary_key;auto_increment" json:"id"`
 	BallotID      uint64    	`gorm:"index;not null;" json:"ballot_id"`
 	CandidateID	  uint64    	`gorm:"index;not null;" json:"candidate_id"`
+	Rank		  int       	`gorm:"not null;default:0;" json:"rank"`
+	Value		  int       	`gorm:"not null;default:0;" json:"value"`
+	// 加密選票的密文與明文為 0 或 1 的證明，此時 Value 不使用
+	Ciphertext    *elgamal.Ciphertext `gorm:"type:jsonb;serializer:json;" json:"ciphertext,omitempty"`
+	Proof         *elgamal.RangeProof `gorm:"type:jsonb;serializer:json;" json:"proof,omitempty"`
 }
\ No newline at end of file
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+	"vote/app/enum"
+
+	"github.com/google/uuid"
+)
+
+func (CredentialAudit) TableName() string {
+	return "credential_audits"
+}
+
+// CredentialAudit 密碼狀態變更的稽核紀錄，ActorID 為空表示由系統執行
+type CredentialAudit struct {
+	ID         uint64                `gorm:"primary_key;auto_increment" json:"id"`
+	VoteID     uuid.UUID             `gorm:"type:uuid;index;not null;" json:"vote_id"`
+	PasswordID uint64                `gorm:"index;not null;" json:"password_id"`
+	VoterID    *uint64               `gorm:"index;" json:"voter_id"`
+	Action     enum.CredentialAction `gorm:"size:20;not null;" json:"action"`
+	FromState  enum.CredentialState  `gorm:"size:20;" json:"from_state"`
+	ToState    enum.CredentialState  `gorm:"size:20;" json:"to_state"`
+	ActorID    *uint64               `json:"actor_id"`
+	Reason     string                `gorm:"size:255;" json:"reason"`
+	CreatedAt  time.Time             `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+type CredentialAuditQuery struct {
+	PasswordID uint64 `form:"password_id" json:"password_id" example:"1"`
+	VoterID    uint64 `form:"voter_id" json:"voter_id" example:"1"`
+}
+
+// CredentialAuditStats 投票的密碼狀態、已投票人數與選票送出次數統計
+type CredentialAuditStats struct {
+	VoteID      uuid.UUID                      `json:"vote_id"`
+	Credentials map[enum.CredentialState]int64 `json:"credentials"`
+	Voted       int64                          `json:"voted"`
+	// 送出選票的次數，開放重新投票時可能多於已投票人數
+	Submissions int64 `json:"submissions"`
+	// 被同一組密碼重新投票取代、不計入開票的次數
+	Superseded int64 `json:"superseded"`
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (LedgerEntry) TableName() string {
+	return "ballot_ledger"
+}
+
+// LedgerEntry 選票雜湊鏈中的一筆紀錄，Hash 同時承諾前一筆的雜湊與選票內容。
+// 建立時間與選票相同只保留到小時。
+type LedgerEntry struct {
+	ID        uint64    `gorm:"primary_key;auto_increment" json:"id"`
+	VoteID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_ballot_ledger_vote_sequence,priority:1;" json:"vote_id"`
+	Sequence  uint64    `gorm:"not null;uniqueIndex:idx_ballot_ledger_vote_sequence,priority:2;" json:"sequence"`
+	BallotID  uint64    `gorm:"not null;uniqueIndex;" json:"ballot_id"`
+	PrevHash  string    `gorm:"size:64;not null;" json:"prev_hash"`
+	Hash      string    `gorm:"size:64;not null;" json:"hash"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// LedgerIssue 驗證雜湊鏈時發現的第一個問題
+type LedgerIssue struct {
+	Sequence uint64 `json:"sequence"`
+	BallotID uint64 `json:"ballot_id"`
+	// missing_entry、missing_ballot、prev_hash、content、unchained_ballot 或 supersede
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// LedgerReport 雜湊鏈的驗證結果
+type LedgerReport struct {
+	VoteID   uuid.UUID    `json:"vote_id"`
+	Entries  uint64       `json:"entries"`
+	HeadHash string       `json:"head_hash"`
+	Valid    bool         `json:"valid"`
+	Issue    *LedgerIssue `json:"issue,omitempty"`
+}
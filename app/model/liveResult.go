@@ -0,0 +1,35 @@
+package model
+
+import (
+	"time"
+	"vote/app/enum"
+
+	"github.com/google/uuid"
+)
+
+// LiveResult 投票進行中的即時統計，透過 GraphQL 訂閱與 SSE 推送
+type LiveResult struct {
+	VoteID uuid.UUID       `json:"vote_id"`
+	Status enum.VoteStatus `json:"status"`
+	// 未撤銷的密碼數
+	Eligible int64 `json:"eligible"`
+	// 已投票的密碼數
+	Voted   int64   `json:"voted"`
+	Turnout float64 `json:"turnout"`
+	// 投票開放即時結果時各候選人目前的票數，加密投票不提供
+	Questions []LiveQuestion `json:"questions"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// LiveQuestion 單一問題目前的票數
+type LiveQuestion struct {
+	QuestionID uint64      `json:"question_id"`
+	Ballots    int64       `json:"ballots"`
+	Candidates []LiveCount `json:"candidates"`
+}
+
+// LiveCount 候選人目前的票數：排序制為第一順位的票數，其他投票方式為有標記此候選人的選票數
+type LiveCount struct {
+	CandidateID uint64 `json:"candidate_id"`
+	Votes       int64  `json:"votes"`
+}
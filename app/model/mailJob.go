@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+	"vote/app/enum"
+
+	"github.com/google/uuid"
+)
+
+func (MailJob) TableName() string {
+	return "mail_jobs"
+}
+
+// MailJob 郵件佇列中的一封郵件。
+// 寄給名冊投票者的郵件只記錄 VoterID，密碼在寄送時才解密，不會存入佇列。
+type MailJob struct {
+	ID            uint64             `gorm:"primary_key;auto_increment" json:"id"`
+	VoteID        *uuid.UUID         `gorm:"type:uuid;index;" json:"vote_id"`
+	VoterID       *uint64            `gorm:"index;" json:"voter_id"`
+	To            string             `gorm:"size:100;not null;" json:"to"`
+	Template      string             `gorm:"size:50;not null;" json:"template"`
+	Language      string             `gorm:"size:10;" json:"language"`
+	// 範本資料，以 JSON 儲存
+	Data          string             `gorm:"type:text;" json:"-"`
+	Status        enum.MailJobStatus `gorm:"size:20;not null;default:queued;index:idx_mail_jobs_status_next,priority:1;" json:"status"`
+	Attempts      int                `gorm:"not null;default:0;" json:"attempts"`
+	NextAttemptAt time.Time          `gorm:"not null;default:CURRENT_TIMESTAMP;index:idx_mail_jobs_status_next,priority:2;" json:"next_attempt_at"`
+	LockedAt      *time.Time         `json:"locked_at"`
+	LastError     string             `gorm:"size:255;" json:"last_error"`
+	SentAt        *time.Time         `json:"sent_at"`
+	CreatedAt     time.Time          `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt     time.Time          `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// MailProgress 投票郵件的寄送進度
+type MailProgress struct {
+	VoteID   uuid.UUID `json:"vote_id"`
+	Template string    `json:"template,omitempty"`
+	Total    int64     `json:"total"`
+	Queued   int64     `json:"queued"`
+	Sending  int64     `json:"sending"`
+	Retrying int64     `json:"retrying"`
+	Sent     int64     `json:"sent"`
+	Dead     int64     `json:"dead"`
+	Canceled int64     `json:"canceled"`
+}
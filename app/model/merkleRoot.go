@@ -0,0 +1,45 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (MerkleRoot) TableName() string {
+	return "vote_merkle_roots"
+}
+
+// MerkleRoot 投票所有選票的 Merkle 樹根，以後端驗證金鑰簽章，可選擇寫入區塊鏈
+type MerkleRoot struct {
+	ID        uint64    `gorm:"primary_key;auto_increment" json:"-"`
+	VoteID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex;" json:"vote_id"`
+	Root      string    `gorm:"size:66;not null;" json:"root"`
+	LeafCount int       `gorm:"not null;" json:"leaf_count"`
+	Signature string    `gorm:"size:132;not null;" json:"signature"`
+	Signer    string    `gorm:"size:42;not null;" json:"signer"`
+	// 寫入區塊鏈的交易雜湊，未設定 ETH_RPC_URL 時為空
+	AnchorTx  string    `gorm:"size:66;" json:"anchor_tx"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// MerkleStep 證明路徑上的一個兄弟節點，Left 表示兄弟節點在左側
+type MerkleStep struct {
+	Hash string `json:"hash"`
+	Left bool   `json:"left"`
+}
+
+// MerkleProof 單一選票的包含證明，只包含雜湊，不會透露投票內容。
+// 葉節點由回條代碼與選票雜湊計算，驗證時以投票者手上的回條代碼重新計算。
+type MerkleProof struct {
+	Leaf       string       `json:"leaf"`
+	BallotHash string       `json:"ballot_hash"`
+	Index      int          `json:"index"`
+	Siblings   []MerkleStep `json:"siblings"`
+}
+
+// MerkleProofBundle 公布的樹根、簽章與選票的包含證明，可交由離線驗證程式檢查
+type MerkleProofBundle struct {
+	MerkleRoot
+	Proofs []MerkleProof `json:"proofs"`
+}
@@ -0,0 +1,22 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (Participation) TableName() string {
+	return "participations"
+}
+
+// Participation 密碼已投票的紀錄，與選票分開儲存，選票不再記錄是哪一組密碼投的。
+// 以密碼為主鍵、不使用流水號，時間只保留到小時，避免依順序或時間對應回選票。
+type Participation struct {
+	PasswordID uint64    `gorm:"primaryKey;autoIncrement:false" json:"password_id"`
+	VoteID     uuid.UUID `gorm:"type:uuid;not null;index;" json:"vote_id"`
+	VotedAt    time.Time `gorm:"not null;" json:"voted_at"`
+	// 送出選票時的冪等鍵雜湊與以冪等鍵加密的回條，重送時回傳原本的結果
+	IdempotencyDigest *string `gorm:"size:64;" json:"-"`
+	SealedReceipt     []byte  `gorm:"type:bytea;" json:"-"`
+}
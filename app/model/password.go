@@ -2,6 +2,7 @@ package model
 
 import (
 	"time"
+	"vote/app/enum"
 
 	"github.com/google/uuid"
 )
@@ -12,11 +13,18 @@ func (Password) TableName() string {
 
 type Password struct {
 	ID        uint64       `gorm:"primary_key;auto_increment" json:"id"`
-	VoteID	  uuid.UUID    `gorm:"index;not null;" json:"vote_id"`
+	VoteID	  uuid.UUID    `gorm:"index;uniqueIndex:idx_passwords_vote_digest,priority:1;not null;" json:"vote_id"`
+	// 可還原的密文，供主辦單位列印密碼
 	Password  string       `gorm:"size:100;not null;" json:"password"`
+	// 以 HMAC 計算的固定摘要，供投票者登入時查詢
+	Digest    string       `gorm:"size:64;not null;uniqueIndex:idx_passwords_vote_digest,priority:2;" json:"-"`
+	// 是否可登入，與 State 同步：已啟用或已投票時為 true
 	Status	  bool         `gorm:"default:false;" json:"status"`
+	State     enum.CredentialState `gorm:"size:20;not null;default:issued;index;" json:"state"`
+	// 過期時間，為空表示不會過期
+	ExpiresAt *time.Time   `json:"expires_at"`
 	CreatedAt time.Time    `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
-	Ballots	  []Ballot     `gorm:"foreignKey:PasswordID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"ballots,omitempty"`
+	Participation *Participation `gorm:"foreignKey:PasswordID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
 }
 
 type PasswordCreate struct {
@@ -31,6 +39,32 @@ type VoterLogin struct {
 	Password string    `json:"password" binding:"required" example:"password"`
 }
 
+type PasswordDecrypt struct {
+	VoteID uuid.UUID `json:"vote_id" binding:"required" example:"00000000-0000-0000-0000-000000000000"`
+	IDs    []uint64  `json:"ids" binding:"required,min=1" example:"1,2,3"`
+}
+
+type PasswordExport struct {
+	PerPage int    `form:"per_page,default=8" json:"per_page" binding:"min=1,max=30" example:"8"`
+	Status  string `form:"status,default=active" json:"status" binding:"oneof=active inactive all" example:"active"`
+}
+
+type PasswordDownload struct {
+	Format string `form:"format,default=csv" json:"format" binding:"oneof=csv xlsx" example:"csv"`
+	Status string `form:"status,default=all" json:"status" binding:"oneof=active inactive all" example:"all"`
+}
+
+// PasswordImportError 匯入密碼時單一列的錯誤，Row 為 CSV 的列號（從 1 開始）
+type PasswordImportError struct {
+	Row     int    `json:"row"`
+	Message string `json:"message"`
+}
+
+type PasswordImportReport struct {
+	Imported int                   `json:"imported"`
+	Errors   []PasswordImportError `json:"errors"`
+}
+
 type PasswordQuery struct {
 	VoteID 		uuid.UUID 	`json:"vote_id" example:"00000000-0000-0000-0000-000000000000"`
 	Password 	string    	`json:"password" example:"password"`
@@ -44,3 +78,14 @@ type PasswordStatusQuery struct {
 	Passwords []any       `json:"passwords" example:"[1,2,3,\"abc\"]"`
 	Status    bool        `json:"status" example:"false"`
 }
+
+type PasswordRevoke struct {
+	IDs    []uint64 `json:"ids" binding:"required,min=1" example:"1,2,3"`
+	Reason string   `json:"reason" binding:"max=255" example:"lost"`
+}
+
+type PasswordExpiry struct {
+	IDs []uint64 `json:"ids" binding:"required,min=1" example:"1,2,3"`
+	// 為空時取消過期時間
+	ExpiresAt *time.Time `json:"expires_at" example:"2006-01-02T15:04:05Z"`
+}
@@ -2,6 +2,7 @@ package model
 
 import (
 	"time"
+	"vote/app/enum"
 
 	"github.com/google/uuid"
 )
@@ -15,6 +16,11 @@ type Question struct {
 	VoteID      uuid.UUID   `gorm:"index;type:uuid;not null;" json:"vote_id"`
 	Title       string 			`gorm:"size:100;not null;" json:"title"`
 	Description string 			`gorm:"size:255;" json:"description"`
+	Method      enum.VotingMethod `gorm:"size:20;not null;default:plurality;" json:"method"`
+	Seats       int         `gorm:"not null;default:1;" json:"seats"`
+	MaxScore    int         `gorm:"not null;default:5;" json:"max_score"`
+	MinSelections int       `gorm:"not null;default:0;" json:"min_selections"`
+	MaxSelections int       `gorm:"not null;default:0;" json:"max_selections"`
 	CreatedAt   time.Time   `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt   time.Time   `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
 	Candidates  []Candidate `gorm:"foreignKey:QuestionID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"candidates,omitempty"`
@@ -24,6 +30,11 @@ type QuestionCreate struct {
 	VoteID      uuid.UUID   `json:"vote_id" binding:"required" example:"00000000-0000-0000-0000-000000000000"`
 	Title       string 			`json:"title" binding:"required" example:"title"`
 	Description string 			`json:"description" example:"description"`
+	Method      enum.VotingMethod `json:"method" binding:"omitempty,oneof=plurality approval borda score irv stv condorcet" example:"plurality"`
+	Seats       int         `json:"seats" binding:"omitempty,min=1" example:"1"`
+	MaxScore    int         `json:"max_score" binding:"omitempty,min=1,max=100" example:"5"`
+	MinSelections int       `json:"min_selections" binding:"omitempty,min=0" example:"1"`
+	MaxSelections int       `json:"max_selections" binding:"omitempty,min=1,gtefield=MinSelections" example:"1"`
 }
 
 // Query parameters for filtering, sorting, and pagination
@@ -0,0 +1,87 @@
+package model
+
+import (
+	"time"
+	"vote/app/enum"
+
+	"github.com/google/uuid"
+)
+
+func (VoteResult) TableName() string {
+	return "vote_results"
+}
+
+// VoteResult 投票場次的開票結果，每個問題的結果以 JSON 形式儲存
+type VoteResult struct {
+	ID        uint64           `gorm:"primary_key;auto_increment" json:"-"`
+	VoteID    uuid.UUID        `gorm:"type:uuid;uniqueIndex;not null;" json:"vote_id"`
+	Voters    int64            `gorm:"not null;default:0;" json:"voters"`
+	Questions []QuestionResult `gorm:"type:jsonb;serializer:json;" json:"questions"`
+	CreatedAt time.Time        `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt time.Time        `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+}
+
+// QuestionResult 單一問題的開票結果
+type QuestionResult struct {
+	QuestionID   uint64            `json:"question_id"`
+	Title        string            `json:"title"`
+	Method       enum.VotingMethod `json:"method"`
+	Seats        int               `json:"seats"`
+	Quota        float64           `json:"quota,omitempty"`
+	TotalBallots int64             `json:"total_ballots"`
+	ValidBallots int64             `json:"valid_ballots"`
+	BlankBallots int64             `json:"blank_ballots"`
+	Abstentions  int64             `json:"abstentions"`
+	Candidates   []CandidateResult `json:"candidates"`
+	Winners      []uint64          `json:"winners"`
+	Rounds       []TallyRound      `json:"rounds,omitempty"`
+	// Condorcet 計票的成對比較矩陣與排名
+	Pairwise        []PairwiseRow `json:"pairwise,omitempty"`
+	CondorcetWinner *uint64       `json:"condorcet_winner,omitempty"`
+	Ranking         []uint64      `json:"ranking,omitempty"`
+}
+
+// CandidateResult 單一候選人的得票數與得票率
+type CandidateResult struct {
+	CandidateID uint64  `json:"candidate_id"`
+	Name        string  `json:"name"`
+	Votes       int64   `json:"votes"`
+	Percentage  float64 `json:"percentage"`
+	// 評分制的平均分數，未評分視為 0 分
+	Average float64 `json:"average,omitempty"`
+}
+
+// TallyRound 排序制每一輪的計票紀錄
+type TallyRound struct {
+	Round      int             `json:"round"`
+	Counts     []RoundCount    `json:"counts"`
+	Exhausted  float64         `json:"exhausted"`
+	Elected    []uint64        `json:"elected"`
+	Eliminated []uint64        `json:"eliminated"`
+	Transfers  []RoundTransfer `json:"transfers"`
+}
+
+// RoundCount 候選人在某一輪的票數
+type RoundCount struct {
+	CandidateID uint64  `json:"candidate_id"`
+	Votes       float64 `json:"votes"`
+}
+
+// RoundTransfer 某一輪淘汰或當選者的選票轉移，To 為 0 表示選票已無可轉移的順位
+type RoundTransfer struct {
+	From  uint64  `json:"from"`
+	To    uint64  `json:"to"`
+	Votes float64 `json:"votes"`
+}
+
+// PairwiseRow 成對比較矩陣的一列，Against 為偏好此候選人勝過其他候選人的票數
+type PairwiseRow struct {
+	CandidateID uint64          `json:"candidate_id"`
+	Against     []PairwiseCount `json:"against"`
+}
+
+// PairwiseCount 偏好某候選人勝過 CandidateID 的票數
+type PairwiseCount struct {
+	CandidateID uint64 `json:"candidate_id"`
+	Votes       int64  `json:"votes"`
+}
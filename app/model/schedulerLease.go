@@ -0,0 +1,16 @@
+package model
+
+import (
+	"time"
+)
+
+func (SchedulerLease) TableName() string {
+	return "scheduler_leases"
+}
+
+// SchedulerLease 背景排程的租約，多個服務同時運行時只有持有租約者會執行排程
+type SchedulerLease struct {
+	Name      string    `gorm:"primary_key;size:50" json:"name"`
+	Holder    string    `gorm:"size:100;not null;" json:"holder"`
+	ExpiresAt time.Time `gorm:"not null;" json:"expires_at"`
+}
@@ -0,0 +1,120 @@
+package model
+
+import (
+	"time"
+	"vote/app/elgamal"
+
+	"github.com/google/uuid"
+)
+
+func (Trustee) TableName() string {
+	return "trustees"
+}
+
+// Trustee 加密投票的受託人，共同產生選舉金鑰並在開票時部分解密
+type Trustee struct {
+	ID     uint64    `gorm:"primary_key;auto_increment" json:"-"`
+	VoteID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_trustees_vote_number,priority:1;" json:"vote_id"`
+	// 受託人編號，從 1 開始，也是分片多項式的求值點
+	Number      int    `gorm:"not null;uniqueIndex:idx_trustees_vote_number,priority:2;" json:"number"`
+	Name        string `gorm:"size:100;not null;" json:"name"`
+	TokenDigest string `gorm:"size:64;not null;uniqueIndex;" json:"-"`
+	// 接收其他受託人金鑰分片用的傳輸公鑰
+	TransportKey *elgamal.Int `gorm:"type:text;serializer:json;" json:"transport_key"`
+	// 秘密多項式係數的承諾，第一個為對選舉公鑰的貢獻
+	Commitments []*elgamal.Int `gorm:"type:jsonb;serializer:json;" json:"commitments"`
+	// 是否已送出所有部分解密
+	Decrypted bool      `gorm:"not null;default:false;" json:"decrypted"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (TrusteeShare) TableName() string {
+	return "trustee_shares"
+}
+
+// TrusteeShare 受託人發給另一位受託人的金鑰分片，以接收者的傳輸公鑰加密
+type TrusteeShare struct {
+	ID         uint64               `gorm:"primary_key;auto_increment" json:"-"`
+	VoteID     uuid.UUID            `gorm:"type:uuid;not null;uniqueIndex:idx_trustee_shares_pair,priority:1;" json:"vote_id"`
+	FromNumber int                  `gorm:"not null;uniqueIndex:idx_trustee_shares_pair,priority:2;" json:"from"`
+	ToNumber   int                  `gorm:"not null;uniqueIndex:idx_trustee_shares_pair,priority:3;" json:"to"`
+	Sealed     *elgamal.SealedShare `gorm:"type:jsonb;serializer:json;not null;" json:"sealed"`
+	CreatedAt  time.Time            `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+func (PartialDecryption) TableName() string {
+	return "partial_decryptions"
+}
+
+// PartialDecryption 受託人對某候選人合計密文的部分解密與證明
+type PartialDecryption struct {
+	ID            uint64                 `gorm:"primary_key;auto_increment" json:"-"`
+	VoteID        uuid.UUID              `gorm:"type:uuid;not null;uniqueIndex:idx_partial_decryptions_entry,priority:1;" json:"vote_id"`
+	TrusteeNumber int                    `gorm:"not null;uniqueIndex:idx_partial_decryptions_entry,priority:2;" json:"trustee"`
+	QuestionID    uint64                 `gorm:"not null;uniqueIndex:idx_partial_decryptions_entry,priority:3;" json:"question_id"`
+	CandidateID   uint64                 `gorm:"not null;uniqueIndex:idx_partial_decryptions_entry,priority:4;" json:"candidate_id"`
+	Share         *elgamal.Int           `gorm:"type:text;serializer:json;not null;" json:"share"`
+	Proof         *elgamal.EqualityProof `gorm:"type:jsonb;serializer:json;not null;" json:"proof"`
+	CreatedAt     time.Time              `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+// TrusteeSetup 設定加密投票的受託人
+type TrusteeSetup struct {
+	// 還原選舉私鑰所需的受託人數量
+	Threshold int      `json:"threshold" binding:"required,min=1" example:"2"`
+	Names     []string `json:"names" binding:"required,min=1,max=32,dive,required,max=100" example:"alice,bob,carol"`
+}
+
+// TrusteeCreated 新建立的受託人與登入權杖，權杖只會回傳這一次
+type TrusteeCreated struct {
+	Number int    `json:"number"`
+	Name   string `json:"name"`
+	Token  string `json:"token"`
+}
+
+// TrusteeKey 受託人上傳的傳輸公鑰
+type TrusteeKey struct {
+	TransportKey *elgamal.Int `json:"transport_key" binding:"required"`
+}
+
+// TrusteeDeal 受託人公布的承諾與發給每位受託人（含自己）的加密分片，鍵為接收者編號
+type TrusteeDeal struct {
+	Commitments []*elgamal.Int               `json:"commitments" binding:"required"`
+	Shares      map[int]*elgamal.SealedShare `json:"shares" binding:"required"`
+}
+
+// TrusteeDecrypt 受託人送出的部分解密，需涵蓋所有合計密文
+type TrusteeDecrypt struct {
+	Partials []PartialDecryptionUpload `json:"partials" binding:"required"`
+}
+
+// PartialDecryptionUpload 單一合計密文的部分解密
+type PartialDecryptionUpload struct {
+	QuestionID  uint64                 `json:"question_id"`
+	CandidateID uint64                 `json:"candidate_id"`
+	Share       *elgamal.Int           `json:"share"`
+	Proof       *elgamal.EqualityProof `json:"proof"`
+}
+
+// EncryptedTally 某候選人所有選票密文相乘的結果，解密後即為得票數
+type EncryptedTally struct {
+	QuestionID  uint64              `json:"question_id"`
+	CandidateID uint64              `json:"candidate_id"`
+	Ciphertext  *elgamal.Ciphertext `json:"ciphertext"`
+	// 此問題的選票數，解密結果不會超過此數
+	Ballots int64 `json:"ballots"`
+}
+
+// TrusteeCeremony 受託人目前可見的金鑰產生與解密進度
+type TrusteeCeremony struct {
+	VoteID    uuid.UUID    `json:"vote_id"`
+	Status    string       `json:"status"`
+	Number    int          `json:"number"`
+	Threshold int          `json:"threshold"`
+	PublicKey *elgamal.Int `json:"public_key"`
+	Trustees  []Trustee    `json:"trustees"`
+	// 發給此受託人的加密分片
+	Shares []TrusteeShare `json:"shares"`
+	// 投票結束且寬限期過後才提供
+	Tally []EncryptedTally `json:"tally"`
+}
@@ -2,6 +2,8 @@ package model
 
 import (
 	"time"
+	"vote/app/elgamal"
+	"vote/app/enum"
 
 	"github.com/google/uuid"
 )
@@ -18,7 +20,20 @@ type Vote struct {
 	StartTime   time.Time  `gorm:"not null;" json:"start_time"`
 	EndTime     time.Time  `gorm:"not null;" json:"end_time"`
 	UserID      uint64     `gorm:"index;not null;" json:"user_id"`
-	Status      int        `gorm:"default:0;not null;" json:"status"`
+	Status      enum.VoteStatus `gorm:"default:0;not null;" json:"status"`
+	TieBreak    enum.TieBreak `gorm:"size:20;not null;default:backward;" json:"tie_break"`
+	GracePeriod int        `gorm:"not null;default:0;" json:"grace_period"`
+	// 實際結束投票的時間，提前結束時早於 EndTime，寬限期由此起算
+	ClosedAt    *time.Time `json:"closed_at"`
+	// 加密投票模式：選票以選舉公鑰加密，開票需由受託人門檻解密
+	Encrypted   bool       `gorm:"not null;default:false;" json:"encrypted"`
+	Threshold   int        `gorm:"not null;default:0;" json:"threshold"`
+	// 受託人完成金鑰產生後的選舉公鑰
+	PublicKey   *elgamal.Int `gorm:"type:text;serializer:json;" json:"public_key"`
+	// 開放重新投票：投票結束前可再次送出選票，只計入每組密碼最後一次的選票
+	AllowRevote bool       `gorm:"not null;default:false;" json:"allow_revote"`
+	// 投票進行中即時推送各候選人目前的票數，投票率不受此設定影響
+	LiveResults bool       `gorm:"not null;default:false;" json:"live_results"`
 	CreatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
 	UpdatedAt   time.Time  `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
 	Questions   []Question `gorm:"foreignKey:VoteID;references:Uuid;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"questions,omitempty"`
@@ -31,6 +46,13 @@ type VoteCreate struct {
 	UserID      uint64    `json:"user_id" example:"1"`
 	StartTime   time.Time `json:"start_time" binding:"required" example:"2006-01-02 15:04:05"`
 	EndTime     time.Time `json:"end_time" binding:"required" example:"2006-01-02 15:04:05"`
+	TieBreak    enum.TieBreak `json:"tie_break" binding:"omitempty,oneof=backward forward candidate seeded" example:"backward"`
+	// 投票結束後仍接受結束前已登入者送出選票的秒數
+	GracePeriod int       `json:"grace_period" binding:"omitempty,min=0,max=3600" example:"300"`
+	// 開放重新投票，只計入每組密碼最後一次的選票
+	AllowRevote bool      `json:"allow_revote" example:"false"`
+	// 投票進行中即時推送各候選人目前的票數
+	LiveResults bool      `json:"live_results" example:"false"`
 }
 
 type VoteUpdate struct {
@@ -39,6 +61,13 @@ type VoteUpdate struct {
 	UserID      uint64    `json:"user_id" example:"1"`
 	StartTime   time.Time `json:"start_time" binding:"required" example:"2006-01-02 15:04:05"`
 	EndTime     time.Time `json:"end_time" binding:"required" example:"2006-01-02 15:04:05"`
+	TieBreak    enum.TieBreak `json:"tie_break" binding:"omitempty,oneof=backward forward candidate seeded" example:"backward"`
+	// 投票結束後仍接受結束前已登入者送出選票的秒數
+	GracePeriod int       `json:"grace_period" binding:"omitempty,min=0,max=3600" example:"300"`
+	// 開放重新投票，只能在草稿狀態變更
+	AllowRevote *bool     `json:"allow_revote" example:"false"`
+	// 投票進行中即時推送各候選人目前的票數
+	LiveResults *bool     `json:"live_results" example:"false"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func (VoteReminder) TableName() string {
+	return "vote_reminders"
+}
+
+// VoteReminder 投票結束前寄給尚未投票者的提醒，執行後記錄寄送人數
+type VoteReminder struct {
+	ID     uint64    `gorm:"primary_key;auto_increment" json:"id"`
+	VoteID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_vote_reminders_vote_offset,priority:1;" json:"vote_id"`
+	// 投票結束前幾秒寄送
+	Offset int        `gorm:"column:offset_seconds;not null;uniqueIndex:idx_vote_reminders_vote_offset,priority:2;" json:"offset"`
+	RunAt  *time.Time `json:"run_at"`
+	// 排入寄送的人數
+	Recipients int `gorm:"not null;default:0;" json:"recipients"`
+	// 已投票或密碼已撤銷、過期而略過的人數
+	Skipped   int       `gorm:"not null;default:0;" json:"skipped"`
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+type VoteReminderSet struct {
+	// 投票結束前的時間，例如 24h、1h30m
+	Offsets []string `json:"offsets" binding:"max=10,dive,required" example:"24h,1h"`
+}
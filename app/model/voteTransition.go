@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+	"vote/app/enum"
+
+	"github.com/google/uuid"
+)
+
+func (VoteTransition) TableName() string {
+	return "vote_transitions"
+}
+
+// VoteTransition 投票狀態轉換紀錄，ActorID 為空表示由系統排程執行
+type VoteTransition struct {
+	ID         uint64          `gorm:"primary_key;auto_increment" json:"id"`
+	VoteID     uuid.UUID       `gorm:"type:uuid;index;not null;" json:"vote_id"`
+	FromStatus enum.VoteStatus `gorm:"not null;" json:"from"`
+	ToStatus   enum.VoteStatus `gorm:"not null;" json:"to"`
+	ActorID    *uint64         `gorm:"index;" json:"actor_id"`
+	CreatedAt  time.Time       `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+}
+
+type VoteTransitionCreate struct {
+	Status *enum.VoteStatus `json:"status" binding:"required" example:"scheduled"`
+}
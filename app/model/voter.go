@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+	"vote/app/enum"
+
+	"github.com/google/uuid"
+)
+
+func (Voter) TableName() string {
+	return "voters"
+}
+
+// Voter 投票者名冊，記錄每位投票者收到的密碼。
+// 名冊只連結到密碼，不連結到選票，避免從名冊追查投票內容。
+type Voter struct {
+	ID             uint64              `gorm:"primary_key;auto_increment" json:"id"`
+	VoteID         uuid.UUID           `gorm:"type:uuid;index;not null;" json:"vote_id"`
+	Name           string              `gorm:"size:100;not null;" json:"name"`
+	Email          string              `gorm:"size:100;not null;" json:"email"`
+	ExternalID     string              `gorm:"size:100;" json:"external_id"`
+	Weight         int                 `gorm:"not null;default:1;" json:"weight"`
+	PasswordID     uint64              `gorm:"uniqueIndex;not null;" json:"password_id"`
+	DeliveryStatus enum.DeliveryStatus `gorm:"size:20;not null;default:pending;" json:"delivery_status"`
+	DeliveryError  string              `gorm:"size:255;" json:"delivery_error"`
+	SentAt         *time.Time          `json:"sent_at"`
+	CreatedAt      time.Time           `gorm:"default:CURRENT_TIMESTAMP" json:"created_at"`
+	UpdatedAt      time.Time           `gorm:"default:CURRENT_TIMESTAMP" json:"updated_at"`
+	Password       *Password           `gorm:"foreignKey:PasswordID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+type VoterCreate struct {
+	Name       string `json:"name" binding:"required,max=100" example:"name"`
+	Email      string `json:"email" binding:"required,email,max=100" example:"voter@example.com"`
+	ExternalID string `json:"external_id" binding:"max=100" example:"A001"`
+	Weight     int    `json:"weight" binding:"omitempty,min=1" example:"1"`
+}
+
+type VoterRollCreate struct {
+	Voters []VoterCreate `json:"voters" binding:"required,min=1,dive"`
+	Length int           `json:"length" binding:"required,min=6" example:"8"`
+	Format string        `json:"format" binding:"required,oneof=int en mix mixExcl mixLower mixUpper" example:"mixExcl"`
+	Active bool          `json:"active" example:"true"`
+}
+
+type VoterReissue struct {
+	Reason string `json:"reason" binding:"max=255" example:"lost"`
+}
@@ -19,7 +19,7 @@ func NewVoteRepository() VoteRepository {
 func (v VoteRepository) GetVoteByUUID(uuid uuid.UUID) (*model.Vote, error) {
 	voteOne := &model.Vote{}
 	err := database.SqlSession.
-		Select([]string{"id", "title", "description", "user_id", "start_time", "end_time"}).
+		Select([]string{"id", "uuid", "title", "description", "user_id", "start_time", "end_time", "status", "tie_break", "grace_period", "closed_at", "encrypted", "threshold", "public_key", "allow_revote", "live_results"}).
 		Where("uuid=?", uuid).
 		First(&voteOne).Error
 
@@ -64,6 +64,10 @@ func (v VoteRepository) CreateVote(form model.VoteCreate) (*model.Vote, error) {
 		UserID:      form.UserID,
 		StartTime:   form.StartTime,
 		EndTime:     form.EndTime,
+		TieBreak:    form.TieBreak,
+		GracePeriod: form.GracePeriod,
+		AllowRevote: form.AllowRevote,
+		LiveResults: form.LiveResults,
 	}
 
 	insertErr := database.SqlSession.Create(&vote).Error
@@ -1,8 +1,21 @@
 package service
 
 import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 	"vote/app/database"
+	"vote/app/enum"
 	"vote/app/model"
+	"vote/app/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BallotService struct {
@@ -12,47 +25,238 @@ func NewBallotService() BallotService {
 	return BallotService{}
 }
 
-// CreateBallots 建立投票
-func (b BallotService) CreateBallots(voter uint64, selectedCandidates map[uint64]map[uint64]bool) error {
+// BallotFieldError 選票中單一欄位的錯誤
+type BallotFieldError struct {
+	QuestionID  uint64 `json:"question_id"`
+	CandidateID uint64 `json:"candidate_id,omitempty"`
+	Message     string `json:"message"`
+}
+
+// BallotError 選票內容不符合問題的投票規則，列出所有欄位的錯誤
+type BallotError struct {
+	Errors []BallotFieldError
+}
+
+func (e *BallotError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, fieldError := range e.Errors {
+		if fieldError.CandidateID != 0 {
+			messages = append(messages, fmt.Sprintf("question %d candidate %d: %s", fieldError.QuestionID, fieldError.CandidateID, fieldError.Message))
+			continue
+		}
+		messages = append(messages, fmt.Sprintf("question %d: %s", fieldError.QuestionID, fieldError.Message))
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// CreateBallots 建立投票並回傳投票回條，選票中任一問題不符合規則時整張選票都不會寫入
+func (b BallotService) CreateBallots(voter uint64, vote model.Vote, selections model.BallotSelections, idempotency *BallotIdempotency) (*model.BallotReceipt, error) {
+	voteId := vote.Uuid
+	questions, err := b.selectQuestions(sortedQuestionIds(selections))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateBallot(voteId, questions, selections); err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	receipt := &model.BallotReceipt{
+		VoteID: voteId,
+		Code:   BallotReceiptCode(voteId, selections, nonce),
+	}
+
+	// 依問題ID排序，讓寫入順序固定
+	ballots := make([]model.Ballot, 0, len(selections))
+	for _, questionId := range sortedQuestionIds(selections) {
+		question := questions[questionId]
+		ballot := model.Ballot{QuestionID: questionId}
+		for _, cid := range markedCandidates(question, selections[questionId]) {
+			ballotSelect := model.BallotSelect{
+				CandidateID: cid,
+				Value:       int(selections[questionId][cid]),
+			}
+			if question.Method.IsRanked() {
+				ballotSelect.Rank = int(selections[questionId][cid])
+			}
+			ballot.BallotSelects = append(ballot.BallotSelects, ballotSelect)
+		}
+		ballots = append(ballots, ballot)
+	}
+
+	return b.storeBallots(voter, vote, receipt, ballots, idempotency)
+}
 
+// storeBallots 在同一個交易中標記密碼已投票，並寫入回條、選票、選項與雜湊鏈。
+// 密碼已投票時，以相同冪等鍵重送的請求回傳原本的回條；投票開放重新投票時，這次的選票取代先前的選票。
+//
+// 選票不記錄密碼且時間只保留到小時，但仍與密碼狀態、已投票紀錄在同一個交易中寫入，
+// 以免密碼被標記已投票卻沒有選票。PostgreSQL 的 xmin 系統欄位記錄寫入每一列的交易，
+// 能直接查詢資料庫的人（在 VACUUM FREEZE 之前）可藉此將選票對應回密碼；
+// 匿名性只對 API 與匯出的資料成立，不對資料庫管理者成立。
+func (b BallotService) storeBallots(voter uint64, vote model.Vote, receipt *model.BallotReceipt, ballots []model.Ballot, idempotency *BallotIdempotency) (*model.BallotReceipt, error) {
+	now := time.Now()
 	transaction := database.SqlSession.Begin()
-	for questionId, candidates := range selectedCandidates {
-		ballot := model.Ballot{
-			PasswordID: voter,
-			QuestionID: questionId,
+	if err := lockBallotVote(transaction, vote.Uuid); err != nil {
+		transaction.Rollback()
+		return nil, err
+	}
+	// 鎖定密碼並標記為已投票，撤銷、過期或已投票的密碼無法寫入選票
+	err := useCredential(transaction, voter, now)
+	var superseded *uint64
+	if errors.Is(err, ErrAlreadyVoted) {
+		// 同時送出的請求在鎖上等到先到者寫入後才會到這裡
+		previous, replayErr := previousBallot(transaction, voter, idempotency)
+		switch {
+		case replayErr == nil && previous != nil:
+			transaction.Rollback()
+			return previous, nil
+		case errors.Is(replayErr, ErrAlreadyVoted) && vote.AllowRevote:
+			superseded, err = supersedeBallot(transaction, voter, now)
+		case replayErr != nil:
+			err = replayErr
+		}
+	}
+	if err != nil {
+		transaction.Rollback()
+		return nil, err
+	}
+	if idempotency != nil {
+		sealed, err := idempotency.Seal(voter, receipt)
+		if err != nil {
+			transaction.Rollback()
+			return nil, err
+		}
+		digest := idempotency.Digest(voter)
+		err = transaction.Model(&model.Participation{PasswordID: voter}).
+			Updates(model.Participation{IdempotencyDigest: &digest, SealedReceipt: sealed}).Error
+		if err != nil {
+			transaction.Rollback()
+			return nil, err
+		}
+	}
+	if vote.AllowRevote {
+		digest, err := supersedeDigest(voter)
+		if err != nil {
+			transaction.Rollback()
+			return nil, err
 		}
-		err := transaction.Create(&ballot).Error
+		receipt.SupersedeDigest = &digest
+	}
+	// 選票以回條與雜湊鏈對應，回條的時間也只保留到小時
+	receipt.CreatedAt = coarseTime(now)
+	if err := transaction.Create(receipt).Error; err != nil {
+		transaction.Rollback()
+		return nil, err
+	}
+	for i := range ballots {
+		selects := ballots[i].BallotSelects
+		// 選票不記錄密碼，時間只保留到小時
+		ballots[i].BallotSelects = nil
+		ballots[i].ReceiptID = &receipt.ID
+		ballots[i].SupersedesID = superseded
+		ballots[i].CreatedAt = coarseTime(now)
+		ballots[i].UpdatedAt = coarseTime(now)
+		err := transaction.Create(&ballots[i]).Error
 		if err != nil {
 			transaction.Rollback()
-			return err
+			return nil, err
 		}
 
-		for cid := range candidates {
-			ballotSelect := model.BallotSelect{
-				BallotID:    ballot.ID,
-				CandidateID: cid,
-			}
-			err = transaction.Create(&ballotSelect).Error
+		for j := range selects {
+			selects[j].BallotID = ballots[i].ID
+			err = transaction.Create(&selects[j]).Error
 			if err != nil {
 				transaction.Rollback()
-				return err
+				return nil, err
 			}
 		}
+		ballots[i].BallotSelects = selects
+	}
+
+	// 將選票寫入雜湊鏈，之後對選票或標記的修改都能被驗證出來
+	if err := NewLedgerService().Append(transaction, vote.Uuid, ballots); err != nil {
+		transaction.Rollback()
+		return nil, err
 	}
 
-	err := transaction.Commit().Error
+	if err := transaction.Commit().Error; err != nil {
+		return nil, err
+	}
+	NotifyLiveResult(vote.Uuid)
+
+	return receipt, nil
+}
 
+// lockBallotVote 以共用鎖鎖定投票並確認尚未開票。
+// 開票持有排他鎖，會等送出中的選票寫入後才讀取選票，開票後送出的選票則被拒絕。
+func lockBallotVote(tx *gorm.DB, voteId uuid.UUID) error {
+	vote := &model.Vote{}
+	err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+		Select("id", "status").
+		Where("uuid = ?", voteId).
+		First(vote).Error
 	if err != nil {
 		return err
 	}
+	if vote.Status > enum.Closed {
+		return ErrVoteClosed
+	}
 
 	return nil
 }
 
-// CheckIfVoterHasVoted 檢查投票者是否已經投票
+// supersedeDigest 開放重新投票時回條上的密碼摘要，沒有 APP_HMAC_KEY 無法由密碼算出
+func supersedeDigest(voter uint64) (string, error) {
+	return (&utils.Password{}).Digest("supersede", strconv.FormatUint(voter, 10))
+}
+
+// supersedeBallot 重新投票時將密碼目前有效的回條標記為已取代，其選票不再計入開票，回傳被取代的回條 ID。
+// 被取代的回條會清除密碼摘要，之後無法再對應回密碼；已投票紀錄的時間與冪等鍵改為這次投票的。
+// 新的選票記錄被取代的回條並寫入雜湊鏈，驗證時可發現沒有對應選票的取代標記。
+func supersedeBallot(tx *gorm.DB, voter uint64, now time.Time) (*uint64, error) {
+	digest, err := supersedeDigest(voter)
+	if err != nil {
+		return nil, err
+	}
+
+	var receipts []model.BallotReceipt
+	if err := tx.Select("id").Where("supersede_digest = ?", digest).Limit(1).Find(&receipts).Error; err != nil {
+		return nil, err
+	}
+	// 開放重新投票前送出的選票沒有摘要，無法取代
+	if len(receipts) == 0 {
+		return nil, ErrAlreadyVoted
+	}
+
+	err = tx.Model(&model.BallotReceipt{ID: receipts[0].ID}).
+		Updates(map[string]any{"superseded": true, "supersede_digest": nil}).Error
+	if err != nil {
+		return nil, err
+	}
+	err = tx.Model(&model.Participation{PasswordID: voter}).
+		Updates(map[string]any{"voted_at": coarseTime(now), "idempotency_digest": nil, "sealed_receipt": nil}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &receipts[0].ID, nil
+}
+
+// countedBallots 排除重新投票後已被取代的選票，沒有回條的舊選票照常計入
+func countedBallots(db *gorm.DB) *gorm.DB {
+	return db.Where("ballots.receipt_id IS NULL OR NOT EXISTS (SELECT 1 FROM ballot_receipts WHERE ballot_receipts.id = ballots.receipt_id AND ballot_receipts.superseded)")
+}
+
+// CheckIfVoterHasVoted 依已投票紀錄檢查投票者是否已經投票
 func (b BallotService) CheckIfVoterHasVoted(voterId uint64) (bool, error) {
 	var count int64
-	err := database.SqlSession.Model(&model.Ballot{}).
+	err := database.SqlSession.Model(&model.Participation{}).
 		Where("password_id = ?", voterId).
 		Count(&count).Error
 
@@ -69,3 +273,168 @@ func (b BallotService) GetBallotByVoterId(voterId uint64) ([][]string) {
 
 	return make([][]string, 1)
 }
+
+// coarseTime 將時間截斷到小時，選票與已投票紀錄無法依時間互相對應
+func coarseTime(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Hour)
+}
+
+// selectQuestions 取得選票中所有問題及其候選人
+func (b BallotService) selectQuestions(questionIds []uint64) (map[uint64]model.Question, error) {
+	var questions []model.Question
+	err := database.SqlSession.
+		Where("id IN ?", questionIds).
+		Preload("Candidates").
+		Find(&questions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[uint64]model.Question, len(questions))
+	for _, question := range questions {
+		result[question.ID] = question
+	}
+
+	return result, nil
+}
+
+// ValidateBallot 檢查整張選票：問題必須屬於投票者的投票場次、候選人必須屬於該問題，
+// 且標記與選擇數量須符合問題的規則。回傳的 BallotError 包含所有不符合的欄位。
+func ValidateBallot(voteId uuid.UUID, questions map[uint64]model.Question, selections model.BallotSelections) error {
+	var errs []BallotFieldError
+	for _, questionId := range sortedQuestionIds(selections) {
+		question, ok := questions[questionId]
+		if !ok || question.VoteID != voteId {
+			errs = append(errs, BallotFieldError{QuestionID: questionId, Message: "question not found in this vote"})
+			continue
+		}
+
+		marks := selections[questionId]
+		candidateErrs := false
+		for _, cid := range sortedCandidateIds(marks) {
+			if !hasCandidate(question, cid) {
+				errs = append(errs, BallotFieldError{QuestionID: questionId, CandidateID: cid, Message: "candidate does not belong to this question"})
+				candidateErrs = true
+			}
+		}
+		if candidateErrs {
+			continue
+		}
+
+		errs = append(errs, validateMarks(question, marks)...)
+	}
+
+	if len(errs) > 0 {
+		return &BallotError{Errors: errs}
+	}
+
+	return nil
+}
+
+// validateMarks 依問題的投票方式檢查標記。
+// 勾選制與認可制只接受 0 或 1；排序制的順位必須從 1 開始連續且不重複；
+// 評分制的分數必須介於 0 與最高分之間。
+// 有選擇時，選擇數量須介於問題的最少與最多選擇數之間，未選擇任何候選人視為空白票。
+func validateMarks(question model.Question, marks map[uint64]model.BallotMark) []BallotFieldError {
+	var errs []BallotFieldError
+	selected := 0
+	switch {
+	case question.Method.IsRanked():
+		var ranks []int
+		for _, cid := range sortedCandidateIds(marks) {
+			if marks[cid] < 0 {
+				errs = append(errs, BallotFieldError{QuestionID: question.ID, CandidateID: cid, Message: "rank must be a positive integer"})
+			}
+			if marks[cid] > 0 {
+				ranks = append(ranks, int(marks[cid]))
+			}
+		}
+		sort.Ints(ranks)
+		for i, rank := range ranks {
+			if rank != i+1 {
+				errs = append(errs, BallotFieldError{QuestionID: question.ID, Message: "ranks must be unique and contiguous starting from 1"})
+				break
+			}
+		}
+		selected = len(ranks)
+	case question.Method == enum.Score:
+		for _, cid := range sortedCandidateIds(marks) {
+			if marks[cid] < 0 || int(marks[cid]) > question.MaxScore {
+				errs = append(errs, BallotFieldError{QuestionID: question.ID, CandidateID: cid, Message: fmt.Sprintf("score must be between 0 and %d", question.MaxScore)})
+			}
+			if marks[cid] > 0 {
+				selected++
+			}
+		}
+	default:
+		for _, cid := range sortedCandidateIds(marks) {
+			if marks[cid] != 0 && marks[cid] != 1 {
+				errs = append(errs, BallotFieldError{QuestionID: question.ID, CandidateID: cid, Message: "selection must be true or false"})
+			}
+			if marks[cid] != 0 {
+				selected++
+			}
+		}
+	}
+
+	if selected == 0 {
+		return errs
+	}
+	if minSelections := question.MinSelections; selected < minSelections {
+		errs = append(errs, BallotFieldError{QuestionID: question.ID, Message: fmt.Sprintf("at least %d selections required", minSelections)})
+	}
+	if maxSelections := maxSelections(question); maxSelections > 0 && selected > maxSelections {
+		errs = append(errs, BallotFieldError{QuestionID: question.ID, Message: fmt.Sprintf("at most %d selections allowed", maxSelections)})
+	}
+
+	return errs
+}
+
+// maxSelections 回傳問題最多可選擇的數量，0 表示不限制。
+// 未設定時，勾選制最多可勾選與席次相同的數量。
+func maxSelections(question model.Question) int {
+	if question.MaxSelections > 0 {
+		return question.MaxSelections
+	}
+	if question.Method == enum.Plurality {
+		return max(question.Seats, 1)
+	}
+
+	return 0
+}
+
+// sortedQuestionIds 回傳選票中的問題ID，依ID排序
+func sortedQuestionIds(selections model.BallotSelections) []uint64 {
+	questionIds := make([]uint64, 0, len(selections))
+	for questionId := range selections {
+		questionIds = append(questionIds, questionId)
+	}
+	sort.Slice(questionIds, func(i, j int) bool { return questionIds[i] < questionIds[j] })
+
+	return questionIds
+}
+
+// sortedCandidateIds 回傳標記中的候選人ID，依ID排序
+func sortedCandidateIds(marks map[uint64]model.BallotMark) []uint64 {
+	candidateIds := make([]uint64, 0, len(marks))
+	for cid := range marks {
+		candidateIds = append(candidateIds, cid)
+	}
+	sort.Slice(candidateIds, func(i, j int) bool { return candidateIds[i] < candidateIds[j] })
+
+	return candidateIds
+}
+
+// markedCandidates 回傳有標記的候選人ID，依ID排序。
+// 評分制的 0 分也是有效的評分，因此會一併保留。
+func markedCandidates(question model.Question, marks map[uint64]model.BallotMark) []uint64 {
+	candidateIds := make([]uint64, 0, len(marks))
+	for cid, mark := range marks {
+		if mark != 0 || question.Method == enum.Score {
+			candidateIds = append(candidateIds, cid)
+		}
+	}
+	sort.Slice(candidateIds, func(i, j int) bool { return candidateIds[i] < candidateIds[j] })
+
+	return candidateIds
+}
@@ -63,6 +63,18 @@ func (c CandidateService) SelectAllCandidates(voteId uuid.UUID, isAdmin bool, us
 
 // CreateOneCandidate 創建新的候選人。
 func (c CandidateService) CreateCandidate(form model.CandidateCreate) (model.Candidate, error) {
+	vote := model.Vote{}
+	err := database.SqlSession.
+		Joins("JOIN questions ON questions.vote_id = votes.uuid").
+		Where("questions.id = ?", form.QuestionID).
+		First(&vote).Error
+	if err != nil {
+		return model.Candidate{}, err
+	}
+	if err := CheckVoteEditable(vote); err != nil {
+		return model.Candidate{}, err
+	}
+
 	candidate := model.Candidate{
 		QuestionID: form.QuestionID,
 		Name:       form.Name,
@@ -0,0 +1,377 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/model"
+	"vote/app/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrCredentialUnavailable 密碼已撤銷、過期或尚未啟用，不能送出選票
+	ErrCredentialUnavailable = errors.New("credential is not available for voting")
+	// ErrCredentialUsed 密碼已投票，不能撤銷或重新發放
+	ErrCredentialUsed = errors.New("credential has already been used to vote")
+)
+
+// credentialChange 一次密碼狀態變更
+type credentialChange struct {
+	action  enum.CredentialAction
+	to      enum.CredentialState
+	actorId *uint64
+	reason  string
+}
+
+// credentialState 新密碼的初始狀態
+func credentialState(active bool) enum.CredentialState {
+	if active {
+		return enum.CredentialActivated
+	}
+
+	return enum.CredentialIssued
+}
+
+// lockPasswords 鎖定投票中的密碼，任一 ID 不存在時回傳錯誤
+func lockPasswords(tx *gorm.DB, voteId uuid.UUID, ids []uint64) ([]model.Password, error) {
+	var passwords []model.Password
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("vote_id = ? AND id IN ?", voteId, ids).
+		Order("id ASC").
+		Find(&passwords).Error
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[uint64]struct{}, len(passwords))
+	for _, password := range passwords {
+		found[password.ID] = struct{}{}
+	}
+	for _, id := range ids {
+		if _, ok := found[id]; !ok {
+			return nil, fmt.Errorf("password %d not found in this vote: %w", id, gorm.ErrRecordNotFound)
+		}
+	}
+
+	return passwords, nil
+}
+
+// changeCredentialState 變更已鎖定密碼的狀態並寫入稽核紀錄，Status 會與新狀態同步
+func changeCredentialState(tx *gorm.DB, passwords []model.Password, change credentialChange) error {
+	if len(passwords) == 0 {
+		return nil
+	}
+
+	ids := make([]uint64, len(passwords))
+	for i, password := range passwords {
+		if !password.State.CanTransitionTo(change.to) {
+			return fmt.Errorf("password %d cannot change from %s to %s", password.ID, password.State, change.to)
+		}
+		ids[i] = password.ID
+	}
+
+	err := tx.Model(&model.Password{}).
+		Where("id IN ?", ids).
+		Updates(map[string]any{"state": change.to, "status": change.to.CanLogin()}).Error
+	if err != nil {
+		return err
+	}
+
+	audits := make([]model.CredentialAudit, len(passwords))
+	for i, password := range passwords {
+		audits[i] = model.CredentialAudit{
+			VoteID:     password.VoteID,
+			PasswordID: password.ID,
+			Action:     change.action,
+			FromState:  password.State,
+			ToState:    change.to,
+			ActorID:    change.actorId,
+			Reason:     change.reason,
+		}
+	}
+
+	return writeCredentialAudits(tx, audits)
+}
+
+// writeCredentialAudits 寫入稽核紀錄，並帶入密碼目前所屬的名冊投票者
+func writeCredentialAudits(tx *gorm.DB, audits []model.CredentialAudit) error {
+	ids := make([]uint64, len(audits))
+	for i, audit := range audits {
+		ids[i] = audit.PasswordID
+	}
+
+	var voters []model.Voter
+	if err := tx.Select([]string{"id", "password_id"}).Where("password_id IN ?", ids).Find(&voters).Error; err != nil {
+		return err
+	}
+	voterIds := make(map[uint64]uint64, len(voters))
+	for _, voter := range voters {
+		voterIds[voter.PasswordID] = voter.ID
+	}
+	for i := range audits {
+		if voterId, ok := voterIds[audits[i].PasswordID]; ok && audits[i].VoterID == nil {
+			audits[i].VoterID = &voterId
+		}
+	}
+
+	return tx.CreateInBatches(&audits, 500).Error
+}
+
+// RevokePasswords 撤銷投票中的密碼，已投票的密碼不能撤銷
+func (p PasswordService) RevokePasswords(voteId uuid.UUID, ids []uint64, actorId *uint64, reason string) error {
+	return database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		passwords, err := lockPasswords(tx, voteId, ids)
+		if err != nil {
+			return err
+		}
+		for _, password := range passwords {
+			if password.State == enum.CredentialUsed {
+				return fmt.Errorf("password %d: %w", password.ID, ErrCredentialUsed)
+			}
+		}
+
+		return changeCredentialState(tx, passwords, credentialChange{
+			action:  enum.CredentialRevoke,
+			to:      enum.CredentialRevoked,
+			actorId: actorId,
+			reason:  reason,
+		})
+	})
+}
+
+// SetPasswordExpiry 設定或取消密碼的過期時間，只適用於尚未投票且未撤銷的密碼
+func (p PasswordService) SetPasswordExpiry(voteId uuid.UUID, ids []uint64, expiresAt *time.Time, actorId *uint64) error {
+	return database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		passwords, err := lockPasswords(tx, voteId, ids)
+		if err != nil {
+			return err
+		}
+
+		audits := make([]model.CredentialAudit, len(passwords))
+		for i, password := range passwords {
+			if password.State != enum.CredentialIssued && password.State != enum.CredentialActivated {
+				return fmt.Errorf("password %d is %s", password.ID, password.State)
+			}
+			reason := "no expiry"
+			if expiresAt != nil {
+				reason = "expires at " + expiresAt.UTC().Format(time.RFC3339)
+			}
+			audits[i] = model.CredentialAudit{
+				VoteID:     password.VoteID,
+				PasswordID: password.ID,
+				Action:     enum.CredentialSetExpiry,
+				FromState:  password.State,
+				ToState:    password.State,
+				ActorID:    actorId,
+				Reason:     reason,
+			}
+		}
+
+		err = tx.Model(&model.Password{}).Where("id IN ?", ids).Update("expires_at", expiresAt).Error
+		if err != nil {
+			return err
+		}
+
+		return writeCredentialAudits(tx, audits)
+	})
+}
+
+// ReissueCredential 撤銷名冊投票者目前的密碼並發放新密碼。
+// 舊密碼與選票寫入時都會鎖定同一筆密碼，已投票的投票者不會再取得新密碼，
+// 因此每位名冊投票者最多只有一張選票。
+func (p PasswordService) ReissueCredential(voteId uuid.UUID, voterId uint64, actorId *uint64, reason string) (*model.Voter, error) {
+	voter := &model.Voter{}
+	err := database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("vote_id = ? AND id = ?", voteId, voterId).
+			First(voter).Error
+		if err != nil {
+			return err
+		}
+
+		olds, err := lockPasswords(tx, voteId, []uint64{voter.PasswordID})
+		if err != nil {
+			return err
+		}
+		old := olds[0]
+
+		var participations int64
+		if err := tx.Model(&model.Participation{}).Where("password_id = ?", old.ID).Count(&participations).Error; err != nil {
+			return err
+		}
+		if old.State == enum.CredentialUsed || participations > 0 {
+			return ErrCredentialUsed
+		}
+
+		if old.State != enum.CredentialRevoked {
+			err = changeCredentialState(tx, olds, credentialChange{
+				action:  enum.CredentialRevoke,
+				to:      enum.CredentialRevoked,
+				actorId: actorId,
+				reason:  reason,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		passwords, err := p.generatePasswords(voteId, 1, passwordLength(old), "mixExcl")
+		if err != nil {
+			return err
+		}
+		replacement := passwords[0]
+		replacement.State = credentialState(old.State == enum.CredentialActivated)
+		replacement.Status = replacement.State.CanLogin()
+		replacement.ExpiresAt = old.ExpiresAt
+		if err := tx.Create(&replacement).Error; err != nil {
+			return err
+		}
+
+		err = tx.Model(voter).Updates(map[string]any{
+			"password_id":     replacement.ID,
+			"delivery_status": enum.DeliveryPending,
+			"delivery_error":  "",
+			"sent_at":         nil,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return writeCredentialAudits(tx, []model.CredentialAudit{{
+			VoteID:     voteId,
+			PasswordID: replacement.ID,
+			VoterID:    &voter.ID,
+			Action:     enum.CredentialReissue,
+			ToState:    replacement.State,
+			ActorID:    actorId,
+			Reason:     fmt.Sprintf("replaces password %d", old.ID),
+		}})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return voter, database.SqlSession.First(voter, voter.ID).Error
+}
+
+// useCredential 在寫入選票的交易中鎖定密碼，確認可以投票後標記為已投票並寫入已投票紀錄。
+// 同一組密碼同時送出的請求會在鎖上排隊，後到者回傳 ErrAlreadyVoted；已投票紀錄以密碼為主鍵，資料庫也不會接受第二筆。
+// 為了不讓選票被對應回密碼，不寫入稽核紀錄，已投票紀錄的時間也只保留到小時。
+func useCredential(tx *gorm.DB, passwordId uint64, now time.Time) error {
+	password := model.Password{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&password, passwordId).Error
+	if err != nil {
+		return err
+	}
+	if password.State == enum.CredentialUsed {
+		return ErrAlreadyVoted
+	}
+	if password.State != enum.CredentialActivated || (password.ExpiresAt != nil && !password.ExpiresAt.After(now)) {
+		return ErrCredentialUnavailable
+	}
+
+	err = tx.Model(&password).Updates(map[string]any{"state": enum.CredentialUsed, "status": enum.CredentialUsed.CanLogin()}).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&model.Participation{
+		PasswordID: password.ID,
+		VoteID:     password.VoteID,
+		VotedAt:    coarseTime(now),
+	}).Error
+}
+
+// ExpireCredentials 將已超過過期時間且尚未投票的密碼標記為過期，回傳處理的數量
+func (p PasswordService) ExpireCredentials(now time.Time) (int, error) {
+	expired := 0
+	err := database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		var passwords []model.Password
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("state IN ? AND expires_at <= ?", []enum.CredentialState{enum.CredentialIssued, enum.CredentialActivated}, now).
+			Order("id ASC").
+			Limit(1000).
+			Find(&passwords).Error
+		if err != nil {
+			return err
+		}
+		expired = len(passwords)
+
+		return changeCredentialState(tx, passwords, credentialChange{
+			action: enum.CredentialExpire,
+			to:     enum.CredentialExpired,
+		})
+	})
+
+	return expired, err
+}
+
+// GetCredentialAudits 取得投票的密碼稽核紀錄，可依密碼或名冊投票者篩選
+func (p PasswordService) GetCredentialAudits(voteId uuid.UUID, query model.CredentialAuditQuery) ([]model.CredentialAudit, error) {
+	var audits []model.CredentialAudit
+	db := database.SqlSession.Where("vote_id = ?", voteId)
+	if query.PasswordID != 0 {
+		db = db.Where("password_id = ?", query.PasswordID)
+	}
+	if query.VoterID != 0 {
+		db = db.Where("voter_id = ?", query.VoterID)
+	}
+	err := db.Order("id ASC").Find(&audits).Error
+
+	return audits, err
+}
+
+// GetCredentialAuditStats 統計投票各狀態的密碼數、已投票人數，以及送出與被重新投票取代的次數
+func (p PasswordService) GetCredentialAuditStats(voteId uuid.UUID) (*model.CredentialAuditStats, error) {
+	stats := &model.CredentialAuditStats{
+		VoteID:      voteId,
+		Credentials: make(map[enum.CredentialState]int64),
+	}
+
+	var states []struct {
+		State enum.CredentialState
+		Count int64
+	}
+	err := database.SqlSession.Model(&model.Password{}).
+		Select("state, COUNT(*) AS count").
+		Where("vote_id = ?", voteId).
+		Group("state").
+		Scan(&states).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, state := range states {
+		stats.Credentials[state.State] = state.Count
+	}
+
+	err = database.SqlSession.Model(&model.Participation{}).Where("vote_id = ?", voteId).Count(&stats.Voted).Error
+	if err != nil {
+		return nil, err
+	}
+	err = database.SqlSession.Model(&model.BallotReceipt{}).Where("vote_id = ?", voteId).Count(&stats.Submissions).Error
+	if err != nil {
+		return nil, err
+	}
+	err = database.SqlSession.Model(&model.BallotReceipt{}).Where("vote_id = ? AND superseded", voteId).Count(&stats.Superseded).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// passwordLength 新密碼沿用舊密碼的長度
+func passwordLength(password model.Password) int {
+	plain, err := (&utils.Password{}).Decrypt(password.Password)
+	if err != nil || len(plain) < passwordMinLength {
+		return 8
+	}
+
+	return len(plain)
+}
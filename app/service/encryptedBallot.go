@@ -0,0 +1,265 @@
+package service
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"vote/app/database"
+	"vote/app/elgamal"
+	"vote/app/model"
+
+	"github.com/google/uuid"
+)
+
+// ErrVoteNotEncrypted 投票未使用加密模式，或選舉金鑰尚未產生
+var ErrVoteNotEncrypted = errors.New("vote does not accept encrypted ballots")
+
+// binaryValues 候選人密文允許的明文：未勾選或勾選
+var binaryValues = []int64{0, 1}
+
+// SelectionContext 候選人密文證明綁定的內容，證明無法搬到其他投票、問題、候選人或其他投票者的密碼。
+// voter 為送出選票的密碼 ID，投票端可由登入取得的 token 讀出。
+func SelectionContext(voteId uuid.UUID, voter uint64, questionId uint64, candidateId uint64) []byte {
+	return []byte(fmt.Sprintf("vote=%s;voter=%d;question=%d;candidate=%d", voteId, voter, questionId, candidateId))
+}
+
+// QuestionContext 問題選擇數量證明綁定的內容
+func QuestionContext(voteId uuid.UUID, voter uint64, questionId uint64) []byte {
+	return []byte(fmt.Sprintf("vote=%s;voter=%d;question=%d", voteId, voter, questionId))
+}
+
+// AllowedSelections 加密選票上問題允許的選擇數量：0 為空白票，其餘須在最少與最多選擇數之間
+func AllowedSelections(question model.Question) []int64 {
+	upper := maxSelections(question)
+	if upper == 0 || upper > len(question.Candidates) {
+		upper = len(question.Candidates)
+	}
+
+	values := []int64{0}
+	for count := max(question.MinSelections, 1); count <= upper; count++ {
+		values = append(values, int64(count))
+	}
+
+	return values
+}
+
+// EncryptQuestion 以選舉公鑰加密單一問題的選擇並產生證明，供投票端使用，voter 為送出選票的密碼 ID。
+// 每位候選人都有一個密文，未選擇的候選人加密 0，伺服器無法由密文數量得知選擇。
+func EncryptQuestion(publicKey *big.Int, voteId uuid.UUID, voter uint64, question model.Question, chosen []uint64, random io.Reader) (*model.EncryptedQuestion, error) {
+	if random == nil {
+		random = rand.Reader
+	}
+
+	marked := make(map[uint64]bool, len(chosen))
+	for _, cid := range chosen {
+		if !hasCandidate(question, cid) {
+			return nil, fmt.Errorf("candidate %d does not belong to question %d", cid, question.ID)
+		}
+		marked[cid] = true
+	}
+
+	candidates := append([]model.Candidate(nil), question.Candidates...)
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].ID < candidates[j].ID })
+
+	encrypted := &model.EncryptedQuestion{QuestionID: question.ID}
+	ciphertexts := make([]*elgamal.Ciphertext, 0, len(candidates))
+	nonce := new(big.Int)
+	for _, candidate := range candidates {
+		var m int64
+		if marked[candidate.ID] {
+			m = 1
+		}
+		ciphertext, r, err := elgamal.Encrypt(publicKey, m, random)
+		if err != nil {
+			return nil, err
+		}
+		proof, err := elgamal.ProveRange(publicKey, ciphertext, r, m, binaryValues, SelectionContext(voteId, voter, question.ID, candidate.ID), random)
+		if err != nil {
+			return nil, err
+		}
+		encrypted.Selections = append(encrypted.Selections, model.EncryptedSelection{
+			CandidateID: candidate.ID,
+			Ciphertext:  ciphertext,
+			Proof:       proof,
+		})
+		ciphertexts = append(ciphertexts, ciphertext)
+		nonce.Add(nonce, r)
+	}
+
+	// 密文相乘後的隨機數為各隨機數之和
+	nonce.Mod(nonce, elgamal.Q)
+	proof, err := elgamal.ProveRange(publicKey, elgamal.Sum(ciphertexts), nonce, int64(len(marked)), AllowedSelections(question), QuestionContext(voteId, voter, question.ID), random)
+	if err != nil {
+		return nil, fmt.Errorf("question %d: %w", question.ID, err)
+	}
+	encrypted.Proof = proof
+
+	return encrypted, nil
+}
+
+// ValidateEncryptedBallot 檢查加密選票：每個問題都要屬於投票且支援加密，每位候選人恰有一個密文，
+// 密文的明文為 0 或 1，且選擇數量符合問題的限制；證明須由 voter 這組密碼產生。
+// 回傳的 BallotError 包含所有不符合的欄位。
+func ValidateEncryptedBallot(vote model.Vote, voter uint64, questions map[uint64]model.Question, ballot model.EncryptedBallot) error {
+	if !vote.Encrypted || vote.PublicKey == nil {
+		return ErrVoteNotEncrypted
+	}
+	publicKey := vote.PublicKey.Big()
+
+	var errs []BallotFieldError
+	seenQuestions := make(map[uint64]bool, len(ballot.Questions))
+	for _, encrypted := range ballot.Questions {
+		questionId := encrypted.QuestionID
+		if seenQuestions[questionId] {
+			errs = append(errs, BallotFieldError{QuestionID: questionId, Message: "question appears more than once"})
+			continue
+		}
+		seenQuestions[questionId] = true
+
+		question, ok := questions[questionId]
+		if !ok || question.VoteID != vote.Uuid {
+			errs = append(errs, BallotFieldError{QuestionID: questionId, Message: "question not found in this vote"})
+			continue
+		}
+		if !question.Method.SupportsEncryption() {
+			errs = append(errs, BallotFieldError{QuestionID: questionId, Message: "voting method does not support encrypted ballots"})
+			continue
+		}
+
+		selectionErrs := false
+		seenCandidates := make(map[uint64]bool, len(encrypted.Selections))
+		ciphertexts := make([]*elgamal.Ciphertext, 0, len(encrypted.Selections))
+		for _, selection := range encrypted.Selections {
+			cid := selection.CandidateID
+			switch {
+			case !hasCandidate(question, cid):
+				errs = append(errs, BallotFieldError{QuestionID: questionId, CandidateID: cid, Message: "candidate does not belong to this question"})
+			case seenCandidates[cid]:
+				errs = append(errs, BallotFieldError{QuestionID: questionId, CandidateID: cid, Message: "candidate appears more than once"})
+			case !elgamal.VerifyRange(publicKey, selection.Ciphertext, binaryValues, SelectionContext(vote.Uuid, voter, questionId, cid), selection.Proof):
+				errs = append(errs, BallotFieldError{QuestionID: questionId, CandidateID: cid, Message: "invalid ciphertext or proof"})
+			default:
+				seenCandidates[cid] = true
+				ciphertexts = append(ciphertexts, selection.Ciphertext)
+				continue
+			}
+			selectionErrs = true
+		}
+		if selectionErrs {
+			continue
+		}
+		if len(seenCandidates) != len(question.Candidates) {
+			errs = append(errs, BallotFieldError{QuestionID: questionId, Message: "every candidate must have a ciphertext"})
+			continue
+		}
+
+		if !elgamal.VerifyRange(publicKey, elgamal.Sum(ciphertexts), AllowedSelections(question), QuestionContext(vote.Uuid, voter, questionId), encrypted.Proof) {
+			errs = append(errs, BallotFieldError{QuestionID: questionId, Message: "number of selections is out of range"})
+		}
+	}
+
+	if len(errs) > 0 {
+		return &BallotError{Errors: errs}
+	}
+
+	return nil
+}
+
+// CreateEncryptedBallots 建立加密選票並回傳投票回條，伺服器只保存密文與證明
+func (b BallotService) CreateEncryptedBallots(voter uint64, vote model.Vote, ballot model.EncryptedBallot, idempotency *BallotIdempotency) (*model.BallotReceipt, error) {
+	questionIds := make([]uint64, 0, len(ballot.Questions))
+	for _, encrypted := range ballot.Questions {
+		questionIds = append(questionIds, encrypted.QuestionID)
+	}
+	questions, err := b.selectQuestions(questionIds)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateEncryptedBallot(vote, voter, questions, ballot); err != nil {
+		return nil, err
+	}
+	if err := checkCopiedCiphertexts(vote.Uuid, ballot); err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, 32)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	receipt := &model.BallotReceipt{
+		VoteID: vote.Uuid,
+		Code:   EncryptedReceiptCode(vote.Uuid, ballot, nonce),
+	}
+
+	// 依問題與候選人ID排序，讓寫入順序固定
+	encryptedQuestions := append([]model.EncryptedQuestion(nil), ballot.Questions...)
+	sort.Slice(encryptedQuestions, func(i, j int) bool { return encryptedQuestions[i].QuestionID < encryptedQuestions[j].QuestionID })
+	ballots := make([]model.Ballot, 0, len(encryptedQuestions))
+	for _, encrypted := range encryptedQuestions {
+		selections := append([]model.EncryptedSelection(nil), encrypted.Selections...)
+		sort.Slice(selections, func(i, j int) bool { return selections[i].CandidateID < selections[j].CandidateID })
+
+		stored := model.Ballot{QuestionID: encrypted.QuestionID, Proof: encrypted.Proof}
+		for _, selection := range selections {
+			stored.BallotSelects = append(stored.BallotSelects, model.BallotSelect{
+				CandidateID: selection.CandidateID,
+				Ciphertext:  selection.Ciphertext,
+				Proof:       selection.Proof,
+			})
+		}
+		ballots = append(ballots, stored)
+	}
+
+	return b.storeBallots(voter, vote, receipt, ballots, idempotency)
+}
+
+// checkCopiedCiphertexts 拒絕與投票中計入開票的選票相同的密文。
+// 重新加密的密文不會重複，相同的密文代表選票是抄來的，抄錄他人的選票會由票數變化看出對方的選擇。
+func checkCopiedCiphertexts(voteId uuid.UUID, ballot model.EncryptedBallot) error {
+	submitted := make(map[string]BallotFieldError)
+	var parts []string
+	for _, encrypted := range ballot.Questions {
+		for _, selection := range encrypted.Selections {
+			a := selection.Ciphertext.A.String()
+			submitted[a+","+selection.Ciphertext.B.String()] = BallotFieldError{
+				QuestionID:  encrypted.QuestionID,
+				CandidateID: selection.CandidateID,
+				Message:     "ciphertext was already submitted in another ballot",
+			}
+			parts = append(parts, a)
+		}
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+
+	var stored []model.BallotSelect
+	err := database.SqlSession.
+		Select("ballot_selects.ciphertext").
+		Joins("JOIN ballots ON ballots.id = ballot_selects.ballot_id").
+		Joins("JOIN questions ON questions.id = ballots.question_id").
+		Where("questions.vote_id = ?", voteId).
+		Scopes(countedBallots).
+		Where("ballot_selects.ciphertext ->> 'a' IN ?", parts).
+		Find(&stored).Error
+	if err != nil {
+		return err
+	}
+
+	var errs []BallotFieldError
+	for _, ballotSelect := range stored {
+		key := ballotSelect.Ciphertext.A.String() + "," + ballotSelect.Ciphertext.B.String()
+		if fieldErr, ok := submitted[key]; ok {
+			errs = append(errs, fieldErr)
+		}
+	}
+	if len(errs) > 0 {
+		return &BallotError{Errors: errs}
+	}
+
+	return nil
+}
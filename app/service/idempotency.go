@@ -0,0 +1,144 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"vote/app/database"
+	"vote/app/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAlreadyVoted 密碼已投過票，且不是同一個冪等鍵的重送
+	ErrAlreadyVoted = errors.New("voter has already voted")
+	// ErrIdempotencyKeyInvalid 冪等鍵長度不符，過短的鍵可被暴力猜出而連結選票
+	ErrIdempotencyKeyInvalid = errors.New("Idempotency-Key must be 16 to 255 characters")
+	// ErrIdempotencyKeyReused 同一個冪等鍵被用來送出不同的選票
+	ErrIdempotencyKeyReused = errors.New("Idempotency-Key was already used with a different ballot")
+)
+
+// BallotIdempotency 送出選票時的冪等鍵與請求內容摘要。
+// 伺服器只保存冪等鍵的雜湊與以冪等鍵加密的回條，沒有冪等鍵便無法由已投票紀錄找到選票。
+type BallotIdempotency struct {
+	key     string
+	request [32]byte
+}
+
+// idempotentReceipt 以冪等鍵加密保存的內容
+type idempotentReceipt struct {
+	Request string `json:"request"`
+	Code    string `json:"code"`
+}
+
+// NewBallotIdempotency 由 Idempotency-Key 與請求內容建立冪等資訊，沒有冪等鍵時回傳 nil
+func NewBallotIdempotency(key string, body []byte) (*BallotIdempotency, error) {
+	if key == "" {
+		return nil, nil
+	}
+	if len(key) < 16 || len(key) > 255 {
+		return nil, ErrIdempotencyKeyInvalid
+	}
+
+	return &BallotIdempotency{key: key, request: sha256.Sum256(body)}, nil
+}
+
+// derive 由冪等鍵與密碼導出不同用途的值
+func (i *BallotIdempotency) derive(purpose string, voter uint64) []byte {
+	sum := sha256.Sum256([]byte(purpose + "\x00" + strconv.FormatUint(voter, 10) + "\x00" + i.key))
+	return sum[:]
+}
+
+// Digest 存在已投票紀錄上的冪等鍵雜湊
+func (i *BallotIdempotency) Digest(voter uint64) string {
+	return hex.EncodeToString(i.derive("ballot-idempotency-digest", voter))
+}
+
+// Seal 以冪等鍵加密回條代碼與請求摘要
+func (i *BallotIdempotency) Seal(voter uint64, receipt *model.BallotReceipt) ([]byte, error) {
+	aead, err := i.aead(voter)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := json.Marshal(idempotentReceipt{
+		Request: hex.EncodeToString(i.request[:]),
+		Code:    receipt.Code,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Open 解開 Seal 保存的回條，請求內容與原本不同時回傳 ErrIdempotencyKeyReused
+func (i *BallotIdempotency) Open(voter uint64, sealed []byte) (string, error) {
+	aead, err := i.aead(voter)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("sealed receipt is too short")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+
+	var stored idempotentReceipt
+	if err := json.Unmarshal(plaintext, &stored); err != nil {
+		return "", err
+	}
+	if stored.Request != hex.EncodeToString(i.request[:]) {
+		return "", ErrIdempotencyKeyReused
+	}
+
+	return stored.Code, nil
+}
+
+func (i *BallotIdempotency) aead(voter uint64) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(i.derive("ballot-idempotency-seal", voter))
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// PreviousBallot 檢查投票者是否已投票：尚未投票時回傳 nil；以相同冪等鍵重送時回傳原本的回條；
+// 其他情況回傳 ErrAlreadyVoted
+func (b BallotService) PreviousBallot(voter uint64, idempotency *BallotIdempotency) (*model.BallotReceipt, error) {
+	return previousBallot(database.SqlSession, voter, idempotency)
+}
+
+func previousBallot(db *gorm.DB, voter uint64, idempotency *BallotIdempotency) (*model.BallotReceipt, error) {
+	participation := model.Participation{}
+	err := db.Where("password_id = ?", voter).Take(&participation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if idempotency == nil || participation.IdempotencyDigest == nil || *participation.IdempotencyDigest != idempotency.Digest(voter) {
+		return nil, ErrAlreadyVoted
+	}
+	code, err := idempotency.Open(voter, participation.SealedReceipt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.BallotReceipt{VoteID: participation.VoteID, Code: code}, nil
+}
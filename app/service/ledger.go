@@ -0,0 +1,290 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"vote/app/database"
+	"vote/app/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LedgerGenesis 雜湊鏈第一筆紀錄的前一筆雜湊
+var LedgerGenesis = strings.Repeat("0", 64)
+
+type LedgerService struct {
+}
+
+func NewLedgerService() LedgerService {
+	return LedgerService{}
+}
+
+// CanonicalBallot 選票的標準化內容，候選人依 ID 排序，確保重新計算時結果一致。
+// 加密選票的候選人另外附上密文的兩個分量，重新投票的選票另外附上被取代的回條。
+func CanonicalBallot(voteId uuid.UUID, ballot model.Ballot) string {
+	selects := append([]model.BallotSelect(nil), ballot.BallotSelects...)
+	sort.Slice(selects, func(i, j int) bool {
+		if selects[i].CandidateID != selects[j].CandidateID {
+			return selects[i].CandidateID < selects[j].CandidateID
+		}
+		return selects[i].ID < selects[j].ID
+	})
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "vote=%s;ballot=%d;question=%d;selects=", voteId, ballot.ID, ballot.QuestionID)
+	for i, ballotSelect := range selects {
+		if i > 0 {
+			builder.WriteString("|")
+		}
+		fmt.Fprintf(&builder, "%d,%d,%d", ballotSelect.CandidateID, ballotSelect.Rank, ballotSelect.Value)
+		// 加密選票另外包含密文
+		if ballotSelect.Ciphertext != nil {
+			fmt.Fprintf(&builder, ",%s,%s", ballotSelect.Ciphertext.A, ballotSelect.Ciphertext.B)
+		}
+	}
+	// 沒有取代回條的選票維持原本的內容，先前寫入的雜湊不受影響
+	if ballot.SupersedesID != nil {
+		fmt.Fprintf(&builder, ";supersedes=%d", *ballot.SupersedesID)
+	}
+
+	return builder.String()
+}
+
+// LedgerHash 計算雜湊鏈紀錄的雜湊：sha256(前一筆雜湊 \n 序號 \n 選票內容)
+func LedgerHash(prevHash string, sequence uint64, canonical string) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s\n%d\n%s", prevHash, sequence, canonical)))
+
+	return hex.EncodeToString(hash[:])
+}
+
+// Append 在寫入選票的交易中將選票接到投票的雜湊鏈後，ballots 需包含 BallotSelects。
+// 以交易層級的 advisory lock 讓同一投票的寫入依序進行。
+func (l LedgerService) Append(tx *gorm.DB, voteId uuid.UUID, ballots []model.Ballot) error {
+	if len(ballots) == 0 {
+		return nil
+	}
+
+	if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", ledgerLockKey(voteId)).Error; err != nil {
+		return err
+	}
+
+	head, err := l.head(tx, voteId)
+	if err != nil {
+		return err
+	}
+
+	// 紀錄對應到選票，時間與選票相同只保留到小時
+	createdAt := coarseTime(time.Now())
+	entries := make([]model.LedgerEntry, len(ballots))
+	for i, ballot := range ballots {
+		sequence := head.Sequence + uint64(i) + 1
+		prevHash := head.Hash
+		if i > 0 {
+			prevHash = entries[i-1].Hash
+		}
+		entries[i] = model.LedgerEntry{
+			VoteID:    voteId,
+			Sequence:  sequence,
+			BallotID:  ballot.ID,
+			PrevHash:  prevHash,
+			Hash:      LedgerHash(prevHash, sequence, CanonicalBallot(voteId, ballot)),
+			CreatedAt: createdAt,
+		}
+	}
+
+	return tx.CreateInBatches(&entries, 500).Error
+}
+
+// Head 取得投票雜湊鏈的最後一筆紀錄，尚無紀錄時回傳序號 0 與起始雜湊
+func (l LedgerService) Head(voteId uuid.UUID) (*model.LedgerEntry, error) {
+	return l.head(database.SqlSession, voteId)
+}
+
+func (l LedgerService) head(tx *gorm.DB, voteId uuid.UUID) (*model.LedgerEntry, error) {
+	var entries []model.LedgerEntry
+	err := tx.Where("vote_id = ?", voteId).Order("sequence DESC").Limit(1).Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return &model.LedgerEntry{VoteID: voteId, Hash: LedgerGenesis}, nil
+	}
+
+	return &entries[0], nil
+}
+
+// Verify 由資料庫重新計算投票的雜湊鏈，回傳第一筆被竄改或遺失的紀錄
+func (l LedgerService) Verify(voteId uuid.UUID) (*model.LedgerReport, error) {
+	var entries []model.LedgerEntry
+	if err := database.SqlSession.Where("vote_id = ?", voteId).Order("sequence ASC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	ballots, err := voteBallots(database.SqlSession, voteId)
+	if err != nil {
+		return nil, err
+	}
+	var superseded []uint64
+	err = database.SqlSession.Model(&model.BallotReceipt{}).
+		Where("vote_id = ? AND superseded", voteId).
+		Order("id ASC").
+		Pluck("id", &superseded).Error
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.LedgerReport{
+		VoteID:   voteId,
+		Entries:  uint64(len(entries)),
+		HeadHash: LedgerGenesis,
+		Issue:    VerifyLedgerChain(voteId, entries, ballots),
+	}
+	if report.Issue == nil {
+		report.Issue = VerifyLedgerSupersession(ballots, superseded)
+	}
+	report.Valid = report.Issue == nil
+	if len(entries) > 0 {
+		report.HeadHash = entries[len(entries)-1].Hash
+	}
+
+	return report, nil
+}
+
+// VerifyLedgerChain 依序檢查雜湊鏈，entries 需依序號排序，ballots 為投票目前所有的選票。
+// 回傳第一個問題，全部正確時回傳 nil。
+func VerifyLedgerChain(voteId uuid.UUID, entries []model.LedgerEntry, ballots map[uint64]model.Ballot) *model.LedgerIssue {
+	prevHash := LedgerGenesis
+	chained := make(map[uint64]struct{}, len(entries))
+	for i, entry := range entries {
+		sequence := uint64(i) + 1
+		if entry.Sequence != sequence {
+			return &model.LedgerIssue{Sequence: sequence, Kind: "missing_entry", Message: fmt.Sprintf("entry %d is missing, found %d instead", sequence, entry.Sequence)}
+		}
+		if entry.PrevHash != prevHash {
+			return &model.LedgerIssue{Sequence: sequence, BallotID: entry.BallotID, Kind: "prev_hash", Message: "previous hash does not match the preceding entry"}
+		}
+
+		ballot, ok := ballots[entry.BallotID]
+		if !ok {
+			return &model.LedgerIssue{Sequence: sequence, BallotID: entry.BallotID, Kind: "missing_ballot", Message: fmt.Sprintf("ballot %d no longer exists", entry.BallotID)}
+		}
+		if LedgerHash(prevHash, sequence, CanonicalBallot(voteId, ballot)) != entry.Hash {
+			return &model.LedgerIssue{Sequence: sequence, BallotID: entry.BallotID, Kind: "content", Message: fmt.Sprintf("ballot %d or its selections differ from the recorded hash", entry.BallotID)}
+		}
+
+		chained[entry.BallotID] = struct{}{}
+		prevHash = entry.Hash
+	}
+
+	// 沒有寫入雜湊鏈的選票，代表選票是繞過正常流程寫入的
+	var unchained []uint64
+	for ballotId := range ballots {
+		if _, ok := chained[ballotId]; !ok {
+			unchained = append(unchained, ballotId)
+		}
+	}
+	if len(unchained) > 0 {
+		sort.Slice(unchained, func(i, j int) bool { return unchained[i] < unchained[j] })
+		return &model.LedgerIssue{BallotID: unchained[0], Kind: "unchained_ballot", Message: fmt.Sprintf("%d ballots are not in the ledger", len(unchained))}
+	}
+
+	return nil
+}
+
+// VerifyLedgerSupersession 檢查回條的取代標記與雜湊鏈中選票記錄的取代一致，ballots 需先通過 VerifyLedgerChain。
+// superseded 為目前標記為已取代的回條，直接修改標記來排除或重新計入選票都會被發現。
+func VerifyLedgerSupersession(ballots map[uint64]model.Ballot, superseded []uint64) *model.LedgerIssue {
+	recorded := make(map[uint64]uint64, len(superseded))
+	for _, ballot := range ballots {
+		if ballot.SupersedesID == nil {
+			continue
+		}
+		if ballotId, ok := recorded[*ballot.SupersedesID]; !ok || ballot.ID < ballotId {
+			recorded[*ballot.SupersedesID] = ballot.ID
+		}
+	}
+
+	marked := make(map[uint64]struct{}, len(superseded))
+	for _, receiptId := range superseded {
+		marked[receiptId] = struct{}{}
+		if _, ok := recorded[receiptId]; !ok {
+			return &model.LedgerIssue{Kind: "supersede", Message: fmt.Sprintf("receipt %d is marked superseded but no ballot in the ledger supersedes it", receiptId)}
+		}
+	}
+
+	var unmarked []uint64
+	for receiptId := range recorded {
+		if _, ok := marked[receiptId]; !ok {
+			unmarked = append(unmarked, receiptId)
+		}
+	}
+	if len(unmarked) > 0 {
+		sort.Slice(unmarked, func(i, j int) bool { return unmarked[i] < unmarked[j] })
+		return &model.LedgerIssue{BallotID: recorded[unmarked[0]], Kind: "supersede", Message: fmt.Sprintf("receipt %d is superseded in the ledger but still counted", unmarked[0])}
+	}
+
+	return nil
+}
+
+// Backfill 將尚未寫入雜湊鏈的選票依 ID 順序接到各投票的雜湊鏈後，回傳處理的選票數
+func (l LedgerService) Backfill(tx *gorm.DB) (int, error) {
+	var voteIds []uuid.UUID
+	err := tx.Model(&model.Question{}).
+		Distinct("questions.vote_id").
+		Joins("JOIN ballots ON ballots.question_id = questions.id").
+		Where("NOT EXISTS (SELECT 1 FROM ballot_ledger WHERE ballot_ledger.ballot_id = ballots.id)").
+		Pluck("questions.vote_id", &voteIds).Error
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for _, voteId := range voteIds {
+		var ballots []model.Ballot
+		err := tx.Preload("BallotSelects").
+			Joins("JOIN questions ON questions.id = ballots.question_id").
+			Where("questions.vote_id = ?", voteId).
+			Where("NOT EXISTS (SELECT 1 FROM ballot_ledger WHERE ballot_ledger.ballot_id = ballots.id)").
+			Order("ballots.id ASC").
+			Find(&ballots).Error
+		if err != nil {
+			return total, err
+		}
+		if err := l.Append(tx, voteId, ballots); err != nil {
+			return total, err
+		}
+		total += len(ballots)
+	}
+
+	return total, nil
+}
+
+// voteBallots 取得投票所有的選票與標記
+func voteBallots(tx *gorm.DB, voteId uuid.UUID) (map[uint64]model.Ballot, error) {
+	var ballots []model.Ballot
+	err := tx.Preload("BallotSelects").
+		Joins("JOIN questions ON questions.id = ballots.question_id").
+		Where("questions.vote_id = ?", voteId).
+		Find(&ballots).Error
+	if err != nil {
+		return nil, err
+	}
+
+	byId := make(map[uint64]model.Ballot, len(ballots))
+	for _, ballot := range ballots {
+		byId[ballot.ID] = ballot
+	}
+
+	return byId, nil
+}
+
+// ledgerLockKey 由投票 UUID 取得 advisory lock 的鍵值
+func ledgerLockKey(voteId uuid.UUID) int64 {
+	return int64(binary.BigEndian.Uint64(voteId[:8]))
+}
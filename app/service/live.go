@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/model"
+	"vote/app/utils"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// liveChannelPrefix 即時統計的 Redis 頻道，後面接投票 UUID
+const liveChannelPrefix = "vote:live:"
+
+// ErrLiveUnavailable 服務沒有啟動即時統計
+var ErrLiveUnavailable = errors.New("live results are not available")
+
+// defaultLiveHub 由 LiveHub.Start 設定，未啟動時（例如測試或指令列工具）通知會被忽略
+var defaultLiveHub *LiveHub
+
+type LiveService struct {
+}
+
+func NewLiveService() LiveService {
+	return LiveService{}
+}
+
+// Snapshot 統計投票目前的投票率，投票開放即時結果且未加密時一併統計各候選人的票數
+func (l LiveService) Snapshot(voteId uuid.UUID) (*model.LiveResult, error) {
+	vote, err := NewVoteService().GetVote(voteId)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &model.LiveResult{
+		VoteID:    vote.Uuid,
+		Status:    vote.Status,
+		Questions: []model.LiveQuestion{},
+		UpdatedAt: time.Now(),
+	}
+	err = database.SqlSession.Model(&model.Password{}).
+		Where("vote_id = ? AND state <> ?", voteId, enum.CredentialRevoked).
+		Count(&result.Eligible).Error
+	if err != nil {
+		return nil, err
+	}
+	err = database.SqlSession.Model(&model.Participation{}).Where("vote_id = ?", voteId).Count(&result.Voted).Error
+	if err != nil {
+		return nil, err
+	}
+	if result.Eligible > 0 {
+		result.Turnout = float64(result.Voted) / float64(result.Eligible)
+	}
+
+	if !vote.LiveResults || vote.Encrypted {
+		return result, nil
+	}
+	result.Questions, err = l.counts(voteId)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// counts 統計每個問題計入開票的選票數與候選人目前的票數，沒有得票的候選人也會列出
+func (l LiveService) counts(voteId uuid.UUID) ([]model.LiveQuestion, error) {
+	questions, err := encryptedQuestions(voteId)
+	if err != nil {
+		return nil, err
+	}
+
+	var ballots []struct {
+		QuestionID uint64
+		Count      int64
+	}
+	err = database.SqlSession.Model(&model.Ballot{}).
+		Select("ballots.question_id, COUNT(*) AS count").
+		Joins("JOIN questions ON questions.id = ballots.question_id").
+		Where("questions.vote_id = ?", voteId).
+		Scopes(countedBallots).
+		Group("ballots.question_id").
+		Scan(&ballots).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var marks []struct {
+		QuestionID  uint64
+		CandidateID uint64
+		First       int64
+		Marked      int64
+	}
+	err = database.SqlSession.Model(&model.BallotSelect{}).
+		Select("ballots.question_id, ballot_selects.candidate_id, "+
+			"COUNT(*) FILTER (WHERE ballot_selects.rank = 1) AS first, "+
+			"COUNT(*) FILTER (WHERE ballot_selects.value > 0) AS marked").
+		Joins("JOIN ballots ON ballots.id = ballot_selects.ballot_id").
+		Joins("JOIN questions ON questions.id = ballots.question_id").
+		Where("questions.vote_id = ?", voteId).
+		Scopes(countedBallots).
+		Group("ballots.question_id, ballot_selects.candidate_id").
+		Scan(&marks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ballotCounts := make(map[uint64]int64, len(ballots))
+	for _, row := range ballots {
+		ballotCounts[row.QuestionID] = row.Count
+	}
+	type markKey struct{ questionId, candidateId uint64 }
+	first := make(map[markKey]int64, len(marks))
+	marked := make(map[markKey]int64, len(marks))
+	for _, row := range marks {
+		first[markKey{row.QuestionID, row.CandidateID}] = row.First
+		marked[markKey{row.QuestionID, row.CandidateID}] = row.Marked
+	}
+
+	live := make([]model.LiveQuestion, 0, len(questions))
+	for _, question := range questions {
+		entry := model.LiveQuestion{
+			QuestionID: question.ID,
+			Ballots:    ballotCounts[question.ID],
+			Candidates: make([]model.LiveCount, 0, len(question.Candidates)),
+		}
+		for _, candidate := range question.Candidates {
+			key := markKey{question.ID, candidate.ID}
+			votes := marked[key]
+			if question.Method.IsRanked() {
+				votes = first[key]
+			}
+			entry.Candidates = append(entry.Candidates, model.LiveCount{CandidateID: candidate.ID, Votes: votes})
+		}
+		live = append(live, entry)
+	}
+
+	return live, nil
+}
+
+// LiveHub 將投票的即時統計推送給訂閱者。
+// 收到選票或狀態改變的服務統計後發布到 Redis，每個服務都訂閱頻道並轉給本機的訂閱者，多個服務推送的內容一致。
+// 同一投票在 Throttle 內的多次通知只統計一次，也讓單張選票難以由票數變化看出。
+type LiveHub struct {
+	Throttle time.Duration
+	// Snapshot 統計投票目前的結果，預設為 LiveService.Snapshot
+	Snapshot func(voteId uuid.UUID) (*model.LiveResult, error)
+
+	client      *redis.Client
+	mu          sync.Mutex
+	subscribers map[uuid.UUID]map[chan *model.LiveResult]struct{}
+	scheduled   map[uuid.UUID]bool
+}
+
+// NewLiveHub 建立即時統計的推送中心
+func NewLiveHub(client *redis.Client) *LiveHub {
+	return &LiveHub{
+		Throttle:    time.Second,
+		Snapshot:    NewLiveService().Snapshot,
+		client:      client,
+		subscribers: make(map[uuid.UUID]map[chan *model.LiveResult]struct{}),
+		scheduled:   make(map[uuid.UUID]bool),
+	}
+}
+
+// Start 在背景訂閱 Redis 頻道直到 ctx 結束，並設為服務預設的推送中心
+func (h *LiveHub) Start(ctx context.Context) {
+	defaultLiveHub = h
+
+	go func() {
+		pubsub := h.client.PSubscribe(ctx, liveChannelPrefix+"*")
+		defer pubsub.Close()
+
+		// 斷線時 go-redis 會自動重新連線並重新訂閱
+		messages := pubsub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case message, ok := <-messages:
+				if !ok {
+					return
+				}
+				result := &model.LiveResult{}
+				if err := json.Unmarshal([]byte(message.Payload), result); err != nil {
+					liveLogger().Error("invalid message on ", message.Channel, ": ", err)
+					continue
+				}
+				if result.VoteID.String() != strings.TrimPrefix(message.Channel, liveChannelPrefix) {
+					continue
+				}
+				h.deliver(result)
+			}
+		}
+	}()
+}
+
+// Subscribe 訂閱投票的即時統計，先送出目前的統計，ctx 結束時取消訂閱並關閉 channel。
+// 訂閱者來不及讀取時只保留最新的統計。
+func (h *LiveHub) Subscribe(ctx context.Context, voteId uuid.UUID) (<-chan *model.LiveResult, error) {
+	current, err := h.Snapshot(voteId)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(chan *model.LiveResult, 1)
+	updates <- current
+
+	h.mu.Lock()
+	if h.subscribers[voteId] == nil {
+		h.subscribers[voteId] = make(map[chan *model.LiveResult]struct{})
+	}
+	h.subscribers[voteId][updates] = struct{}{}
+	h.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subscribers[voteId], updates)
+		if len(h.subscribers[voteId]) == 0 {
+			delete(h.subscribers, voteId)
+		}
+		close(updates)
+	}()
+
+	return updates, nil
+}
+
+// Notify 投票有新選票或狀態改變時，在 Throttle 後統計並發布
+func (h *LiveHub) Notify(voteId uuid.UUID) {
+	h.mu.Lock()
+	if h.scheduled[voteId] {
+		h.mu.Unlock()
+		return
+	}
+	h.scheduled[voteId] = true
+	h.mu.Unlock()
+
+	time.AfterFunc(h.Throttle, func() {
+		h.mu.Lock()
+		delete(h.scheduled, voteId)
+		h.mu.Unlock()
+
+		if err := h.publish(voteId); err != nil {
+			liveLogger().Error("vote ", voteId, ": ", err)
+		}
+	})
+}
+
+// publish 統計並發布到 Redis，發布失敗時至少推送給本機的訂閱者
+func (h *LiveHub) publish(voteId uuid.UUID) error {
+	result, err := h.Snapshot(voteId)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	err = h.client.Publish(context.Background(), liveChannelPrefix+voteId.String(), payload).Err()
+	if err != nil {
+		h.deliver(result)
+	}
+
+	return err
+}
+
+// deliver 推送給本機的訂閱者，來不及讀取的舊統計會被取代
+func (h *LiveHub) deliver(result *model.LiveResult) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for updates := range h.subscribers[result.VoteID] {
+		select {
+		case updates <- result:
+		default:
+			select {
+			case <-updates:
+			default:
+			}
+			updates <- result
+		}
+	}
+}
+
+// SubscribeLiveResult 以服務預設的推送中心訂閱投票的即時統計
+func SubscribeLiveResult(ctx context.Context, voteId uuid.UUID) (<-chan *model.LiveResult, error) {
+	if defaultLiveHub == nil {
+		return nil, ErrLiveUnavailable
+	}
+
+	return defaultLiveHub.Subscribe(ctx, voteId)
+}
+
+// NotifyLiveResult 通知服務預設的推送中心投票的統計已改變
+func NotifyLiveResult(voteId uuid.UUID) {
+	if defaultLiveHub != nil {
+		defaultLiveHub.Notify(voteId)
+	}
+}
+
+func liveLogger() *logrus.Entry {
+	return utils.Logger().WithFields(logrus.Fields{
+		"name": "live",
+	})
+}
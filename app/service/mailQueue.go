@@ -0,0 +1,401 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/mail"
+	"vote/app/model"
+	"vote/app/utils"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// mailJobStaleAfter 寄送中的工作超過此時間未完成，視為工作者已中斷並重新寄送
+const mailJobStaleAfter = 10 * time.Minute
+
+type MailQueueService struct {
+}
+
+func NewMailQueueService() MailQueueService {
+	return MailQueueService{}
+}
+
+// Enqueue 將範本郵件排入佇列，data 會以 JSON 儲存
+func (m MailQueueService) Enqueue(voteId *uuid.UUID, to string, template string, language string, data any) (*model.MailJob, error) {
+	if _, err := mail.NewData(template); err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &model.MailJob{
+		VoteID:        voteId,
+		To:            to,
+		Template:      template,
+		Language:      language,
+		Data:          string(encoded),
+		Status:        enum.MailQueued,
+		NextAttemptAt: time.Now(),
+	}
+
+	return job, database.SqlSession.Create(job).Error
+}
+
+// EnqueueVoters 為名冊中的投票者排入範本郵件，已有相同範本的工作在佇列中時略過。
+// 郵件內容在寄送時才依投票者產生，回傳排入的數量。
+func (m MailQueueService) EnqueueVoters(tx *gorm.DB, voters []model.Voter, template string) (int, error) {
+	if len(voters) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]uint64, len(voters))
+	for i, voter := range voters {
+		ids[i] = voter.ID
+	}
+
+	var pending []uint64
+	err := tx.Model(&model.MailJob{}).
+		Where("voter_id IN ? AND template = ?", ids, template).
+		Where("status IN ?", []enum.MailJobStatus{enum.MailQueued, enum.MailSending, enum.MailRetrying}).
+		Pluck("voter_id", &pending).Error
+	if err != nil {
+		return 0, err
+	}
+	skip := make(map[uint64]struct{}, len(pending))
+	for _, id := range pending {
+		skip[id] = struct{}{}
+	}
+
+	now := time.Now()
+	jobs := make([]model.MailJob, 0, len(voters))
+	for _, voter := range voters {
+		if _, ok := skip[voter.ID]; ok {
+			continue
+		}
+		voteId, voterId := voter.VoteID, voter.ID
+		jobs = append(jobs, model.MailJob{
+			VoteID:        &voteId,
+			VoterID:       &voterId,
+			To:            voter.Email,
+			Template:      template,
+			Status:        enum.MailQueued,
+			NextAttemptAt: now,
+		})
+	}
+	if len(jobs) == 0 {
+		return 0, nil
+	}
+
+	return len(jobs), tx.CreateInBatches(&jobs, 500).Error
+}
+
+// GetProgress 取得投票郵件的寄送進度，template 為空時統計所有範本
+func (m MailQueueService) GetProgress(voteId uuid.UUID, template string) (*model.MailProgress, error) {
+	var rows []struct {
+		Status enum.MailJobStatus
+		Count  int64
+	}
+	query := database.SqlSession.Model(&model.MailJob{}).
+		Select("status, count(*) AS count").
+		Where("vote_id = ?", voteId)
+	if template != "" {
+		query = query.Where("template = ?", template)
+	}
+	if err := query.Group("status").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	progress := &model.MailProgress{VoteID: voteId, Template: template}
+	for _, row := range rows {
+		progress.Total += row.Count
+		switch row.Status {
+		case enum.MailQueued:
+			progress.Queued = row.Count
+		case enum.MailSending:
+			progress.Sending = row.Count
+		case enum.MailRetrying:
+			progress.Retrying = row.Count
+		case enum.MailSent:
+			progress.Sent = row.Count
+		case enum.MailDead:
+			progress.Dead = row.Count
+		case enum.MailCanceled:
+			progress.Canceled = row.Count
+		}
+	}
+
+	return progress, nil
+}
+
+// RetryDead 將投票中超過重試次數的郵件重新排入佇列，回傳重新排入的數量
+func (m MailQueueService) RetryDead(voteId uuid.UUID) (int64, error) {
+	result := database.SqlSession.Model(&model.MailJob{}).
+		Where("vote_id = ? AND status = ?", voteId, enum.MailDead).
+		Updates(map[string]any{
+			"status":          enum.MailQueued,
+			"attempts":        0,
+			"next_attempt_at": time.Now(),
+			"updated_at":      time.Now(),
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+// MailWorker 從佇列取出到期的郵件寄送，失敗時以指數退避重試，
+// 超過 MaxAttempts 次後標記為 dead。多個服務可同時執行，工作以 SKIP LOCKED 分配。
+type MailWorker struct {
+	Concurrency int
+	Interval    time.Duration
+	MaxAttempts int
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+}
+
+// NewMailWorker 建立郵件工作者，可由 MAIL_WORKER_CONCURRENCY、MAIL_WORKER_INTERVAL 與 MAIL_MAX_ATTEMPTS 設定
+func NewMailWorker() *MailWorker {
+	return &MailWorker{
+		Concurrency: envInt("MAIL_WORKER_CONCURRENCY", 4),
+		Interval:    time.Duration(envInt("MAIL_WORKER_INTERVAL", 5)) * time.Second,
+		MaxAttempts: envInt("MAIL_MAX_ATTEMPTS", 5),
+		BaseBackoff: 30 * time.Second,
+		MaxBackoff:  time.Hour,
+	}
+}
+
+// Start 在背景執行工作者，直到 ctx 結束
+func (w *MailWorker) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(w.Interval)
+		defer ticker.Stop()
+
+		for {
+			// 取到完整一批時代表可能還有待寄送的郵件，立即再取一次
+			for {
+				count, err := w.Work(time.Now())
+				if err != nil {
+					mailQueueLogger().Error("error: ", err)
+				}
+				if err != nil || count < w.batchSize() || ctx.Err() != nil {
+					break
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Work 取出一批到期的郵件並以 Concurrency 個併發寄送，回傳處理的數量
+func (w *MailWorker) Work(now time.Time) (int, error) {
+	jobs, err := w.claim(now)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	limit := make(chan struct{}, max(w.Concurrency, 1))
+	for _, job := range jobs {
+		wg.Add(1)
+		limit <- struct{}{}
+		go func(job model.MailJob) {
+			defer wg.Done()
+			defer func() { <-limit }()
+			defer func() {
+				if r := recover(); r != nil {
+					w.finish(job, fmt.Errorf("panic: %v", r))
+				}
+			}()
+
+			w.finish(job, w.send(job))
+		}(job)
+	}
+	wg.Wait()
+
+	return len(jobs), nil
+}
+
+// Backoff 第 attempts 次寄送失敗後到下次重試的等待時間
+func (w *MailWorker) Backoff(attempts int) time.Duration {
+	backoff := w.BaseBackoff
+	for i := 1; i < attempts && backoff < w.MaxBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, w.MaxBackoff)
+}
+
+func (w *MailWorker) batchSize() int {
+	return max(w.Concurrency, 1) * 5
+}
+
+// claim 鎖定一批到期的工作並標記為寄送中，寄送中過久的工作也會被重新取出
+func (w *MailWorker) claim(now time.Time) ([]model.MailJob, error) {
+	var jobs []model.MailJob
+	err := database.SqlSession.Raw(`
+		UPDATE mail_jobs SET status = ?, attempts = attempts + 1, locked_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM mail_jobs
+			WHERE (status IN (?, ?) AND next_attempt_at <= ?) OR (status = ? AND locked_at < ?)
+			ORDER BY next_attempt_at, id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		enum.MailSending, now, now,
+		enum.MailQueued, enum.MailRetrying, now, enum.MailSending, now.Add(-mailJobStaleAfter),
+		w.batchSize(),
+	).Scan(&jobs).Error
+
+	return jobs, err
+}
+
+// send 產生郵件內容並寄送
+func (w *MailWorker) send(job model.MailJob) error {
+	data, err := mailJobData(job)
+	if err != nil {
+		return err
+	}
+
+	return NewSmtpService().SendTemplate(job.To, job.Template, job.Language, data)
+}
+
+// finish 記錄寄送結果，並同步名冊投票者的寄送狀態
+func (w *MailWorker) finish(job model.MailJob, sendErr error) {
+	now := time.Now()
+	updates := map[string]any{
+		"status":     enum.MailSent,
+		"locked_at":  nil,
+		"last_error": "",
+		"sent_at":    now,
+		"updated_at": now,
+	}
+	voterUpdates := map[string]any{
+		"delivery_status": enum.DeliverySent,
+		"delivery_error":  "",
+		"sent_at":         now,
+	}
+	if errors.Is(sendErr, errVoterHasVoted) || errors.Is(sendErr, errReminderVoteClosed) {
+		updates = map[string]any{
+			"status":     enum.MailCanceled,
+			"locked_at":  nil,
+			"last_error": sendErr.Error(),
+			"updated_at": now,
+		}
+		voterUpdates = nil
+	} else if sendErr != nil {
+		message := truncate(sendErr.Error(), 255)
+		updates = map[string]any{
+			"status":          enum.MailRetrying,
+			"locked_at":       nil,
+			"last_error":      message,
+			"next_attempt_at": now.Add(w.Backoff(job.Attempts)),
+			"updated_at":      now,
+		}
+		voterUpdates = nil
+		if job.Attempts >= w.MaxAttempts {
+			updates["status"] = enum.MailDead
+			voterUpdates = map[string]any{
+				"delivery_status": enum.DeliveryFailed,
+				"delivery_error":  message,
+			}
+		}
+		mailQueueLogger().Warn("job ", job.ID, " attempt ", job.Attempts, ": ", sendErr)
+	}
+
+	err := database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.MailJob{}).Where("id = ?", job.ID).Updates(updates).Error; err != nil {
+			return err
+		}
+		if job.VoterID == nil || voterUpdates == nil {
+			return nil
+		}
+		return tx.Model(&model.Voter{}).Where("id = ?", *job.VoterID).Updates(voterUpdates).Error
+	})
+	if err != nil {
+		mailQueueLogger().Error("job ", job.ID, ": ", err)
+	}
+}
+
+// mailJobData 還原郵件的範本資料，寄給名冊投票者的郵件在此時才產生內容
+func mailJobData(job model.MailJob) (any, error) {
+	if job.VoterID != nil {
+		return voterMailData(*job.VoterID, job.Template)
+	}
+
+	data, err := mail.NewData(job.Template)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(job.Data), data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// voterMailData 依投票者與投票產生範本資料
+func voterMailData(voterId uint64, template string) (any, error) {
+	voter := model.Voter{}
+	if err := database.SqlSession.First(&voter, voterId).Error; err != nil {
+		return nil, err
+	}
+	vote, err := NewVoteService().GetVote(voter.VoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch template {
+	case mail.Invitation:
+		return NewVoterRollService().invitation(*vote, voter)
+	case mail.Reminder:
+		if vote.Status != enum.Open || !time.Now().Before(vote.EndTime) {
+			return nil, errReminderVoteClosed
+		}
+		hasVoted, err := NewBallotService().CheckIfVoterHasVoted(voter.PasswordID)
+		if err != nil {
+			return nil, err
+		}
+		if hasVoted {
+			return nil, errVoterHasVoted
+		}
+		return mail.ReminderData{
+			Name:      voter.Name,
+			VoteTitle: vote.Title,
+			EndTime:   vote.EndTime,
+			LoginURL:  VoterLoginURL(*vote, ""),
+		}, nil
+	default:
+		return nil, fmt.Errorf("mail template %s is not supported for voters", template)
+	}
+}
+
+// envInt 讀取正整數環境變數，未設定或格式錯誤時使用預設值
+func envInt(key string, fallback int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+
+	return fallback
+}
+
+func mailQueueLogger() *logrus.Entry {
+	return utils.Logger().WithFields(logrus.Fields{
+		"name": "MailQueue",
+	})
+}
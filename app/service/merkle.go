@@ -0,0 +1,429 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/model"
+	"vote/app/utils"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrMerkleKeyMissing 未設定後端驗證金鑰
+	ErrMerkleKeyMissing = errors.New("SMART_CONTRACT_PRIVATE_KEY is not set")
+	// ErrMerkleVoteOpen 投票尚未結束，選票仍可能增加
+	ErrMerkleVoteOpen = errors.New("vote must be closed before publishing the merkle root")
+)
+
+// merklePublishTimeout 開票後公布樹根的時間上限，區塊鏈節點沒有回應時不會卡住開票與排程，逾時後可由 API 重新公布
+const merklePublishTimeout = 30 * time.Second
+
+// 葉節點與內部節點使用不同前綴，避免以內部節點偽造葉節點
+var (
+	merkleLeafPrefix = []byte{0x00}
+	merkleNodePrefix = []byte{0x01}
+)
+
+// MerkleBallotHash 選票內容的雜湊：keccak256(標準化選票內容)
+func MerkleBallotHash(voteId uuid.UUID, ballot model.Ballot) common.Hash {
+	return crypto.Keccak256Hash([]byte(CanonicalBallot(voteId, ballot)))
+}
+
+// MerkleLeaf 選票的葉節點雜湊，同時承諾選票所屬的回條代碼與選票內容，沒有回條的舊選票代碼為空字串
+func MerkleLeaf(voteId uuid.UUID, receiptCode string, ballot model.Ballot) common.Hash {
+	return MerkleReceiptLeaf(receiptCode, MerkleBallotHash(voteId, ballot))
+}
+
+// MerkleReceiptLeaf 由回條代碼與選票雜湊計算葉節點：keccak256(0x00 || keccak256(代碼) || 選票雜湊)。
+// 投票者以手上的回條代碼重新計算，證明的葉節點屬於自己的回條；沒有代碼也無法由葉節點反推選票內容。
+func MerkleReceiptLeaf(receiptCode string, ballotHash common.Hash) common.Hash {
+	code := crypto.Keccak256([]byte(strings.ToLower(strings.TrimSpace(receiptCode))))
+
+	return crypto.Keccak256Hash(merkleLeafPrefix, code, ballotHash.Bytes())
+}
+
+// merkleNode 內部節點雜湊：keccak256(0x01 || 左 || 右)
+func merkleNode(left common.Hash, right common.Hash) common.Hash {
+	return crypto.Keccak256Hash(merkleNodePrefix, left.Bytes(), right.Bytes())
+}
+
+// MerkleTree 依選票 ID 排序建立的 Merkle 樹，奇數個節點時最後一個節點直接升到上一層
+type MerkleTree struct {
+	levels [][]common.Hash
+}
+
+// NewMerkleTree 由葉節點建立 Merkle 樹
+func NewMerkleTree(leaves []common.Hash) *MerkleTree {
+	levels := [][]common.Hash{append([]common.Hash(nil), leaves...)}
+	for len(levels[len(levels)-1]) > 1 {
+		current := levels[len(levels)-1]
+		next := make([]common.Hash, 0, (len(current)+1)/2)
+		for i := 0; i < len(current); i += 2 {
+			if i+1 == len(current) {
+				next = append(next, current[i])
+				continue
+			}
+			next = append(next, merkleNode(current[i], current[i+1]))
+		}
+		levels = append(levels, next)
+	}
+
+	return &MerkleTree{levels: levels}
+}
+
+// Root 樹根，沒有葉節點時為零值
+func (t *MerkleTree) Root() common.Hash {
+	top := t.levels[len(t.levels)-1]
+	if len(top) == 0 {
+		return common.Hash{}
+	}
+
+	return top[0]
+}
+
+// Proof 第 index 個葉節點的包含證明
+func (t *MerkleTree) Proof(index int) model.MerkleProof {
+	proof := model.MerkleProof{Leaf: t.levels[0][index].Hex(), Index: index, Siblings: []model.MerkleStep{}}
+	for _, level := range t.levels[:len(t.levels)-1] {
+		sibling := index ^ 1
+		if sibling < len(level) {
+			proof.Siblings = append(proof.Siblings, model.MerkleStep{Hash: level[sibling].Hex(), Left: sibling < index})
+		}
+		index /= 2
+	}
+
+	return proof
+}
+
+// VerifyMerkleProof 由葉節點與證明路徑重新計算樹根並比對
+func VerifyMerkleProof(proof model.MerkleProof, root string) bool {
+	hash := common.HexToHash(proof.Leaf)
+	for _, step := range proof.Siblings {
+		if step.Left {
+			hash = merkleNode(common.HexToHash(step.Hash), hash)
+		} else {
+			hash = merkleNode(hash, common.HexToHash(step.Hash))
+		}
+	}
+
+	return strings.EqualFold(hash.Hex(), root)
+}
+
+// MerkleRootMessage 簽章的訊息，包含投票、樹根與選票數
+func MerkleRootMessage(voteId uuid.UUID, root string, leafCount int) []byte {
+	return []byte(fmt.Sprintf("vote:%s\nmerkle-root:%s\nballots:%d", voteId, strings.ToLower(root), leafCount))
+}
+
+// SignMerkleRoot 以 EIP-191 個人訊息格式簽署樹根，回傳 0x 開頭的簽章
+func SignMerkleRoot(key *ecdsa.PrivateKey, message []byte) (string, error) {
+	signature, err := crypto.Sign(accounts.TextHash(message), key)
+	if err != nil {
+		return "", err
+	}
+	// recovery ID 轉為 27 或 28，與錢包簽章格式一致
+	signature[crypto.RecoveryIDOffset] += 27
+
+	return hexutil.Encode(signature), nil
+}
+
+// RecoverMerkleSigner 由簽章還原簽署者地址
+func RecoverMerkleSigner(message []byte, signature string) (common.Address, error) {
+	raw, err := hexutil.Decode(signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(raw) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("signature must be %d bytes", crypto.SignatureLength)
+	}
+	if raw[crypto.RecoveryIDOffset] >= 27 {
+		raw[crypto.RecoveryIDOffset] -= 27
+	}
+
+	publicKey, err := crypto.SigToPub(accounts.TextHash(message), raw)
+	if err != nil {
+		return common.Address{}, err
+	}
+
+	return crypto.PubkeyToAddress(*publicKey), nil
+}
+
+// VerifyMerkleBundle 離線驗證公布的樹根簽章與所有包含證明，signer 為空時只檢查簽章可還原。
+// 每個葉節點都以投票者提供的回條代碼與證明中的選票雜湊重新計算，通過時表示簽署的樹根包含此回條的選票；
+// 選票雜湊對應的內容由雜湊鏈驗證，這裡不檢查。
+func VerifyMerkleBundle(bundle model.MerkleProofBundle, receiptCode string, signer string) error {
+	if strings.TrimSpace(receiptCode) == "" {
+		return errors.New("receipt code is required")
+	}
+
+	recovered, err := RecoverMerkleSigner(MerkleRootMessage(bundle.VoteID, bundle.Root, bundle.LeafCount), bundle.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %w", err)
+	}
+	if !strings.EqualFold(recovered.Hex(), bundle.Signer) {
+		return fmt.Errorf("signature was made by %s, not the published signer %s", recovered.Hex(), bundle.Signer)
+	}
+	if signer != "" && !strings.EqualFold(recovered.Hex(), signer) {
+		return fmt.Errorf("signature was made by %s, expected %s", recovered.Hex(), signer)
+	}
+
+	for _, proof := range bundle.Proofs {
+		if proof.Index < 0 || proof.Index >= bundle.LeafCount {
+			return fmt.Errorf("leaf %s has index %d outside of %d ballots", proof.Leaf, proof.Index, bundle.LeafCount)
+		}
+		if !strings.EqualFold(MerkleReceiptLeaf(receiptCode, common.HexToHash(proof.BallotHash)).Hex(), proof.Leaf) {
+			return fmt.Errorf("leaf %s does not belong to receipt %s", proof.Leaf, receiptCode)
+		}
+		if !VerifyMerkleProof(proof, bundle.Root) {
+			return fmt.Errorf("leaf %s is not included in root %s", proof.Leaf, bundle.Root)
+		}
+	}
+
+	return nil
+}
+
+// AnchorClient 寫入區塊鏈所需的方法，ethclient.Client 與模擬鏈皆可使用
+type AnchorClient interface {
+	ChainID(ctx context.Context) (*big.Int, error)
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+	SendTransaction(ctx context.Context, tx *types.Transaction) error
+}
+
+// MerkleAnchorData 寫入交易資料欄位的內容：投票 UUID 與樹根
+func MerkleAnchorData(voteId uuid.UUID, root string) []byte {
+	return append(append([]byte{}, voteId[:]...), common.HexToHash(root).Bytes()...)
+}
+
+// AnchorMerkleRoot 以驗證金鑰送出一筆轉給自己、資料欄位為樹根的交易，回傳交易雜湊
+func AnchorMerkleRoot(ctx context.Context, client AnchorClient, key *ecdsa.PrivateKey, voteId uuid.UUID, root string) (common.Hash, error) {
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	data := MerkleAnchorData(voteId, root)
+
+	chainId, err := client.ChainID(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	nonce, err := client.PendingNonceAt(ctx, from)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	gasPrice, err := client.SuggestGasPrice(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	gas, err := client.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &from, Data: data})
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	tx, err := types.SignTx(
+		types.NewTx(&types.LegacyTx{Nonce: nonce, To: &from, Gas: gas, GasPrice: gasPrice, Data: data}),
+		types.LatestSignerForChainID(chainId),
+		key,
+	)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	return tx.Hash(), client.SendTransaction(ctx, tx)
+}
+
+type MerkleService struct {
+}
+
+func NewMerkleService() MerkleService {
+	return MerkleService{}
+}
+
+// Publish 建立投票所有選票的 Merkle 樹，簽署樹根後儲存，設定 ETH_RPC_URL 時另外寫入區塊鏈。
+// 已公布的投票會重新計算，樹根不同時更新紀錄。
+func (m MerkleService) Publish(ctx context.Context, voteId uuid.UUID) (*model.MerkleRoot, error) {
+	voteOne, err := NewVoteService().GetVote(voteId)
+	if err != nil {
+		return nil, err
+	}
+	if voteOne.Status < enum.Closed {
+		return nil, ErrMerkleVoteOpen
+	}
+
+	key, err := merkleSigningKey()
+	if err != nil {
+		return nil, err
+	}
+
+	tree, _, err := m.tree(voteId)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &model.MerkleRoot{}
+	err = database.SqlSession.Where("vote_id = ?", voteId).First(record).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	root := tree.Root().Hex()
+	if record.ID != 0 && record.Root == root && record.LeafCount == len(tree.levels[0]) {
+		return record, nil
+	}
+
+	record.VoteID = voteId
+	record.Root = root
+	record.LeafCount = len(tree.levels[0])
+	record.Signer = crypto.PubkeyToAddress(key.PublicKey).Hex()
+	record.AnchorTx = ""
+	record.Signature, err = SignMerkleRoot(key, MerkleRootMessage(voteId, root, record.LeafCount))
+	if err != nil {
+		return nil, err
+	}
+
+	if rpcUrl := os.Getenv("ETH_RPC_URL"); rpcUrl != "" {
+		client, err := ethclient.DialContext(ctx, rpcUrl)
+		if err != nil {
+			return nil, err
+		}
+		defer client.Close()
+
+		txHash, err := AnchorMerkleRoot(ctx, client, key, voteId, root)
+		if err != nil {
+			return nil, fmt.Errorf("anchor merkle root: %w", err)
+		}
+		record.AnchorTx = txHash.Hex()
+	}
+
+	return record, database.SqlSession.Save(record).Error
+}
+
+// GetRoot 取得投票已公布的樹根
+func (m MerkleService) GetRoot(voteId uuid.UUID) (*model.MerkleRoot, error) {
+	record := &model.MerkleRoot{}
+	err := database.SqlSession.Where("vote_id = ?", voteId).First(record).Error
+
+	return record, err
+}
+
+// GetReceiptProofs 取得回條中每張選票的包含證明
+func (m MerkleService) GetReceiptProofs(code string) (*model.MerkleProofBundle, error) {
+	receipt := model.BallotReceipt{}
+	err := database.SqlSession.
+		Where("code = ?", strings.ToLower(strings.TrimSpace(code))).
+		First(&receipt).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if receipt.Superseded {
+		return nil, ErrReceiptSuperseded
+	}
+
+	record, err := m.GetRoot(receipt.VoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, ballots, err := m.tree(receipt.VoteID)
+	if err != nil {
+		return nil, err
+	}
+	if tree.Root().Hex() != record.Root {
+		return nil, fmt.Errorf("ballots of vote %s no longer match the published merkle root", receipt.VoteID)
+	}
+
+	var receiptBallots []uint64
+	err = database.SqlSession.Model(&model.Ballot{}).
+		Where("receipt_id = ?", receipt.ID).
+		Order("id ASC").
+		Pluck("id", &receiptBallots).Error
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &model.MerkleProofBundle{MerkleRoot: *record, Proofs: []model.MerkleProof{}}
+	for _, ballotId := range receiptBallots {
+		index := sort.Search(len(ballots), func(i int) bool { return ballots[i].ID >= ballotId })
+		if index == len(ballots) || ballots[index].ID != ballotId {
+			return nil, fmt.Errorf("ballot %d is not in the merkle tree", ballotId)
+		}
+		proof := tree.Proof(index)
+		proof.BallotHash = MerkleBallotHash(receipt.VoteID, ballots[index]).Hex()
+		bundle.Proofs = append(bundle.Proofs, proof)
+	}
+
+	return bundle, nil
+}
+
+// tree 依選票 ID 順序建立投票計入開票的選票的 Merkle 樹，同時回傳排序後的選票
+func (m MerkleService) tree(voteId uuid.UUID) (*MerkleTree, []model.Ballot, error) {
+	ballots, err := NewTallyService().SelectBallots(voteId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var receipts []model.BallotReceipt
+	if err := database.SqlSession.Select("id", "code").Where("vote_id = ?", voteId).Find(&receipts).Error; err != nil {
+		return nil, nil, err
+	}
+	codes := make(map[uint64]string, len(receipts))
+	for _, receipt := range receipts {
+		codes[receipt.ID] = receipt.Code
+	}
+
+	leaves := make([]common.Hash, len(ballots))
+	for i, ballot := range ballots {
+		code := ""
+		if ballot.ReceiptID != nil {
+			code = codes[*ballot.ReceiptID]
+		}
+		leaves[i] = MerkleLeaf(voteId, code, ballot)
+	}
+
+	return NewMerkleTree(leaves), ballots, nil
+}
+
+// merkleSigningKey 讀取後端驗證金鑰，與 cmd/genPrivateKey 產生的格式相同
+func merkleSigningKey() (*ecdsa.PrivateKey, error) {
+	hexKey := strings.TrimPrefix(strings.TrimSpace(os.Getenv("SMART_CONTRACT_PRIVATE_KEY")), "0x")
+	if hexKey == "" {
+		return nil, ErrMerkleKeyMissing
+	}
+
+	return crypto.HexToECDSA(hexKey)
+}
+
+// publishMerkleRoot 開票後公布選票的 Merkle 樹根，失敗或逾時只記錄，可由 API 重新公布
+func publishMerkleRoot(voteId uuid.UUID) {
+	ctx, cancel := context.WithTimeout(context.Background(), merklePublishTimeout)
+	defer cancel()
+
+	record, err := NewMerkleService().Publish(ctx, voteId)
+	if err != nil {
+		merkleLogger().Error("vote ", voteId, ": merkle root error: ", err)
+		return
+	}
+	merkleLogger().Info("Vote ", voteId, ": merkle root ", record.Root, " over ", record.LeafCount, " ballots")
+}
+
+func merkleLogger() *logrus.Entry {
+	return utils.Logger().WithFields(logrus.Fields{
+		"name": "merkle",
+	})
+}
@@ -1,11 +1,16 @@
 package service
 
 import (
+	"fmt"
+	"time"
 	"vote/app/database"
+	"vote/app/enum"
 	"vote/app/model"
 	"vote/app/utils"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PasswordService struct {
@@ -15,11 +20,18 @@ func NewPasswordService() PasswordService {
 	return PasswordService{}
 }
 
-// SelectOnePassword 根據提供的投票ID和密碼，檢查密碼是否存在。
+// SelectOnePassword 根據提供的投票ID和明文密碼，以摘要查詢可登入且未過期的密碼。
 func (p PasswordService) SelectOnePassword(voteId uuid.UUID, password string) (*model.Password, error) {
+	digest, err := (&utils.Password{}).Digest(voteId.String(), password)
+	if err != nil {
+		return nil, err
+	}
+
 	passwordModel := model.Password{}
-	err := database.SqlSession.
-		Where("vote_id = ? AND password = ? AND status = true", voteId, password).
+	err = database.SqlSession.
+		Where("vote_id = ? AND digest = ?", voteId, digest).
+		Where("state IN ?", []enum.CredentialState{enum.CredentialActivated, enum.CredentialUsed}).
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
 		First(&passwordModel).
 		Error
 		
@@ -67,28 +79,13 @@ func (p PasswordService) SelectPassword(voteId uuid.UUID, passwordQuery model.Pa
 	return passwords, total, nil
 }
 
-// CreatePassword 建立可以加解密的密碼
+// CreatePassword 建立可以加解密的密碼，並以摘要確保同一投票中的密碼不重複
 func (p PasswordService) CreatePassword(voteId uuid.UUID, number int, length int, format string) error {
-	passwordUtil := &utils.Password{}
-	// 生成密碼
-	passwords, err := passwordUtil.GeneratePassword(number, length, format)
+	passwordModels, err := p.generatePasswords(voteId, number, length, format)
 	if err != nil {
 		return err
 	}
 
-	// 將密碼加密
-	passwordModels := make([]model.Password, len(passwords))
-	for i, password := range passwords {
-		passwordEncrypt, err := passwordUtil.Encrypt(password)
-		if err != nil {
-			return err
-		}
-		passwordModels[i] = model.Password{
-			VoteID:   voteId,
-			Password: passwordEncrypt,
-		}
-	}
-
 	// 使用transaction，將密碼存入資料庫
 	transaction := database.SqlSession.Begin()
 	err = transaction.CreateInBatches(&passwordModels, 100).Error
@@ -101,15 +98,128 @@ func (p PasswordService) CreatePassword(voteId uuid.UUID, number int, length int
 	return transaction.Commit().Error
 }
 
-// UpdatePasswordStatus 更新密碼狀態
-func (p PasswordService) UpdatePasswordStatus(voteId uuid.UUID, passwordIDs []any, status bool) error {
-	err := database.SqlSession.Model(&model.Password{}).
-		Where("vote_id = ? AND id IN ?", voteId, passwordIDs).
-		Update("status", status).Error
+// generatePasswords 生成加密後的密碼，重複或已存在於投票中的密碼會重新生成，尚未寫入資料庫
+func (p PasswordService) generatePasswords(voteId uuid.UUID, number int, length int, format string) ([]model.Password, error) {
+	passwordUtil := &utils.Password{}
+	passwordModels := make([]model.Password, 0, number)
+	digests := make(map[string]struct{}, number)
+
+	// 重複的密碼會被捨棄並重新生成，直到數量足夠
+	for attempt := 0; len(passwordModels) < number; attempt++ {
+		if attempt >= 10 {
+			return nil, fmt.Errorf("unable to generate %d unique passwords, try a longer length", number)
+		}
+
+		passwords, err := passwordUtil.GeneratePassword(number-len(passwordModels), length, format)
+		if err != nil {
+			return nil, err
+		}
+
+		candidates := make(map[string]string, len(passwords))
+		for _, password := range passwords {
+			digest, err := passwordUtil.Digest(voteId.String(), password)
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := digests[digest]; !ok {
+				candidates[digest] = password
+			}
+		}
+
+		existing, err := p.existingDigests(voteId, candidates)
+		if err != nil {
+			return nil, err
+		}
+
+		for digest, password := range candidates {
+			if _, ok := existing[digest]; ok {
+				continue
+			}
+			passwordEncrypt, err := passwordUtil.Encrypt(password)
+			if err != nil {
+				return nil, err
+			}
+			digests[digest] = struct{}{}
+			passwordModels = append(passwordModels, model.Password{
+				VoteID:   voteId,
+				Password: passwordEncrypt,
+				Digest:   digest,
+				State:    enum.CredentialIssued,
+			})
+		}
+	}
+
+	return passwordModels, nil
+}
+
+// DecryptPassword 解密指定投票中的密碼，只會回傳屬於該投票的密碼
+func (p PasswordService) DecryptPassword(voteId uuid.UUID, ids []uint64) (map[uint64]string, error) {
+	var passwords []model.Password
+	err := database.SqlSession.
+		Select([]string{"id", "password"}).
+		Where("vote_id = ? AND id IN ?", voteId, ids).
+		Find(&passwords).Error
+	if err != nil {
+		return nil, err
+	}
+
+	passwordUtil := &utils.Password{}
+	decrypts := make(map[uint64]string, len(passwords))
+	for _, password := range passwords {
+		decrypt, err := passwordUtil.Decrypt(password.Password)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt password %d: %w", password.ID, err)
+		}
+		decrypts[password.ID] = decrypt
+	}
+
+	return decrypts, nil
+}
+
+// existingDigests 回傳投票中已存在的摘要
+func (p PasswordService) existingDigests(voteId uuid.UUID, candidates map[string]string) (map[string]struct{}, error) {
+	existing := make(map[string]struct{})
+	if len(candidates) == 0 {
+		return existing, nil
+	}
+
+	digests := make([]string, 0, len(candidates))
+	for digest := range candidates {
+		digests = append(digests, digest)
+	}
 
+	var found []string
+	err := database.SqlSession.Model(&model.Password{}).
+		Where("vote_id = ? AND digest IN ?", voteId, digests).
+		Pluck("digest", &found).Error
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
-}
\ No newline at end of file
+	for _, digest := range found {
+		existing[digest] = struct{}{}
+	}
+
+	return existing, nil
+}
+
+// UpdatePasswordStatus 啟用或停用密碼，只會變更尚未投票、撤銷或過期的密碼
+func (p PasswordService) UpdatePasswordStatus(voteId uuid.UUID, passwordIDs []any, status bool, actorId *uint64) error {
+	from, change := enum.CredentialActivated, credentialChange{action: enum.CredentialDeactivate, to: enum.CredentialIssued, actorId: actorId}
+	if status {
+		from, change = enum.CredentialIssued, credentialChange{action: enum.CredentialActivate, to: enum.CredentialActivated, actorId: actorId}
+	}
+
+	return database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		var passwords []model.Password
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("vote_id = ? AND id IN ? AND state = ?", voteId, passwordIDs, from).
+			Order("id ASC").
+			Find(&passwords).Error
+		if err != nil {
+			return err
+		}
+
+		return changeCredentialState(tx, passwords, change)
+	})
+}
@@ -0,0 +1,142 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"os"
+	"strings"
+	"vote/app/model"
+	"vote/app/utils"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	sheetMargin  = 10.0
+	sheetPadding = 4.0
+)
+
+// ExportPasswordSheet 將投票的密碼輸出為可列印的 PDF，每張密碼條包含投票標題、密碼與登入用的 QR code
+func (p PasswordService) ExportPasswordSheet(w io.Writer, vote model.Vote, options model.PasswordExport) error {
+	var passwords []model.Password
+	err := passwordsByStatus(vote.Uuid, options.Status).
+		Select([]string{"id", "password"}).
+		Order("id ASC").
+		Find(&passwords).Error
+	if err != nil {
+		return err
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(sheetMargin, sheetMargin, sheetMargin)
+	pdf.SetAutoPageBreak(false, 0)
+	font, translate := sheetFont(pdf)
+
+	perPage := max(options.PerPage, 1)
+	columns := 1
+	if perPage >= 4 {
+		columns = 2
+	}
+	rows := int(math.Ceil(float64(perPage) / float64(columns)))
+	pageWidth, pageHeight := pdf.GetPageSize()
+	slipWidth := (pageWidth - 2*sheetMargin) / float64(columns)
+	slipHeight := (pageHeight - 2*sheetMargin) / float64(rows)
+
+	passwordUtil := &utils.Password{}
+	for i, password := range passwords {
+		if i%perPage == 0 {
+			pdf.AddPage()
+		}
+
+		plain, err := passwordUtil.Decrypt(password.Password)
+		if err != nil {
+			return fmt.Errorf("failed to decrypt password %d: %w", password.ID, err)
+		}
+
+		slot := i % perPage
+		x := sheetMargin + float64(slot%columns)*slipWidth
+		y := sheetMargin + float64(slot/columns)*slipHeight
+		if err := drawPasswordSlip(pdf, font, translate, vote, plain, x, y, slipWidth, slipHeight); err != nil {
+			return err
+		}
+	}
+
+	// 沒有密碼時仍輸出一頁空白文件
+	if len(passwords) == 0 {
+		pdf.AddPage()
+	}
+
+	return pdf.Output(w)
+}
+
+// drawPasswordSlip 繪製單張密碼條，左側為文字，右側為 QR code，外框以虛線作為裁切線
+func drawPasswordSlip(pdf *fpdf.Fpdf, font string, translate func(string) string, vote model.Vote, password string, x, y, width, height float64) error {
+	pdf.SetDashPattern([]float64{1, 1}, 0)
+	pdf.Rect(x, y, width, height, "D")
+	pdf.SetDashPattern([]float64{}, 0)
+
+	qrSize := math.Min(height-2*sheetPadding, width/2.5)
+	png, err := qrcode.Encode(VoterLoginURL(vote, password), qrcode.Medium, 256)
+	if err != nil {
+		return err
+	}
+	imageName := "qr-" + password
+	pdf.RegisterImageOptionsReader(imageName, fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(png))
+	pdf.ImageOptions(imageName, x+width-sheetPadding-qrSize, y+(height-qrSize)/2, qrSize, qrSize, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+	textWidth := width - qrSize - 3*sheetPadding
+	pdf.SetXY(x+sheetPadding, y+sheetPadding)
+	pdf.SetFont(font, "", 12)
+	pdf.MultiCell(textWidth, 6, translate(vote.Title), "", "L", false)
+
+	pdf.SetX(x + sheetPadding)
+	pdf.SetFont(font, "", 9)
+	pdf.CellFormat(textWidth, 6, translate(vote.StartTime.Format("2006-01-02 15:04")+" ~ "+vote.EndTime.Format("2006-01-02 15:04")), "", 1, "L", false, 0, "")
+
+	pdf.SetX(x + sheetPadding)
+	pdf.SetFont(font, "", 16)
+	pdf.CellFormat(textWidth, 10, password, "", 1, "L", false, 0, "")
+
+	return pdf.Error()
+}
+
+// sheetFont 設定 PDF 字型，有設定 PDF_FONT_PATH 時使用該 TrueType 字型以支援非拉丁文字，
+// 否則使用內建的 Helvetica，無法顯示的字元會被取代。
+func sheetFont(pdf *fpdf.Fpdf) (string, func(string) string) {
+	if path := os.Getenv("PDF_FONT_PATH"); path != "" {
+		if data, err := os.ReadFile(path); err == nil {
+			pdf.AddUTF8FontFromBytes("sheet", "", data)
+			if pdf.Ok() {
+				return "sheet", func(text string) string { return text }
+			}
+			pdf.ClearError()
+		}
+	}
+
+	return "Helvetica", pdf.UnicodeTranslatorFromDescriptor("")
+}
+
+// VoterLoginURL 投票者的登入網址，包含投票 UUID 與密碼，密碼為空時只包含投票 UUID
+func VoterLoginURL(vote model.Vote, password string) string {
+	base := os.Getenv("APP_VOTER_LOGIN_URL")
+	if base == "" {
+		base = strings.TrimRight(os.Getenv("HOST"), "/") + "/voter/login"
+	}
+
+	query := url.Values{}
+	query.Set("vote_id", vote.Uuid.String())
+	if password != "" {
+		query.Set("password", password)
+	}
+
+	separator := "?"
+	if strings.Contains(base, "?") {
+		separator = "&"
+	}
+
+	return base + separator + query.Encode()
+}
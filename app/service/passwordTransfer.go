@@ -0,0 +1,274 @@
+package service
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"vote/app/database"
+	"vote/app/model"
+	"vote/app/utils"
+
+	"github.com/google/uuid"
+	"github.com/xuri/excelize/v2"
+	"gorm.io/gorm"
+)
+
+const (
+	passwordBatchSize = 100
+	// 密文欄位長度為 100，base64 編碼後須能容納 IV 與密碼
+	passwordMaxLength = 50
+	passwordMinLength = 6
+)
+
+var passwordExportHeader = []string{"id", "password", "status", "state", "expires_at", "created_at"}
+
+// formulaPrefixes 試算表開啟 CSV 時會當成公式執行的開頭字元
+const formulaPrefixes = "=+-@"
+
+// ExportPasswords 將投票的密碼解密後以 CSV 或 XLSX 格式輸出，分批讀取以支援大量密碼
+func (p PasswordService) ExportPasswords(w io.Writer, voteId uuid.UUID, options model.PasswordDownload) error {
+	if options.Format == "xlsx" {
+		return p.exportPasswordsXlsx(w, voteId, options.Status)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(passwordExportHeader); err != nil {
+		return err
+	}
+
+	err := eachPasswordRow(voteId, options.Status, func(row []string) error {
+		for i := range row {
+			row[i] = neutralizeFormula(row[i])
+		}
+		return writer.Write(row)
+	}, writer.Flush)
+	if err != nil {
+		return err
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// neutralizeFormula 在會被試算表當成公式的儲存格前加上單引號。
+// 匯入時已拒絕這類密碼，這裡防範匯入檢查之前寫入的資料。
+func neutralizeFormula(value string) string {
+	if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+// exportPasswordsXlsx 以串流方式寫入 XLSX 工作表，字串儲存格不會被當成公式
+func (p PasswordService) exportPasswordsXlsx(w io.Writer, voteId uuid.UUID, status string) error {
+	file := excelize.NewFile()
+	defer file.Close()
+
+	sheet := file.GetSheetName(0)
+	stream, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+
+	rowNumber := 1
+	writeRow := func(row []string) error {
+		cells := make([]any, len(row))
+		for i, value := range row {
+			cells[i] = value
+		}
+		cell, err := excelize.CoordinatesToCellName(1, rowNumber)
+		if err != nil {
+			return err
+		}
+		rowNumber++
+		return stream.SetRow(cell, cells)
+	}
+
+	if err := writeRow(passwordExportHeader); err != nil {
+		return err
+	}
+	if err := eachPasswordRow(voteId, status, writeRow, nil); err != nil {
+		return err
+	}
+	if err := stream.Flush(); err != nil {
+		return err
+	}
+
+	_, err = file.WriteTo(w)
+	return err
+}
+
+// eachPasswordRow 分批讀取並解密密碼，每一列呼叫 write，每批結束時呼叫 flush
+func eachPasswordRow(voteId uuid.UUID, status string, write func([]string) error, flush func()) error {
+	passwordUtil := &utils.Password{}
+	var passwords []model.Password
+
+	return passwordsByStatus(voteId, status).
+		Select([]string{"id", "password", "status", "state", "expires_at", "created_at"}).
+		FindInBatches(&passwords, 500, func(tx *gorm.DB, batch int) error {
+			for _, password := range passwords {
+				plain, err := passwordUtil.Decrypt(password.Password)
+				if err != nil {
+					return fmt.Errorf("failed to decrypt password %d: %w", password.ID, err)
+				}
+
+				status := "inactive"
+				if password.Status {
+					status = "active"
+				}
+				expiresAt := ""
+				if password.ExpiresAt != nil {
+					expiresAt = password.ExpiresAt.Format("2006-01-02 15:04:05")
+				}
+				row := []string{
+					strconv.FormatUint(password.ID, 10),
+					plain,
+					status,
+					string(password.State),
+					expiresAt,
+					password.CreatedAt.Format("2006-01-02 15:04:05"),
+				}
+				if err := write(row); err != nil {
+					return err
+				}
+			}
+			if flush != nil {
+				flush()
+			}
+
+			return nil
+		}).Error
+}
+
+// ImportPasswords 匯入預先發放的密碼。第一欄為密碼，若第一列為 password 標題則略過。
+// 格式錯誤或重複的列會列入報告而不會寫入，其餘的密碼分批寫入。
+func (p PasswordService) ImportPasswords(voteId uuid.UUID, r io.Reader, active bool) (*model.PasswordImportReport, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	passwordUtil := &utils.Password{}
+	report := &model.PasswordImportReport{Errors: []model.PasswordImportError{}}
+	candidates := make(map[string]string)
+	rows := make(map[string]int)
+	var digests []string
+
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			report.Errors = append(report.Errors, model.PasswordImportError{Row: row, Message: parseErr.Err.Error()})
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		password := ""
+		if len(record) > 0 {
+			password = strings.TrimSpace(record[0])
+		}
+		if row == 1 && strings.EqualFold(password, "password") {
+			continue
+		}
+		if message := validateImportedPassword(password); message != "" {
+			report.Errors = append(report.Errors, model.PasswordImportError{Row: row, Message: message})
+			continue
+		}
+
+		digest, err := passwordUtil.Digest(voteId.String(), password)
+		if err != nil {
+			return nil, err
+		}
+		if first, ok := rows[digest]; ok {
+			report.Errors = append(report.Errors, model.PasswordImportError{Row: row, Message: fmt.Sprintf("duplicate of row %d", first)})
+			continue
+		}
+		rows[digest] = row
+		candidates[digest] = password
+		digests = append(digests, digest)
+	}
+
+	existing, err := p.existingDigests(voteId, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	passwordModels := make([]model.Password, 0, len(digests))
+	for _, digest := range digests {
+		if _, ok := existing[digest]; ok {
+			report.Errors = append(report.Errors, model.PasswordImportError{Row: rows[digest], Message: "password already exists in this vote"})
+			continue
+		}
+
+		passwordEncrypt, err := passwordUtil.Encrypt(candidates[digest])
+		if err != nil {
+			return nil, err
+		}
+		passwordModels = append(passwordModels, model.Password{
+			VoteID:   voteId,
+			Password: passwordEncrypt,
+			Digest:   digest,
+			Status:   active,
+			State:    credentialState(active),
+		})
+	}
+
+	if len(passwordModels) > 0 {
+		// 使用transaction，將密碼存入資料庫
+		transaction := database.SqlSession.Begin()
+		err = transaction.CreateInBatches(&passwordModels, passwordBatchSize).Error
+		if err != nil {
+			transaction.Rollback()
+			return nil, err
+		}
+		if err := transaction.Commit().Error; err != nil {
+			return nil, err
+		}
+	}
+	report.Imported = len(passwordModels)
+
+	return report, nil
+}
+
+// validateImportedPassword 檢查匯入的密碼，回傳錯誤訊息，合法時回傳空字串
+func validateImportedPassword(password string) string {
+	if password == "" {
+		return "password is empty"
+	}
+	if len(password) < passwordMinLength || len(password) > passwordMaxLength {
+		return fmt.Sprintf("password must be %d to %d characters", passwordMinLength, passwordMaxLength)
+	}
+	for _, char := range password {
+		if char > unicode.MaxASCII || !unicode.IsPrint(char) || unicode.IsSpace(char) {
+			return "password may only contain printable ASCII characters without spaces"
+		}
+	}
+	// 匯出後以試算表開啟時會被當成公式
+	if strings.ContainsRune(formulaPrefixes, rune(password[0])) {
+		return "password may not start with =, +, - or @"
+	}
+
+	return ""
+}
+
+// passwordsByStatus 依狀態篩選投票的密碼，status 為 active、inactive 或 all
+func passwordsByStatus(voteId uuid.UUID, status string) *gorm.DB {
+	query := database.SqlSession.Model(&model.Password{}).Where("vote_id = ?", voteId)
+	switch status {
+	case "active":
+		query = query.Where("status = true")
+	case "inactive":
+		query = query.Where("status = false")
+	}
+
+	return query
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 	"vote/app/database"
+	"vote/app/enum"
 	"vote/app/model"
 	"vote/app/repository"
 	"vote/app/utils"
@@ -65,15 +66,42 @@ func (q QuestionService) GetQuestions(isAdmin bool, userId uint64, questionQuery
 // CreateOneQuestion 創建新的問題。
 func (q QuestionService) CreateQuestion(form model.QuestionCreate) (*model.Question, error) {
 	// check vote exists
-	_, err := NewVoteService().GetVote(form.VoteID)
+	vote, err := NewVoteService().GetVote(form.VoteID)
 	if err != nil {
 		return nil, fmt.Errorf("vote not found")
 	}
+	if err := CheckVoteEditable(*vote); err != nil {
+		return nil, err
+	}
+
+	method := form.Method
+	if method == "" {
+		method = enum.Plurality
+	}
+
+	seats := form.Seats
+	if seats < 1 {
+		seats = 1
+	}
+
+	if form.MaxSelections > 0 && form.MinSelections > form.MaxSelections {
+		return nil, fmt.Errorf("min_selections must not exceed max_selections")
+	}
+
+	maxScore := form.MaxScore
+	if maxScore < 1 {
+		maxScore = 5
+	}
 
 	question := model.Question{
 		VoteID:      form.VoteID,
 		Title:       form.Title,
 		Description: form.Description,
+		Method:      method,
+		Seats:       seats,
+		MaxScore:    maxScore,
+		MinSelections: max(form.MinSelections, 0),
+		MaxSelections: max(form.MaxSelections, 0),
 	}
 
 	insertErr := database.SqlSession.Model(&model.Question{}).Create(&question).Error
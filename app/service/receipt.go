@@ -0,0 +1,112 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrReceiptSuperseded 回條的選票已被同一組密碼重新投票取代，不計入開票
+var ErrReceiptSuperseded = errors.New("ballots of this receipt were superseded by a later ballot")
+
+// BallotReceiptCode 以選票內容與伺服器產生的隨機值計算回條代碼。
+// 隨機值不會保存也不會回傳，無法由代碼反推投票內容。
+func BallotReceiptCode(voteId uuid.UUID, selections model.BallotSelections, nonce []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(voteId.String()))
+	for _, questionId := range sortedQuestionIds(selections) {
+		fmt.Fprintf(hash, "|q%d", questionId)
+		marks := selections[questionId]
+		for _, candidateId := range sortedCandidateIds(marks) {
+			fmt.Fprintf(hash, ":c%d=%d", candidateId, marks[candidateId])
+		}
+	}
+	hash.Write([]byte("|"))
+	hash.Write(nonce)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// EncryptedReceiptCode 以加密選票的密文與伺服器產生的隨機值計算回條代碼
+func EncryptedReceiptCode(voteId uuid.UUID, ballot model.EncryptedBallot, nonce []byte) string {
+	questions := append([]model.EncryptedQuestion(nil), ballot.Questions...)
+	sort.Slice(questions, func(i, j int) bool { return questions[i].QuestionID < questions[j].QuestionID })
+
+	hash := sha256.New()
+	hash.Write([]byte(voteId.String()))
+	for _, question := range questions {
+		fmt.Fprintf(hash, "|q%d", question.QuestionID)
+		selections := append([]model.EncryptedSelection(nil), question.Selections...)
+		sort.Slice(selections, func(i, j int) bool { return selections[i].CandidateID < selections[j].CandidateID })
+		for _, selection := range selections {
+			fmt.Fprintf(hash, ":c%d=%s,%s", selection.CandidateID, selection.Ciphertext.A, selection.Ciphertext.B)
+		}
+	}
+	hash.Write([]byte("|"))
+	hash.Write(nonce)
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// LookupReceipt 依回條代碼查詢選票是否已計入開票結果，不會回傳投票內容
+func (b BallotService) LookupReceipt(code string) (*model.BallotReceiptLookup, error) {
+	receipt := model.BallotReceipt{}
+	err := database.SqlSession.
+		Where("code = ?", strings.ToLower(strings.TrimSpace(code))).
+		First(&receipt).Error
+	if err != nil {
+		return nil, err
+	}
+
+	vote, err := NewVoteService().GetVote(receipt.VoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	lookup := &model.BallotReceiptLookup{
+		Code:       receipt.Code,
+		VoteID:     vote.Uuid,
+		VoteTitle:  vote.Title,
+		VoteStatus: vote.Status,
+		Superseded: receipt.Superseded,
+	}
+	err = database.SqlSession.Model(&model.Ballot{}).
+		Where("receipt_id = ?", receipt.ID).
+		Count(&lookup.Questions).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if vote.Status < enum.Tallied {
+		return lookup, nil
+	}
+
+	// 開票時間取最後一次轉換為已開票的時間，在此之前送出的選票皆已計入
+	transition := model.VoteTransition{}
+	err = database.SqlSession.
+		Where("vote_id = ? AND to_status = ?", vote.Uuid, enum.Tallied).
+		Order("id DESC").
+		First(&transition).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// 狀態由舊資料回填，沒有轉換紀錄
+		lookup.Included = lookup.Questions > 0 && !receipt.Superseded
+		return lookup, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	talliedAt := transition.CreatedAt
+	lookup.TalliedAt = &talliedAt
+	lookup.Included = lookup.Questions > 0 && !receipt.Superseded && !receipt.CreatedAt.After(talliedAt)
+
+	return lookup, nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/mail"
+	"vote/app/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ReminderService struct {
+}
+
+func NewReminderService() ReminderService {
+	return ReminderService{}
+}
+
+// ParseReminderOffsets 解析提醒時間，必須為正數、不重複，且早於投票期間長度
+func ParseReminderOffsets(vote model.Vote, offsets []string) ([]int, error) {
+	seconds := make([]int, 0, len(offsets))
+	seen := make(map[int]struct{}, len(offsets))
+	for _, offset := range offsets {
+		duration, err := time.ParseDuration(offset)
+		if err != nil {
+			return nil, fmt.Errorf("invalid reminder offset %s", offset)
+		}
+		if duration < time.Minute || duration%time.Second != 0 {
+			return nil, fmt.Errorf("reminder offset %s must be whole seconds and at least 1m", offset)
+		}
+		if duration >= vote.EndTime.Sub(vote.StartTime) {
+			return nil, fmt.Errorf("reminder offset %s is longer than the voting period", offset)
+		}
+
+		value := int(duration / time.Second)
+		if _, ok := seen[value]; ok {
+			return nil, fmt.Errorf("duplicate reminder offset %s", offset)
+		}
+		seen[value] = struct{}{}
+		seconds = append(seconds, value)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(seconds)))
+
+	return seconds, nil
+}
+
+// SetReminders 設定投票的提醒時間，已執行的提醒會保留作為紀錄
+func (r ReminderService) SetReminders(vote model.Vote, offsets []string) ([]model.VoteReminder, error) {
+	if vote.Status > enum.Open {
+		return nil, ErrVoteClosed
+	}
+
+	seconds, err := ParseReminderOffsets(vote, offsets)
+	if err != nil {
+		return nil, err
+	}
+
+	err = database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		var ran []int
+		err := tx.Model(&model.VoteReminder{}).
+			Where("vote_id = ? AND run_at IS NOT NULL", vote.Uuid).
+			Pluck("offset_seconds", &ran).Error
+		if err != nil {
+			return err
+		}
+		existing := make(map[int]struct{}, len(ran))
+		for _, offset := range ran {
+			existing[offset] = struct{}{}
+		}
+
+		err = tx.Where("vote_id = ? AND run_at IS NULL", vote.Uuid).Delete(&model.VoteReminder{}).Error
+		if err != nil {
+			return err
+		}
+
+		reminders := make([]model.VoteReminder, 0, len(seconds))
+		for _, offset := range seconds {
+			if _, ok := existing[offset]; ok {
+				continue
+			}
+			reminders = append(reminders, model.VoteReminder{VoteID: vote.Uuid, Offset: offset})
+		}
+		if len(reminders) == 0 {
+			return nil
+		}
+
+		return tx.Create(&reminders).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetReminders(vote.Uuid)
+}
+
+// GetReminders 取得投票的提醒與執行紀錄
+func (r ReminderService) GetReminders(voteId uuid.UUID) ([]model.VoteReminder, error) {
+	var reminders []model.VoteReminder
+	err := database.SqlSession.
+		Where("vote_id = ?", voteId).
+		Order("offset_seconds DESC").
+		Find(&reminders).Error
+
+	return reminders, err
+}
+
+// DueReminders 取得投票開放中且已到寄送時間的提醒
+func (r ReminderService) DueReminders(now time.Time) ([]model.VoteReminder, error) {
+	var votes []model.Vote
+	err := database.SqlSession.
+		Select("uuid", "end_time").
+		Where("status = ? AND end_time > ?", enum.Open, now).
+		Find(&votes).Error
+	if err != nil || len(votes) == 0 {
+		return nil, err
+	}
+
+	endTimes := make(map[uuid.UUID]time.Time, len(votes))
+	voteIds := make([]uuid.UUID, 0, len(votes))
+	for _, vote := range votes {
+		endTimes[vote.Uuid] = vote.EndTime
+		voteIds = append(voteIds, vote.Uuid)
+	}
+
+	var reminders []model.VoteReminder
+	err = database.SqlSession.
+		Where("vote_id IN ? AND run_at IS NULL", voteIds).
+		Order("id ASC").
+		Find(&reminders).Error
+	if err != nil {
+		return nil, err
+	}
+
+	due := make([]model.VoteReminder, 0, len(reminders))
+	for _, reminder := range reminders {
+		sendAt := endTimes[reminder.VoteID].Add(-time.Duration(reminder.Offset) * time.Second)
+		if !now.Before(sendAt) {
+			due = append(due, reminder)
+		}
+	}
+
+	return due, nil
+}
+
+// RunReminder 將提醒郵件排入佇列，寄給名冊中密碼仍可使用且尚未投票的投票者，並記錄人數。
+// 提醒只會執行一次，已被其他服務執行時回傳 false。
+func (r ReminderService) RunReminder(reminder model.VoteReminder, now time.Time) (*model.VoteReminder, bool, error) {
+	ran := false
+	err := database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&model.VoteReminder{}).
+			Where("id = ? AND run_at IS NULL", reminder.ID).
+			Update("run_at", now)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		ran = true
+
+		var total int64
+		if err := tx.Model(&model.Voter{}).Where("vote_id = ?", reminder.VoteID).Count(&total).Error; err != nil {
+			return err
+		}
+
+		// 與 CheckIfVoterHasVoted 相同，密碼已有已投票紀錄即視為已投票；撤銷或過期的密碼無法投票，不寄提醒
+		var voters []model.Voter
+		err := tx.
+			Where("vote_id = ?", reminder.VoteID).
+			Where("password_id IN (SELECT id FROM passwords WHERE state IN ?)", []enum.CredentialState{enum.CredentialIssued, enum.CredentialActivated}).
+			Where("NOT EXISTS (SELECT 1 FROM participations WHERE participations.password_id = voters.password_id)").
+			Order("id ASC").
+			Find(&voters).Error
+		if err != nil {
+			return err
+		}
+
+		queued, err := NewMailQueueService().EnqueueVoters(tx, voters, mail.Reminder)
+		if err != nil {
+			return err
+		}
+
+		reminder.RunAt = &now
+		reminder.Recipients = queued
+		reminder.Skipped = int(total) - len(voters)
+
+		return tx.Model(&model.VoteReminder{}).
+			Where("id = ?", reminder.ID).
+			Updates(map[string]any{
+				"recipients": reminder.Recipients,
+				"skipped":    reminder.Skipped,
+			}).Error
+	})
+	if err != nil || !ran {
+		return nil, false, err
+	}
+
+	return &reminder, true, nil
+}
+
+// errVoterHasVoted 投票者在提醒寄出前已投票
+var errVoterHasVoted = errors.New("voter has already voted")
+
+// errReminderVoteClosed 提醒寄出前投票已結束，例如重試時已超過結束時間
+var errReminderVoteClosed = errors.New("vote is no longer open")
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/mail"
+	"vote/app/model"
+	"vote/app/utils"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+const voteSchedulerLease = "vote-scheduler"
+
+// VoteHook 投票狀態由排程轉換後執行的掛鉤
+type VoteHook func(vote model.Vote, from enum.VoteStatus, to enum.VoteStatus)
+
+// VoteScheduler 依投票的開始與結束時間自動開放、結束投票並開票。
+// 每次執行都會處理所有已到期的投票，服務重啟後可補上錯過的轉換；
+// 多個服務同時運行時以資料庫租約確保同一時間只有一個排程在執行。
+type VoteScheduler struct {
+	Interval time.Duration
+	LeaseTTL time.Duration
+	holder   string
+	hooks    []VoteHook
+}
+
+// NewVoteScheduler 建立排程，間隔秒數可由 SCHEDULER_INTERVAL 設定，預設 30 秒
+func NewVoteScheduler() *VoteScheduler {
+	interval := 30 * time.Second
+	if seconds, err := strconv.Atoi(os.Getenv("SCHEDULER_INTERVAL")); err == nil && seconds > 0 {
+		interval = time.Duration(seconds) * time.Second
+	}
+
+	hostname, _ := os.Hostname()
+
+	return &VoteScheduler{
+		Interval: interval,
+		LeaseTTL: 3 * interval,
+		holder:   fmt.Sprintf("%s-%d-%s", hostname, os.Getpid(), uuid.NewString()[:8]),
+	}
+}
+
+// OnTransition 註冊狀態轉換後執行的掛鉤
+func (s *VoteScheduler) OnTransition(hook VoteHook) {
+	s.hooks = append(s.hooks, hook)
+}
+
+// Start 在背景執行排程，啟動時立即執行一次，直到 ctx 結束
+func (s *VoteScheduler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+
+		for {
+			if err := s.Tick(time.Now()); err != nil {
+				schedulerLogger().Error("error: ", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Tick 取得租約後處理所有到期的投票：
+// 已排程且到達開始時間者開放投票，開放中且到達結束時間者結束投票，
+// 已結束且超過寬限期者開票，並寄送已到時間的提醒、將過期的密碼標記為過期。
+func (s *VoteScheduler) Tick(now time.Time) error {
+	acquired, err := s.acquireLease(now)
+	if err != nil || !acquired {
+		return err
+	}
+
+	var errs []error
+	errs = append(errs, s.advance(enum.Scheduled, enum.Open, "start_time <= ?", now))
+	errs = append(errs, s.advance(enum.Open, enum.Closed, "end_time <= ?", now))
+	errs = append(errs, s.tally(now))
+	errs = append(errs, s.remind(now))
+	errs = append(errs, s.expireCredentials(now))
+
+	return errors.Join(errs...)
+}
+
+// acquireLease 取得或延長租約，租約由其他服務持有且尚未過期時回傳 false
+func (s *VoteScheduler) acquireLease(now time.Time) (bool, error) {
+	result := database.SqlSession.Exec(`
+		INSERT INTO scheduler_leases (name, holder, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET holder = EXCLUDED.holder, expires_at = EXCLUDED.expires_at
+		WHERE scheduler_leases.holder = EXCLUDED.holder OR scheduler_leases.expires_at < ?`,
+		voteSchedulerLease, s.holder, now.Add(s.LeaseTTL), now,
+	)
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// advance 將符合條件的投票由 from 轉換為 to
+func (s *VoteScheduler) advance(from enum.VoteStatus, to enum.VoteStatus, condition string, now time.Time) error {
+	var votes []model.Vote
+	err := database.SqlSession.
+		Where("status = ?", from).
+		Where(condition, now).
+		Order("id ASC").
+		Find(&votes).Error
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, vote := range votes {
+		updated, err := NewVoteService().TransitionVote(vote.Uuid, to, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("vote %s: %w", vote.Uuid, err))
+			continue
+		}
+		s.fire(*updated, from, to)
+	}
+
+	return errors.Join(errs...)
+}
+
+// tally 對已結束且超過寬限期的投票開票，開票失敗的投票會在下次排程重試。
+// 寬限期與 TallyVote 相同由 ballotsFinal 判斷
+func (s *VoteScheduler) tally(now time.Time) error {
+	var votes []model.Vote
+	err := database.SqlSession.
+		Where("status = ?", enum.Closed).
+		Order("id ASC").
+		Find(&votes).Error
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, vote := range votes {
+		if !ballotsFinal(vote, now) {
+			continue
+		}
+		if _, err := NewTallyService().TallyVote(vote.Uuid, nil); err != nil {
+			// 加密投票等受託人送出部分解密後才開票
+			if !errors.Is(err, ErrDecryptionPending) {
+				errs = append(errs, fmt.Errorf("vote %s: %w", vote.Uuid, err))
+			}
+			continue
+		}
+		vote.Status = enum.Tallied
+		s.fire(vote, enum.Closed, enum.Tallied)
+	}
+
+	return errors.Join(errs...)
+}
+
+// remind 執行已到時間的提醒
+func (s *VoteScheduler) remind(now time.Time) error {
+	reminders, err := NewReminderService().DueReminders(now)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, reminder := range reminders {
+		result, ran, err := NewReminderService().RunReminder(reminder, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reminder %d: %w", reminder.ID, err))
+			continue
+		}
+		if ran {
+			schedulerLogger().Info("Vote ", result.VoteID, ": reminder ", time.Duration(result.Offset)*time.Second,
+				" before close queued ", result.Recipients, ", skipped ", result.Skipped, " voted")
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// expireCredentials 將超過過期時間的密碼標記為過期
+func (s *VoteScheduler) expireCredentials(now time.Time) error {
+	expired, err := NewPasswordService().ExpireCredentials(now)
+	if expired > 0 {
+		schedulerLogger().Info("Expired ", expired, " credentials")
+	}
+
+	return err
+}
+
+// fire 執行所有掛鉤，單一掛鉤發生錯誤不影響其他掛鉤與排程
+func (s *VoteScheduler) fire(vote model.Vote, from enum.VoteStatus, to enum.VoteStatus) {
+	schedulerLogger().Info("Vote ", vote.Uuid, ": ", from, " -> ", to)
+
+	for _, hook := range s.hooks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					schedulerLogger().Error("hook panic: ", r)
+				}
+			}()
+			hook(vote, from, to)
+		}()
+	}
+}
+
+// NotifyVoteOwner 以電子郵件通知投票建立者投票已開放、結束或完成開票
+func NotifyVoteOwner(vote model.Vote, from enum.VoteStatus, to enum.VoteStatus) {
+	owner, err := NewUserService().GetUserById(int64(vote.UserID))
+	if err != nil {
+		schedulerLogger().Error("error: ", err)
+		return
+	}
+
+	// 排入郵件佇列，寄信不阻塞排程
+	voteId := vote.Uuid
+	_, err = NewMailQueueService().Enqueue(&voteId, owner.Email, mail.VoteStatus, "", mail.VoteStatusData{
+		Name:      owner.Account,
+		VoteTitle: vote.Title,
+		Status:    to.String(),
+	})
+	if err != nil {
+		schedulerLogger().Error("notify error: ", err)
+	}
+}
+
+func schedulerLogger() *logrus.Entry {
+	return utils.Logger().WithFields(logrus.Fields{
+		"name": "Scheduler",
+	})
+}
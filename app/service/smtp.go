@@ -1,13 +1,9 @@
 package service
 
 import (
-	"net/smtp"
-	"os"
-	"sync"
-
+	"vote/app/mail"
 	"vote/app/utils"
 
-	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 )
 
@@ -18,48 +14,39 @@ func NewSmtpService() SmtpService {
 	return SmtpService{}
 }
 
-func (s SmtpService) Send(title string, body string, to string, wg *sync.WaitGroup) {
-	defer wg.Done()
-	
-	envErr := godotenv.Load()
-	if envErr != nil {
-		panic(envErr)
+// SendMail 寄送純文字郵件
+func (s SmtpService) SendMail(title string, body string, to string) error {
+	mailer, err := mail.Default()
+	if err != nil {
+		return err
 	}
 
-	from := os.Getenv("SMTP_FROM")
-	username := os.Getenv("SMTP_USERNAME")
-	password := os.Getenv("SMTP_PASSWORD")
-	port := os.Getenv("SMTP_PORT")
-	host := os.Getenv("SMTP_HOST")
-
-	msg := "From: " + from + "\n" +
-		   "To: " + to + "\n" +
-		   "Subject: " + title + "\n" +
-		   body
+	return s.log(to, mailer.Send(mail.Message{
+		To:      []string{to},
+		Subject: title,
+		Text:    body,
+	}))
+}
 
-	err := smtp.SendMail(host + ":" + port,
-		   smtp.PlainAuth("", username, password, host),
-		   from, []string{to}, []byte(msg))
-		   
-	if err != nil{
-		utils.Logger().WithFields(logrus.Fields{
-			"name": "Smtp",
-		}).Error("error: ", err)
-		return
+// SendTemplate 以郵件範本寄送，language 為空時使用 MAIL_LANGUAGE
+func (s SmtpService) SendTemplate(to string, template string, language string, data any) error {
+	mailer, err := mail.Default()
+	if err != nil {
+		return err
 	}
 
-	utils.Logger().WithFields(logrus.Fields{
-		"name": "Smtp",
-	}).Info("Send from: ", from + ", To: ", to)
+	return s.log(to, mailer.SendTemplate(to, template, language, data))
 }
 
-func (s SmtpService) MultiSend(email string) {
-	var wg sync.WaitGroup
-	wg.Add(2)
-	go s.Send("Register Notification", "Welcome to become our membership", email, &wg)
-	go s.Send("Please review the rules", "Rules1:..........", email, &wg)
-	wg.Wait()
-	utils.Logger().WithFields(logrus.Fields{
+func (s SmtpService) log(to string, err error) error {
+	logger := utils.Logger().WithFields(logrus.Fields{
 		"name": "Smtp",
-	}).Info("Finished all tasks")
-}
\ No newline at end of file
+	})
+	if err != nil {
+		logger.Error("error: ", err)
+		return err
+	}
+	logger.Info("Send to: ", to)
+
+	return nil
+}
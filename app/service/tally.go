@@ -0,0 +1,369 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"time"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TallyService struct {
+}
+
+func NewTallyService() TallyService {
+	return TallyService{}
+}
+
+// TallyVote 計算投票場次所有問題的結果，儲存後回傳。
+// 投票必須已結束且超過寬限期，開票後狀態轉為已開票；重複開票會覆蓋先前的結果。
+// 開票期間鎖定投票，送出中的選票會先寫入並計入，之後的選票因投票已開票而被拒絕。
+// 開票後公布選票的 Merkle 樹根。
+func (t TallyService) TallyVote(voteId uuid.UUID, actorId *uint64) (*model.VoteResult, error) {
+	var result model.VoteResult
+	err := database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		voteOne := &model.Vote{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ?", voteId).First(voteOne).Error
+		if err != nil {
+			return err
+		}
+		if voteOne.Status != enum.Closed && voteOne.Status != enum.Tallied {
+			return &TransitionError{From: voteOne.Status, To: enum.Tallied, Reason: "vote must be closed before tallying"}
+		}
+		if !ballotsFinal(*voteOne, time.Now()) {
+			return &TransitionError{From: voteOne.Status, To: enum.Tallied, Reason: "ballots are accepted until the grace period has passed"}
+		}
+		tieBreaker := TieBreaker{Rule: voteOne.TieBreak, Seed: voteId.String()}
+
+		var questions []model.Question
+		err = tx.
+			Where("vote_id = ?", voteId).
+			Preload("Candidates", func(db *gorm.DB) *gorm.DB {
+				return db.Order("candidates.id ASC")
+			}).
+			Order("id ASC").
+			Find(&questions).Error
+		if err != nil {
+			return err
+		}
+
+		ballots, err := selectBallots(tx, voteId)
+		if err != nil {
+			return err
+		}
+
+		// 依問題分組
+		ballotsByQuestion := make(map[uint64][]model.Ballot)
+		for _, ballot := range ballots {
+			ballotsByQuestion[ballot.QuestionID] = append(ballotsByQuestion[ballot.QuestionID], ballot)
+		}
+
+		// 選票不記錄投票者，實際參與投票的人數由已投票紀錄計算
+		var voters int64
+		err = tx.Model(&model.Participation{}).Where("vote_id = ?", voteId).Count(&voters).Error
+		if err != nil {
+			return err
+		}
+
+		result = model.VoteResult{
+			VoteID: voteId,
+			Voters: voters,
+		}
+		if voteOne.Encrypted {
+			// 加密投票由受託人的部分解密還原每位候選人的得票數
+			counts, err := NewTrusteeService().DecryptTally(*voteOne, questions, ballots)
+			if err != nil {
+				return err
+			}
+			for _, question := range questions {
+				result.Questions = append(result.Questions, CountEncryptedQuestion(question, counts[question.ID], int64(len(ballotsByQuestion[question.ID])), result.Voters))
+			}
+		} else {
+			for _, question := range questions {
+				result.Questions = append(result.Questions, CountQuestion(question, ballotsByQuestion[question.ID], result.Voters, tieBreaker))
+			}
+		}
+
+		err = tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "vote_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"voters", "questions", "updated_at"}),
+		}).Create(&result).Error
+		if err != nil {
+			return err
+		}
+
+		// 將得票數寫回候選人
+		for _, questionResult := range result.Questions {
+			for _, candidateResult := range questionResult.Candidates {
+				err := tx.Model(&model.Candidate{}).
+					Where("id = ?", candidateResult.CandidateID).
+					Update("result", strconv.FormatInt(candidateResult.Votes, 10)).Error
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		if voteOne.Status == enum.Closed {
+			return transitionVote(tx, voteOne, enum.Tallied, actorId)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	// 手動開票、排程開票與受託人解密後開票都在此公布選票的 Merkle 樹根
+	publishMerkleRoot(voteId)
+
+	return &result, nil
+}
+
+// GetVoteResult 取得已儲存的開票結果
+func (t TallyService) GetVoteResult(voteId uuid.UUID) (*model.VoteResult, error) {
+	result := &model.VoteResult{}
+	err := database.SqlSession.Where("vote_id = ?", voteId).First(result).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// GetQuestionResult 從已儲存的開票結果中取得單一問題的結果
+func (t TallyService) GetQuestionResult(question model.Question) (*model.QuestionResult, error) {
+	result, err := t.GetVoteResult(question.VoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, questionResult := range result.Questions {
+		if questionResult.QuestionID == question.ID {
+			return &questionResult, nil
+		}
+	}
+
+	return nil, gorm.ErrRecordNotFound
+}
+
+// SelectBallots 取得投票場次所有計入開票的選票及其選項，重新投票後被取代的選票不包含在內
+func (t TallyService) SelectBallots(voteId uuid.UUID) ([]model.Ballot, error) {
+	return selectBallots(database.SqlSession, voteId)
+}
+
+// selectBallots 在 db 中取得投票計入開票的選票
+func selectBallots(db *gorm.DB, voteId uuid.UUID) ([]model.Ballot, error) {
+	var ballots []model.Ballot
+	err := db.
+		Joins("JOIN questions ON questions.id = ballots.question_id").
+		Where("questions.vote_id = ?", voteId).
+		Scopes(countedBallots).
+		Preload("BallotSelects").
+		Order("ballots.id ASC").
+		Find(&ballots).Error
+
+	return ballots, err
+}
+
+// CountQuestion 計算單一問題的得票數、得票率、廢票（空白票）與未投票數。
+// voters 為此投票場次實際參與投票的人數，用來計算未對此問題投票的人數。
+// 排序制的問題以第一順位計算得票數，並依投票方式逐輪計票決定當選者，平手時依 tieBreaker 處理。
+func CountQuestion(question model.Question, ballots []model.Ballot, voters int64, tieBreaker TieBreaker) model.QuestionResult {
+	seats := max(question.Seats, 1)
+
+	result := model.QuestionResult{
+		QuestionID:   question.ID,
+		Title:        question.Title,
+		Method:       question.Method,
+		Seats:        seats,
+		TotalBallots: int64(len(ballots)),
+		Candidates:   make([]model.CandidateResult, 0, len(question.Candidates)),
+		Winners:      []uint64{},
+	}
+
+	candidateIds := make([]uint64, 0, len(question.Candidates))
+	for _, candidate := range question.Candidates {
+		candidateIds = append(candidateIds, candidate.ID)
+	}
+
+	votes := make(map[uint64]int64)
+	var preferences [][]uint64
+	for _, ballot := range ballots {
+		selects := ballotSelects(question, ballot)
+		if len(selects) == 0 {
+			result.BlankBallots++
+			continue
+		}
+
+		switch {
+		case question.Method.IsRanked():
+			preference := make([]uint64, 0, len(selects))
+			for _, ballotSelect := range selects {
+				preference = append(preference, ballotSelect.CandidateID)
+			}
+			preferences = append(preferences, preference)
+			votes[preference[0]]++
+		case question.Method == enum.Score:
+			for _, ballotSelect := range selects {
+				votes[ballotSelect.CandidateID] += int64(ballotSelect.Value)
+			}
+		default:
+			for _, ballotSelect := range selects {
+				votes[ballotSelect.CandidateID]++
+			}
+		}
+	}
+
+	result.ValidBallots = result.TotalBallots - result.BlankBallots
+	result.Abstentions = max(voters-result.TotalBallots, 0)
+
+	// 波達計數與評分制以總分計算，得票率為佔所有分數的比例
+	if question.Method == enum.Borda {
+		votes = CountBorda(candidateIds, preferences)
+	}
+	total := result.ValidBallots
+	if question.Method == enum.Borda || question.Method == enum.Score {
+		total = 0
+		for _, points := range votes {
+			total += points
+		}
+	}
+
+	for _, candidate := range question.Candidates {
+		candidateResult := model.CandidateResult{
+			CandidateID: candidate.ID,
+			Name:        candidate.Name,
+			Votes:       votes[candidate.ID],
+			Percentage:  percentage(votes[candidate.ID], total),
+		}
+		if question.Method == enum.Score && result.ValidBallots > 0 {
+			candidateResult.Average = math.Round(float64(votes[candidate.ID])/float64(result.ValidBallots)*100) / 100
+		}
+		result.Candidates = append(result.Candidates, candidateResult)
+	}
+
+	switch question.Method {
+	case enum.IRV:
+		result.Rounds, result.Winners = CountInstantRunoff(candidateIds, preferences, tieBreaker)
+	case enum.STV:
+		result.Rounds, result.Winners, result.Quota = CountSingleTransferable(candidateIds, preferences, seats, tieBreaker)
+	case enum.Condorcet:
+		result.Pairwise, result.CondorcetWinner, result.Ranking, result.Winners = CountCondorcet(candidateIds, preferences, tieBreaker)
+	default:
+		result.Winners = topCandidates(result.Candidates, seats)
+	}
+
+	return result
+}
+
+// CountEncryptedQuestion 以解密後的得票數產生加密投票問題的結果。
+// 個別選票無法解密，空白票併入有效票計算，得票率以選票數為分母。
+func CountEncryptedQuestion(question model.Question, votes map[uint64]int64, ballots int64, voters int64) model.QuestionResult {
+	result := model.QuestionResult{
+		QuestionID:   question.ID,
+		Title:        question.Title,
+		Method:       question.Method,
+		Seats:        max(question.Seats, 1),
+		TotalBallots: ballots,
+		ValidBallots: ballots,
+		Abstentions:  max(voters-ballots, 0),
+		Candidates:   make([]model.CandidateResult, 0, len(question.Candidates)),
+	}
+
+	for _, candidate := range question.Candidates {
+		result.Candidates = append(result.Candidates, model.CandidateResult{
+			CandidateID: candidate.ID,
+			Name:        candidate.Name,
+			Votes:       votes[candidate.ID],
+			Percentage:  percentage(votes[candidate.ID], ballots),
+		})
+	}
+	result.Winners = topCandidates(result.Candidates, result.Seats)
+
+	return result
+}
+
+// ballotSelects 取得選票上屬於此問題的選項。
+// 排序制依順位排列，其餘依候選人ID排列。
+func ballotSelects(question model.Question, ballot model.Ballot) []model.BallotSelect {
+	selects := make([]model.BallotSelect, 0, len(ballot.BallotSelects))
+	for _, ballotSelect := range ballot.BallotSelects {
+		// 忽略不屬於此問題的候選人
+		if !hasCandidate(question, ballotSelect.CandidateID) {
+			continue
+		}
+		if question.Method.IsRanked() && ballotSelect.Rank <= 0 {
+			continue
+		}
+		selects = append(selects, ballotSelect)
+	}
+
+	sort.Slice(selects, func(i, j int) bool {
+		if selects[i].Rank != selects[j].Rank {
+			return selects[i].Rank < selects[j].Rank
+		}
+		return selects[i].CandidateID < selects[j].CandidateID
+	})
+
+	return selects
+}
+
+// CountBorda 計算波達分數，n 位候選人時第一順位得 n-1 分，依序遞減，未排序者不得分
+func CountBorda(candidateIds []uint64, preferences [][]uint64) map[uint64]int64 {
+	points := make(map[uint64]int64, len(candidateIds))
+	for _, preference := range preferences {
+		for i, cid := range preference {
+			points[cid] += int64(max(len(candidateIds)-1-i, 0))
+		}
+	}
+
+	return points
+}
+
+// hasCandidate 檢查候選人是否屬於問題
+func hasCandidate(question model.Question, candidateId uint64) bool {
+	for _, candidate := range question.Candidates {
+		if candidate.ID == candidateId {
+			return true
+		}
+	}
+
+	return false
+}
+
+// topCandidates 回傳得票數最高的 seats 位候選人，最後一席平手時全部列出
+func topCandidates(candidates []model.CandidateResult, seats int) []uint64 {
+	ranked := make([]model.CandidateResult, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.Votes > 0 {
+			ranked = append(ranked, candidate)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool { return ranked[i].Votes > ranked[j].Votes })
+
+	winners := []uint64{}
+	for i, candidate := range ranked {
+		if i >= seats && candidate.Votes < ranked[seats-1].Votes {
+			break
+		}
+		winners = append(winners, candidate.CandidateID)
+	}
+	sort.Slice(winners, func(i, j int) bool { return winners[i] < winners[j] })
+
+	return winners
+}
+
+// percentage 計算百分比，取到小數點後兩位
+func percentage(part int64, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+
+	return math.Round(float64(part)/float64(total)*10000) / 100
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"vote/app/model"
+)
+
+// PairwisePreferences 計算成對偏好矩陣，matrix[a][b] 為將 a 排在 b 之前的選票數。
+// 未排序的候選人視為排在所有已排序候選人之後，兩者皆未排序時不計。
+func PairwisePreferences(candidateIds []uint64, preferences [][]uint64) map[uint64]map[uint64]int64 {
+	matrix := make(map[uint64]map[uint64]int64, len(candidateIds))
+	for _, a := range candidateIds {
+		matrix[a] = make(map[uint64]int64, len(candidateIds))
+	}
+
+	for _, preference := range preferences {
+		ranked := make(map[uint64]bool, len(preference))
+		for i, a := range preference {
+			ranked[a] = true
+			for _, b := range preference[i+1:] {
+				matrix[a][b]++
+			}
+		}
+
+		for _, a := range preference {
+			for _, b := range candidateIds {
+				if !ranked[b] {
+					matrix[a][b]++
+				}
+			}
+		}
+	}
+
+	return matrix
+}
+
+// CountCondorcet 以成對比較計票。若有候選人在所有成對比較中皆勝出即為 Condorcet 贏家；
+// 否則依 Schulze 方法的最強路徑排序，並以排名第一者當選。
+// 回傳成對比較矩陣、Condorcet 贏家（沒有時為 nil）、Schulze 排名與當選者。
+func CountCondorcet(candidateIds []uint64, preferences [][]uint64, tieBreaker TieBreaker) ([]model.PairwiseRow, *uint64, []uint64, []uint64) {
+	matrix := PairwisePreferences(candidateIds, preferences)
+
+	pairwise := make([]model.PairwiseRow, 0, len(candidateIds))
+	for _, a := range candidateIds {
+		row := model.PairwiseRow{CandidateID: a, Against: make([]model.PairwiseCount, 0, len(candidateIds)-1)}
+		for _, b := range candidateIds {
+			if a != b {
+				row.Against = append(row.Against, model.PairwiseCount{CandidateID: b, Votes: matrix[a][b]})
+			}
+		}
+		pairwise = append(pairwise, row)
+	}
+
+	if len(preferences) == 0 {
+		return pairwise, nil, []uint64{}, []uint64{}
+	}
+
+	var condorcetWinner *uint64
+	for _, a := range candidateIds {
+		beatsAll := true
+		for _, b := range candidateIds {
+			if a != b && matrix[a][b] <= matrix[b][a] {
+				beatsAll = false
+				break
+			}
+		}
+		if beatsAll {
+			winner := a
+			condorcetWinner = &winner
+			break
+		}
+	}
+
+	ranking := SchulzeRanking(candidateIds, matrix, tieBreaker)
+	if condorcetWinner != nil {
+		return pairwise, condorcetWinner, ranking, []uint64{*condorcetWinner}
+	}
+
+	winners := []uint64{}
+	if len(ranking) > 0 {
+		winners = append(winners, ranking[0])
+	}
+
+	return pairwise, nil, ranking, winners
+}
+
+// SchulzeRanking 依 Schulze 方法計算最強路徑，並以勝過的候選人數由多到少排序。
+// 勝場數相同時依 tieBreaker 決定先後。
+func SchulzeRanking(candidateIds []uint64, matrix map[uint64]map[uint64]int64, tieBreaker TieBreaker) []uint64 {
+	strength := make(map[uint64]map[uint64]int64, len(candidateIds))
+	for _, a := range candidateIds {
+		strength[a] = make(map[uint64]int64, len(candidateIds))
+		for _, b := range candidateIds {
+			if a != b && matrix[a][b] > matrix[b][a] {
+				strength[a][b] = matrix[a][b]
+			}
+		}
+	}
+
+	for _, k := range candidateIds {
+		for _, a := range candidateIds {
+			if a == k {
+				continue
+			}
+			for _, b := range candidateIds {
+				if b == a || b == k {
+					continue
+				}
+				strength[a][b] = max(strength[a][b], min(strength[a][k], strength[k][b]))
+			}
+		}
+	}
+
+	wins := make(map[uint64]float64, len(candidateIds))
+	for _, a := range candidateIds {
+		wins[a] = 0
+		for _, b := range candidateIds {
+			if a != b && strength[a][b] > strength[b][a] {
+				wins[a]++
+			}
+		}
+	}
+
+	ranking := make([]uint64, 0, len(candidateIds))
+	for len(wins) > 0 {
+		cid := tieBreaker.Break(extremeCandidates(wins, false), nil, false)
+		delete(wins, cid)
+		ranking = append(ranking, cid)
+	}
+
+	return ranking
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"strconv"
+	"vote/app/enum"
+	"vote/app/model"
+)
+
+// CountInstantRunoff 以即時決選制（IRV）計票。
+// candidateIds 為問題的候選人，preferences 為每張有效選票依順位排列的候選人。
+// 每一輪計算各候選人的最高順位票數，若有人過半即當選，
+// 否則淘汰票數最少的候選人，並將其選票轉移給下一個尚未淘汰的順位。
+func CountInstantRunoff(candidateIds []uint64, preferences [][]uint64, tieBreaker TieBreaker) ([]model.TallyRound, []uint64) {
+	hopeful := make(map[uint64]bool, len(candidateIds))
+	for _, cid := range candidateIds {
+		hopeful[cid] = true
+	}
+
+	var rounds []model.TallyRound
+	var history []map[uint64]float64
+	for round := 1; len(hopeful) > 0; round++ {
+		counts := make(map[uint64]float64, len(hopeful))
+		for cid := range hopeful {
+			counts[cid] = 0
+		}
+
+		var exhausted float64
+		for _, preference := range preferences {
+			if top := firstHopeful(preference, hopeful); top != 0 {
+				counts[top]++
+			} else {
+				exhausted++
+			}
+		}
+
+		tallyRound := model.TallyRound{
+			Round:      round,
+			Counts:     roundCounts(candidateIds, counts),
+			Exhausted:  exhausted,
+			Elected:    []uint64{},
+			Eliminated: []uint64{},
+			Transfers:  []model.RoundTransfer{},
+		}
+
+		active := float64(len(preferences)) - exhausted
+		if active == 0 {
+			rounds = append(rounds, tallyRound)
+			return rounds, []uint64{}
+		}
+
+		leader := tieBreaker.Break(extremeCandidates(counts, false), history, false)
+		if counts[leader]*2 > active || len(hopeful) == 1 {
+			tallyRound.Elected = []uint64{leader}
+			rounds = append(rounds, tallyRound)
+			return rounds, []uint64{leader}
+		}
+
+		loser := tieBreaker.Break(extremeCandidates(counts, true), history, true)
+		delete(hopeful, loser)
+		tallyRound.Eliminated = []uint64{loser}
+		tallyRound.Transfers = transferVotes(loser, candidateIds, preferences, hopeful)
+
+		rounds = append(rounds, tallyRound)
+		history = append(history, counts)
+	}
+
+	return rounds, []uint64{}
+}
+
+// firstHopeful 回傳選票上第一個尚未淘汰的候選人，沒有則回傳 0
+func firstHopeful(preference []uint64, hopeful map[uint64]bool) uint64 {
+	for _, cid := range preference {
+		if hopeful[cid] {
+			return cid
+		}
+	}
+
+	return 0
+}
+
+// transferVotes 計算被淘汰候選人的選票轉移到哪些候選人，hopeful 不含被淘汰者
+func transferVotes(from uint64, candidateIds []uint64, preferences [][]uint64, hopeful map[uint64]bool) []model.RoundTransfer {
+	withFrom := make(map[uint64]bool, len(hopeful)+1)
+	for cid := range hopeful {
+		withFrom[cid] = true
+	}
+	withFrom[from] = true
+
+	transfers := make(map[uint64]float64)
+	for _, preference := range preferences {
+		if firstHopeful(preference, withFrom) != from {
+			continue
+		}
+		transfers[firstHopeful(preference, hopeful)]++
+	}
+
+	return roundTransfers(from, candidateIds, transfers)
+}
+
+// roundCounts 依候選人順序輸出票數
+func roundCounts(candidateIds []uint64, counts map[uint64]float64) []model.RoundCount {
+	result := []model.RoundCount{}
+	for _, cid := range candidateIds {
+		if votes, ok := counts[cid]; ok {
+			result = append(result, model.RoundCount{CandidateID: cid, Votes: roundVotes(votes)})
+		}
+	}
+
+	return result
+}
+
+// roundTransfers 依候選人順序輸出轉移票數，已用盡的選票排在最後
+func roundTransfers(from uint64, candidateIds []uint64, transfers map[uint64]float64) []model.RoundTransfer {
+	result := []model.RoundTransfer{}
+	for _, cid := range append(append([]uint64{}, candidateIds...), 0) {
+		if votes := transfers[cid]; votes > 0 {
+			result = append(result, model.RoundTransfer{From: from, To: cid, Votes: roundVotes(votes)})
+		}
+	}
+
+	return result
+}
+
+// extremeCandidates 回傳票數最少（lowest 為 true）或最多的候選人
+func extremeCandidates(counts map[uint64]float64, lowest bool) []uint64 {
+	var result []uint64
+	var extreme float64
+	for cid, votes := range counts {
+		switch {
+		case len(result) == 0 || (lowest && votes < extreme) || (!lowest && votes > extreme):
+			extreme = votes
+			result = []uint64{cid}
+		case votes == extreme:
+			result = append(result, cid)
+		}
+	}
+
+	return result
+}
+
+// TieBreaker 依投票場次設定的規則處理計票平手
+type TieBreaker struct {
+	Rule enum.TieBreak
+	// Seed 為 Seeded 規則使用的種子，通常是投票場次的 UUID
+	Seed string
+}
+
+// Break 從平手的候選人中選出一位，lowest 為 true 時選出要淘汰者，否則選出要當選者。
+// 比較歷史票數後仍平手，Seeded 規則以種子與候選人ID的雜湊值最小者為準，
+// 其餘規則依候選人ID決定：淘汰ID較大者（較晚建立），當選ID較小者。
+func (t TieBreaker) Break(tied []uint64, history []map[uint64]float64, lowest bool) uint64 {
+	compare := func(i int) {
+		counts := make(map[uint64]float64, len(tied))
+		for _, cid := range tied {
+			counts[cid] = history[i][cid]
+		}
+		tied = extremeCandidates(counts, lowest)
+	}
+
+	switch t.Rule {
+	case enum.Forward:
+		for i := 0; i < len(history) && len(tied) > 1; i++ {
+			compare(i)
+		}
+	case enum.CandidateOrder, enum.Seeded:
+	default:
+		for i := len(history) - 1; i >= 0 && len(tied) > 1; i-- {
+			compare(i)
+		}
+	}
+
+	chosen := tied[0]
+	for _, cid := range tied[1:] {
+		if t.Rule == enum.Seeded {
+			if bytes.Compare(t.hash(cid), t.hash(chosen)) < 0 {
+				chosen = cid
+			}
+			continue
+		}
+		if (lowest && cid > chosen) || (!lowest && cid < chosen) {
+			chosen = cid
+		}
+	}
+
+	return chosen
+}
+
+// hash 計算種子與候選人ID的雜湊值
+func (t TieBreaker) hash(candidateId uint64) []byte {
+	sum := sha256.Sum256([]byte(t.Seed + ":" + strconv.FormatUint(candidateId, 10)))
+	return sum[:]
+}
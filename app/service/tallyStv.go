@@ -0,0 +1,175 @@
+package service
+
+import (
+	"math"
+	"vote/app/model"
+)
+
+// stvEpsilon 浮點數比較的容許誤差
+const stvEpsilon = 1e-9
+
+// stvBallot 計票過程中的排序選票，holder 為目前持有此選票的候選人，0 表示已用盡
+type stvBallot struct {
+	preference []uint64
+	weight     float64
+	holder     uint64
+}
+
+// DroopQuota 計算 Droop 當選基數：有效票數 / (席次 + 1) 取整數後加一
+func DroopQuota(validBallots int64, seats int) float64 {
+	return math.Floor(float64(validBallots)/float64(seats+1)) + 1
+}
+
+// CountSingleTransferable 以單記可讓渡投票制（STV）計票，採 Droop 當選基數與 Gregory 分數轉移。
+// 每一輪先讓所有達到當選基數的候選人當選，並將餘額依比例轉移給選票上的下一個順位；
+// 若無人達到當選基數，則淘汰票數最少的候選人並轉移其選票。
+// 剩餘候選人數不超過剩餘席次時全部當選。回傳每輪紀錄、當選者（依當選順序）與當選基數。
+func CountSingleTransferable(candidateIds []uint64, preferences [][]uint64, seats int, tieBreaker TieBreaker) ([]model.TallyRound, []uint64, float64) {
+	if seats < 1 {
+		seats = 1
+	}
+	quota := DroopQuota(int64(len(preferences)), seats)
+
+	hopeful := make(map[uint64]bool, len(candidateIds))
+	for _, cid := range candidateIds {
+		hopeful[cid] = true
+	}
+
+	ballots := make([]*stvBallot, 0, len(preferences))
+	for _, preference := range preferences {
+		ballots = append(ballots, &stvBallot{
+			preference: preference,
+			weight:     1,
+			holder:     firstHopeful(preference, hopeful),
+		})
+	}
+
+	elected := []uint64{}
+	var rounds []model.TallyRound
+	var history []map[uint64]float64
+	for round := 1; len(elected) < seats && len(hopeful) > 0; round++ {
+		counts := make(map[uint64]float64, len(candidateIds))
+		for cid := range hopeful {
+			counts[cid] = 0
+		}
+		for _, cid := range elected {
+			counts[cid] = 0
+		}
+
+		var exhausted float64
+		for _, ballot := range ballots {
+			if ballot.holder == 0 {
+				exhausted += ballot.weight
+				continue
+			}
+			counts[ballot.holder] += ballot.weight
+		}
+
+		tallyRound := model.TallyRound{
+			Round:      round,
+			Counts:     roundCounts(candidateIds, counts),
+			Exhausted:  roundVotes(exhausted),
+			Elected:    []uint64{},
+			Eliminated: []uint64{},
+			Transfers:  []model.RoundTransfer{},
+		}
+
+		hopefulCounts := make(map[uint64]float64, len(hopeful))
+		for cid := range hopeful {
+			hopefulCounts[cid] = counts[cid]
+		}
+
+		if len(preferences) == 0 {
+			rounds = append(rounds, tallyRound)
+			break
+		}
+
+		// 剩餘候選人數不超過剩餘席次，依票數高低全部當選
+		if len(hopeful) <= seats-len(elected) {
+			for len(hopefulCounts) > 0 {
+				cid := tieBreaker.Break(extremeCandidates(hopefulCounts, false), history, false)
+				delete(hopefulCounts, cid)
+				delete(hopeful, cid)
+				elected = append(elected, cid)
+				tallyRound.Elected = append(tallyRound.Elected, cid)
+			}
+			rounds = append(rounds, tallyRound)
+			break
+		}
+
+		// 依票數高低排列達到當選基數的候選人
+		reached := make(map[uint64]float64)
+		for cid, votes := range hopefulCounts {
+			if votes >= quota-stvEpsilon {
+				reached[cid] = votes
+			}
+		}
+
+		if len(reached) > 0 {
+			for len(reached) > 0 && len(elected) < seats {
+				cid := tieBreaker.Break(extremeCandidates(reached, false), history, false)
+				delete(reached, cid)
+				delete(hopeful, cid)
+				elected = append(elected, cid)
+				tallyRound.Elected = append(tallyRound.Elected, cid)
+			}
+
+			// 轉移當選者的餘額
+			for _, cid := range tallyRound.Elected {
+				surplus := counts[cid] - quota
+				if surplus <= stvEpsilon {
+					continue
+				}
+				ballots = transferSurplus(cid, surplus/counts[cid], ballots, hopeful, &tallyRound, candidateIds)
+			}
+		} else {
+			loser := tieBreaker.Break(extremeCandidates(hopefulCounts, true), history, true)
+			delete(hopeful, loser)
+			tallyRound.Eliminated = []uint64{loser}
+
+			transfers := make(map[uint64]float64)
+			for _, ballot := range ballots {
+				if ballot.holder != loser {
+					continue
+				}
+				ballot.holder = firstHopeful(ballot.preference, hopeful)
+				transfers[ballot.holder] += ballot.weight
+			}
+			tallyRound.Transfers = append(tallyRound.Transfers, roundTransfers(loser, candidateIds, transfers)...)
+		}
+
+		rounds = append(rounds, tallyRound)
+		history = append(history, hopefulCounts)
+	}
+
+	return rounds, elected, quota
+}
+
+// transferSurplus 將當選者持有的每張選票，依轉移比例分出一部分給下一個尚未當選或淘汰的順位
+func transferSurplus(from uint64, ratio float64, ballots []*stvBallot, hopeful map[uint64]bool, tallyRound *model.TallyRound, candidateIds []uint64) []*stvBallot {
+	transfers := make(map[uint64]float64)
+	for _, ballot := range ballots {
+		if ballot.holder != from {
+			continue
+		}
+
+		moved := ballot.weight * ratio
+		ballot.weight -= moved
+
+		next := firstHopeful(ballot.preference, hopeful)
+		transfers[next] += moved
+		ballots = append(ballots, &stvBallot{
+			preference: ballot.preference,
+			weight:     moved,
+			holder:     next,
+		})
+	}
+	tallyRound.Transfers = append(tallyRound.Transfers, roundTransfers(from, candidateIds, transfers)...)
+
+	return ballots
+}
+
+// roundVotes 將票數取到小數點後四位
+func roundVotes(votes float64) float64 {
+	return math.Round(votes*10000) / 10000
+}
@@ -0,0 +1,607 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sort"
+	"time"
+	"vote/app/database"
+	"vote/app/elgamal"
+	"vote/app/enum"
+	"vote/app/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrDecryptionPending 加密投票尚未有足夠的受託人送出部分解密
+var ErrDecryptionPending = errors.New("waiting for trustees to decrypt the tally")
+
+// CeremonyError 金鑰產生或解密的步驟不符合目前的進度
+type CeremonyError struct {
+	Reason string
+}
+
+func (e *CeremonyError) Error() string {
+	return "trustee ceremony: " + e.Reason
+}
+
+// ShareContext 金鑰分片加密綁定的內容
+func ShareContext(voteId uuid.UUID, from int, to int) []byte {
+	return []byte(fmt.Sprintf("vote=%s;share=%d->%d", voteId, from, to))
+}
+
+// DecryptionContext 部分解密證明綁定的內容
+func DecryptionContext(voteId uuid.UUID, number int, questionId uint64, candidateId uint64) []byte {
+	return []byte(fmt.Sprintf("vote=%s;trustee=%d;question=%d;candidate=%d", voteId, number, questionId, candidateId))
+}
+
+// DealShares 受託人產生秘密多項式，回傳公開的承諾與發給每位受託人（含自己）的加密分片。
+// 多項式只存在於記憶體中，送出後即丟棄。
+func DealShares(voteId uuid.UUID, number int, threshold int, trustees []model.Trustee, random io.Reader) (*model.TrusteeDeal, error) {
+	polynomial, err := elgamal.NewPolynomial(threshold, random)
+	if err != nil {
+		return nil, err
+	}
+
+	deal := &model.TrusteeDeal{
+		Commitments: polynomial.Commitments(),
+		Shares:      make(map[int]*elgamal.SealedShare, len(trustees)),
+	}
+	for _, trustee := range trustees {
+		if trustee.TransportKey == nil {
+			return nil, fmt.Errorf("trustee %d has not uploaded a transport key", trustee.Number)
+		}
+		sealed, err := elgamal.SealShare(trustee.TransportKey.Big(), polynomial.Evaluate(int64(trustee.Number)), ShareContext(voteId, number, trustee.Number), random)
+		if err != nil {
+			return nil, err
+		}
+		deal.Shares[trustee.Number] = sealed
+	}
+
+	return deal, nil
+}
+
+// OpenTrusteeShare 解開並依承諾驗證其他受託人發來的分片，回傳合併後的金鑰分片。
+// 任一分片不符時回傳錯誤並指出發送者。
+func OpenTrusteeShare(voteId uuid.UUID, number int, transportSecret *big.Int, trustees []model.Trustee, shares []model.TrusteeShare) (*big.Int, error) {
+	received := make(map[int]model.TrusteeShare, len(shares))
+	for _, share := range shares {
+		if share.ToNumber == number {
+			received[share.FromNumber] = share
+		}
+	}
+
+	opened := make([]*big.Int, 0, len(trustees))
+	for _, dealer := range trustees {
+		share, ok := received[dealer.Number]
+		if !ok {
+			return nil, fmt.Errorf("missing share from trustee %d", dealer.Number)
+		}
+		value, err := elgamal.OpenShare(transportSecret, share.Sealed, ShareContext(voteId, dealer.Number, number))
+		if err != nil {
+			return nil, fmt.Errorf("share from trustee %d: %w", dealer.Number, err)
+		}
+		if !elgamal.VerifyShare(dealer.Commitments, int64(number), value) {
+			return nil, fmt.Errorf("share from trustee %d: %w", dealer.Number, elgamal.ErrShareInvalid)
+		}
+		opened = append(opened, value)
+	}
+
+	return elgamal.CombineShares(opened), nil
+}
+
+// PartialDecryptions 受託人以金鑰分片對所有合計密文部分解密並附上證明
+func PartialDecryptions(voteId uuid.UUID, number int, share *big.Int, tally []model.EncryptedTally, random io.Reader) ([]model.PartialDecryptionUpload, error) {
+	verificationKey := new(big.Int).Exp(elgamal.G, share, elgamal.P)
+
+	partials := make([]model.PartialDecryptionUpload, 0, len(tally))
+	for _, entry := range tally {
+		a := entry.Ciphertext.A.Big()
+		d := elgamal.PartialDecrypt(share, entry.Ciphertext)
+		proof, err := elgamal.ProveEquality(share, verificationKey, a, d, DecryptionContext(voteId, number, entry.QuestionID, entry.CandidateID), random)
+		if err != nil {
+			return nil, err
+		}
+		partials = append(partials, model.PartialDecryptionUpload{
+			QuestionID:  entry.QuestionID,
+			CandidateID: entry.CandidateID,
+			Share:       elgamal.NewInt(d),
+			Proof:       proof,
+		})
+	}
+
+	return partials, nil
+}
+
+// AggregateTally 將每位候選人在所有選票上的密文相乘，只包含支援加密的問題
+func AggregateTally(questions []model.Question, ballots []model.Ballot) []model.EncryptedTally {
+	ballotsByQuestion := make(map[uint64][]model.Ballot)
+	for _, ballot := range ballots {
+		ballotsByQuestion[ballot.QuestionID] = append(ballotsByQuestion[ballot.QuestionID], ballot)
+	}
+
+	var tally []model.EncryptedTally
+	for _, question := range questions {
+		if !question.Method.SupportsEncryption() {
+			continue
+		}
+
+		candidates := append([]model.Candidate(nil), question.Candidates...)
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].ID < candidates[j].ID })
+		for _, candidate := range candidates {
+			sum := elgamal.Identity()
+			for _, ballot := range ballotsByQuestion[question.ID] {
+				for _, ballotSelect := range ballot.BallotSelects {
+					if ballotSelect.CandidateID == candidate.ID && ballotSelect.Ciphertext != nil {
+						sum = sum.Mul(ballotSelect.Ciphertext)
+					}
+				}
+			}
+			tally = append(tally, model.EncryptedTally{
+				QuestionID:  question.ID,
+				CandidateID: candidate.ID,
+				Ciphertext:  sum,
+				Ballots:     int64(len(ballotsByQuestion[question.ID])),
+			})
+		}
+	}
+
+	return tally
+}
+
+// VerifyPartialDecryption 以受託人的公開驗證值檢查部分解密的證明
+func VerifyPartialDecryption(voteId uuid.UUID, trustees []model.Trustee, number int, entry model.EncryptedTally, partial model.PartialDecryptionUpload) bool {
+	if partial.Share == nil {
+		return false
+	}
+	verificationKey := elgamal.VerificationKey(dealerCommitments(trustees), int64(number))
+
+	return elgamal.VerifyEquality(verificationKey, entry.Ciphertext.A.Big(), partial.Share.Big(),
+		DecryptionContext(voteId, number, entry.QuestionID, entry.CandidateID), partial.Proof)
+}
+
+// CombineTally 以編號最小的 threshold 位已完成部分解密的受託人還原得票數，回傳 問題ID -> 候選人ID -> 票數
+func CombineTally(threshold int, tally []model.EncryptedTally, partials []model.PartialDecryption) (map[uint64]map[uint64]int64, error) {
+	type entryKey struct{ questionId, candidateId uint64 }
+	byTrustee := make(map[int]map[entryKey]*big.Int)
+	for _, partial := range partials {
+		if byTrustee[partial.TrusteeNumber] == nil {
+			byTrustee[partial.TrusteeNumber] = make(map[entryKey]*big.Int)
+		}
+		byTrustee[partial.TrusteeNumber][entryKey{partial.QuestionID, partial.CandidateID}] = partial.Share.Big()
+	}
+
+	var complete []int
+	for number, shares := range byTrustee {
+		covered := true
+		for _, entry := range tally {
+			if shares[entryKey{entry.QuestionID, entry.CandidateID}] == nil {
+				covered = false
+				break
+			}
+		}
+		if covered {
+			complete = append(complete, number)
+		}
+	}
+	if len(complete) < threshold {
+		return nil, ErrDecryptionPending
+	}
+	sort.Ints(complete)
+	complete = complete[:threshold]
+
+	counts := make(map[uint64]map[uint64]int64)
+	for _, entry := range tally {
+		key := entryKey{entry.QuestionID, entry.CandidateID}
+		selected := make(map[int64]*big.Int, threshold)
+		for _, number := range complete {
+			selected[int64(number)] = byTrustee[number][key]
+		}
+
+		votes, err := elgamal.DiscreteLog(elgamal.Combine(entry.Ciphertext, selected), entry.Ballots)
+		if err != nil {
+			return nil, fmt.Errorf("question %d candidate %d: %w", entry.QuestionID, entry.CandidateID, err)
+		}
+		if counts[entry.QuestionID] == nil {
+			counts[entry.QuestionID] = make(map[uint64]int64)
+		}
+		counts[entry.QuestionID][entry.CandidateID] = votes
+	}
+
+	return counts, nil
+}
+
+// dealerCommitments 依受託人編號排列的承諾
+func dealerCommitments(trustees []model.Trustee) [][]*elgamal.Int {
+	sorted := append([]model.Trustee(nil), trustees...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Number < sorted[j].Number })
+
+	dealers := make([][]*elgamal.Int, 0, len(sorted))
+	for _, trustee := range sorted {
+		dealers = append(dealers, trustee.Commitments)
+	}
+
+	return dealers
+}
+
+// ballotsFinal 投票已結束且寬限期已過，選票不會再增加
+func ballotsFinal(vote model.Vote, now time.Time) bool {
+	grace := time.Duration(vote.GracePeriod) * time.Second
+
+	return vote.Status >= enum.Closed && !now.Before(voteClosedAt(vote).Add(grace))
+}
+
+// trusteeTokenDigest 受託人權杖的摘要，資料庫不保存權杖本身
+func trusteeTokenDigest(token string) string {
+	digest := sha256.Sum256([]byte(token))
+
+	return hex.EncodeToString(digest[:])
+}
+
+type TrusteeService struct {
+}
+
+func NewTrusteeService() TrusteeService {
+	return TrusteeService{}
+}
+
+// SetupTrustees 將草稿投票設為加密模式並建立受託人，重新設定會清除先前的金鑰產生進度。
+// 回傳的權杖只會出現這一次，需分別交給每位受託人。
+func (t TrusteeService) SetupTrustees(voteId uuid.UUID, setup model.TrusteeSetup) ([]model.TrusteeCreated, error) {
+	if setup.Threshold > len(setup.Names) {
+		return nil, &CeremonyError{Reason: "threshold cannot exceed the number of trustees"}
+	}
+
+	var created []model.TrusteeCreated
+	err := database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		vote := model.Vote{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ?", voteId).First(&vote).Error
+		if err != nil {
+			return err
+		}
+		if vote.Status != enum.Draft {
+			return &CeremonyError{Reason: "trustees can only be set up while the vote is a draft"}
+		}
+		if err := checkEncryptableQuestions(tx, voteId); err != nil {
+			return err
+		}
+
+		for _, table := range []any{&model.PartialDecryption{}, &model.TrusteeShare{}, &model.Trustee{}} {
+			if err := tx.Where("vote_id = ?", voteId).Delete(table).Error; err != nil {
+				return err
+			}
+		}
+
+		for i, name := range setup.Names {
+			token := make([]byte, 32)
+			if _, err := rand.Read(token); err != nil {
+				return err
+			}
+			trustee := model.Trustee{
+				VoteID:      voteId,
+				Number:      i + 1,
+				Name:        name,
+				TokenDigest: trusteeTokenDigest(hex.EncodeToString(token)),
+			}
+			if err := tx.Create(&trustee).Error; err != nil {
+				return err
+			}
+			created = append(created, model.TrusteeCreated{Number: trustee.Number, Name: name, Token: hex.EncodeToString(token)})
+		}
+
+		return tx.Model(&vote).Updates(map[string]any{
+			"encrypted":  true,
+			"threshold":  setup.Threshold,
+			"public_key": nil,
+		}).Error
+	})
+
+	return created, err
+}
+
+// GetTrustees 取得投票的受託人，依編號排序
+func (t TrusteeService) GetTrustees(voteId uuid.UUID) ([]model.Trustee, error) {
+	return t.trustees(database.SqlSession, voteId)
+}
+
+// TrusteeByToken 以權杖取得受託人
+func (t TrusteeService) TrusteeByToken(token string) (*model.Trustee, error) {
+	trustee := &model.Trustee{}
+	err := database.SqlSession.Where("token_digest = ?", trusteeTokenDigest(token)).First(trustee).Error
+
+	return trustee, err
+}
+
+// GetCeremony 取得受託人目前可見的進度：所有受託人的公開資料、發給自己的分片，
+// 以及選票確定後的合計密文
+func (t TrusteeService) GetCeremony(trustee model.Trustee) (*model.TrusteeCeremony, error) {
+	vote, err := NewVoteService().GetVote(trustee.VoteID)
+	if err != nil {
+		return nil, err
+	}
+	trustees, err := t.GetTrustees(trustee.VoteID)
+	if err != nil {
+		return nil, err
+	}
+
+	ceremony := &model.TrusteeCeremony{
+		VoteID:    vote.Uuid,
+		Status:    ceremonyStatus(*vote, trustees),
+		Number:    trustee.Number,
+		Threshold: vote.Threshold,
+		PublicKey: vote.PublicKey,
+		Trustees:  trustees,
+		Shares:    []model.TrusteeShare{},
+		Tally:     []model.EncryptedTally{},
+	}
+	err = database.SqlSession.
+		Where("vote_id = ? AND to_number = ?", trustee.VoteID, trustee.Number).
+		Order("from_number ASC").
+		Find(&ceremony.Shares).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if ballotsFinal(*vote, time.Now()) {
+		if ceremony.Tally, err = t.EncryptedTally(vote.Uuid); err != nil {
+			return nil, err
+		}
+	}
+
+	return ceremony, nil
+}
+
+// SetTransportKey 受託人上傳接收分片用的傳輸公鑰，任何人開始發送分片後不可再更換
+func (t TrusteeService) SetTransportKey(trustee model.Trustee, key *elgamal.Int) error {
+	if key == nil || !elgamal.IsElement(key.Big()) || key.Big().Cmp(big.NewInt(1)) == 0 {
+		return &CeremonyError{Reason: "transport key is not a valid group element"}
+	}
+
+	return database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		vote, err := t.lockDraftVote(tx, trustee.VoteID)
+		if err != nil {
+			return err
+		}
+
+		var dealt int64
+		if err := tx.Model(&model.TrusteeShare{}).Where("vote_id = ?", vote.Uuid).Count(&dealt).Error; err != nil {
+			return err
+		}
+		if dealt > 0 {
+			return &CeremonyError{Reason: "shares have already been dealt"}
+		}
+
+		// 以結構更新，欄位才會經過 JSON 序列化
+		return tx.Model(&model.Trustee{ID: trustee.ID}).Select("transport_key").Updates(&model.Trustee{TransportKey: key}).Error
+	})
+}
+
+// Deal 保存受託人的承諾與加密分片，最後一位受託人送出後計算選舉公鑰
+func (t TrusteeService) Deal(trustee model.Trustee, deal model.TrusteeDeal) error {
+	return database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		vote, err := t.lockDraftVote(tx, trustee.VoteID)
+		if err != nil {
+			return err
+		}
+		trustees, err := t.trustees(tx, vote.Uuid)
+		if err != nil {
+			return err
+		}
+
+		for _, other := range trustees {
+			if other.TransportKey == nil {
+				return &CeremonyError{Reason: fmt.Sprintf("trustee %d has not uploaded a transport key", other.Number)}
+			}
+			if other.Number == trustee.Number && other.Commitments != nil {
+				return &CeremonyError{Reason: "shares have already been dealt"}
+			}
+		}
+		if !elgamal.ValidCommitments(deal.Commitments, vote.Threshold) {
+			return &CeremonyError{Reason: fmt.Sprintf("expected %d valid commitments", vote.Threshold)}
+		}
+		if len(deal.Shares) != len(trustees) {
+			return &CeremonyError{Reason: "a share is required for every trustee"}
+		}
+
+		for _, receiver := range trustees {
+			sealed := deal.Shares[receiver.Number]
+			if sealed == nil || sealed.R == nil || !elgamal.IsElement(sealed.R.Big()) {
+				return &CeremonyError{Reason: fmt.Sprintf("invalid share for trustee %d", receiver.Number)}
+			}
+			err := tx.Create(&model.TrusteeShare{
+				VoteID:     vote.Uuid,
+				FromNumber: trustee.Number,
+				ToNumber:   receiver.Number,
+				Sealed:     sealed,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		err = tx.Model(&model.Trustee{ID: trustee.ID}).Select("commitments").Updates(&model.Trustee{Commitments: deal.Commitments}).Error
+		if err != nil {
+			return err
+		}
+
+		// 所有受託人都送出後，選舉公鑰為各承諾常數項的乘積
+		for i := range trustees {
+			if trustees[i].Number == trustee.Number {
+				trustees[i].Commitments = deal.Commitments
+			}
+			if trustees[i].Commitments == nil {
+				return nil
+			}
+		}
+
+		return tx.Model(vote).Select("public_key").Updates(&model.Vote{PublicKey: elgamal.NewInt(elgamal.JointPublicKey(dealerCommitments(trustees)))}).Error
+	})
+}
+
+// SubmitPartials 驗證並保存受託人的部分解密，達到門檻後開票並回傳結果，未達門檻時結果為 nil
+func (t TrusteeService) SubmitPartials(trustee model.Trustee, upload model.TrusteeDecrypt) (*model.VoteResult, error) {
+	vote, err := NewVoteService().GetVote(trustee.VoteID)
+	if err != nil {
+		return nil, err
+	}
+	if vote.Status != enum.Closed || !ballotsFinal(*vote, time.Now()) {
+		return nil, &CeremonyError{Reason: "the tally can only be decrypted after the vote is closed and the grace period has passed"}
+	}
+	trustees, err := t.GetTrustees(vote.Uuid)
+	if err != nil {
+		return nil, err
+	}
+	tally, err := t.EncryptedTally(vote.Uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	type entryKey struct{ questionId, candidateId uint64 }
+	uploaded := make(map[entryKey]model.PartialDecryptionUpload, len(upload.Partials))
+	for _, partial := range upload.Partials {
+		uploaded[entryKey{partial.QuestionID, partial.CandidateID}] = partial
+	}
+	if len(uploaded) != len(tally) {
+		return nil, &CeremonyError{Reason: "a partial decryption is required for every candidate"}
+	}
+
+	partials := make([]model.PartialDecryption, 0, len(tally))
+	for _, entry := range tally {
+		partial, ok := uploaded[entryKey{entry.QuestionID, entry.CandidateID}]
+		if !ok || !VerifyPartialDecryption(vote.Uuid, trustees, trustee.Number, entry, partial) {
+			return nil, &CeremonyError{Reason: fmt.Sprintf("invalid partial decryption for question %d candidate %d", entry.QuestionID, entry.CandidateID)}
+		}
+		partials = append(partials, model.PartialDecryption{
+			VoteID:        vote.Uuid,
+			TrusteeNumber: trustee.Number,
+			QuestionID:    entry.QuestionID,
+			CandidateID:   entry.CandidateID,
+			Share:         partial.Share,
+			Proof:         partial.Proof,
+		})
+	}
+
+	err = database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("vote_id = ? AND trustee_number = ?", vote.Uuid, trustee.Number).Delete(&model.PartialDecryption{}).Error
+		if err != nil {
+			return err
+		}
+		if len(partials) > 0 {
+			if err := tx.Create(&partials).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&model.Trustee{}).Where("id = ?", trustee.ID).Update("decrypted", true).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := NewTallyService().TallyVote(vote.Uuid, nil)
+	if errors.Is(err, ErrDecryptionPending) {
+		return nil, nil
+	}
+
+	return result, err
+}
+
+// EncryptedTally 取得投票每位候選人的合計密文
+func (t TrusteeService) EncryptedTally(voteId uuid.UUID) ([]model.EncryptedTally, error) {
+	questions, err := encryptedQuestions(voteId)
+	if err != nil {
+		return nil, err
+	}
+	ballots, err := NewTallyService().SelectBallots(voteId)
+	if err != nil {
+		return nil, err
+	}
+
+	return AggregateTally(questions, ballots), nil
+}
+
+// DecryptTally 以已保存的部分解密還原加密投票的得票數
+func (t TrusteeService) DecryptTally(vote model.Vote, questions []model.Question, ballots []model.Ballot) (map[uint64]map[uint64]int64, error) {
+	var partials []model.PartialDecryption
+	if err := database.SqlSession.Where("vote_id = ?", vote.Uuid).Find(&partials).Error; err != nil {
+		return nil, err
+	}
+
+	return CombineTally(vote.Threshold, AggregateTally(questions, ballots), partials)
+}
+
+// trustees 依編號取得投票的受託人
+func (t TrusteeService) trustees(tx *gorm.DB, voteId uuid.UUID) ([]model.Trustee, error) {
+	var trustees []model.Trustee
+	err := tx.Where("vote_id = ?", voteId).Order("number ASC").Find(&trustees).Error
+
+	return trustees, err
+}
+
+// lockDraftVote 鎖定投票，金鑰產生只能在草稿階段進行
+func (t TrusteeService) lockDraftVote(tx *gorm.DB, voteId uuid.UUID) (*model.Vote, error) {
+	vote := &model.Vote{}
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("uuid = ?", voteId).First(vote).Error
+	if err != nil {
+		return nil, err
+	}
+	if vote.Status != enum.Draft {
+		return nil, &CeremonyError{Reason: "the key ceremony must finish while the vote is a draft"}
+	}
+
+	return vote, nil
+}
+
+// ceremonyStatus 金鑰產生與解密的進度
+func ceremonyStatus(vote model.Vote, trustees []model.Trustee) string {
+	switch {
+	case vote.Status >= enum.Tallied:
+		return "tallied"
+	case ballotsFinal(vote, time.Now()):
+		return "decrypting"
+	case vote.PublicKey != nil:
+		return "ready"
+	}
+	for _, trustee := range trustees {
+		if trustee.TransportKey == nil {
+			return "keys"
+		}
+	}
+
+	return "dealing"
+}
+
+// encryptedQuestions 取得投票的問題與候選人，依ID排序
+func encryptedQuestions(voteId uuid.UUID) ([]model.Question, error) {
+	var questions []model.Question
+	err := database.SqlSession.
+		Where("vote_id = ?", voteId).
+		Preload("Candidates", func(db *gorm.DB) *gorm.DB {
+			return db.Order("candidates.id ASC")
+		}).
+		Order("id ASC").
+		Find(&questions).Error
+
+	return questions, err
+}
+
+// checkEncryptableQuestions 加密投票的問題只能使用勾選制或認可制
+func checkEncryptableQuestions(tx *gorm.DB, voteId uuid.UUID) error {
+	var questions []model.Question
+	if err := tx.Select("id", "method").Where("vote_id = ?", voteId).Find(&questions).Error; err != nil {
+		return err
+	}
+	for _, question := range questions {
+		if !question.Method.SupportsEncryption() {
+			return &CeremonyError{Reason: fmt.Sprintf("question %d uses %s, encrypted ballots support plurality and approval only", question.ID, question.Method)}
+		}
+	}
+
+	return nil
+}
@@ -1,7 +1,10 @@
 package service
 
 import (
+	"errors"
 	"strconv"
+	"time"
+	"vote/app/enum"
 	"vote/app/model"
 	"vote/app/repository"
 	"vote/app/utils"
@@ -27,6 +30,55 @@ func (v VoteService) GetVote(uuid uuid.UUID) (*model.Vote, error) {
 	}
 }
 
+var (
+	ErrVoteNotOpen = errors.New("vote has not started yet")
+	ErrVoteClosed  = errors.New("vote has ended")
+	// ErrAllowRevoteLocked 已送出的選票沒有可取代的摘要，開始投票後不能變更是否開放重新投票
+	ErrAllowRevoteLocked = errors.New("allow_revote can only be changed while the vote is a draft")
+)
+
+// CheckVoteOpen 檢查投票在 now 是否開放登入，投票狀態必須為開放且在投票時間內
+func CheckVoteOpen(vote model.Vote, now time.Time) error {
+	if vote.Status < enum.Open || now.Before(vote.StartTime) {
+		return ErrVoteNotOpen
+	}
+	if vote.Status > enum.Open || !now.Before(vote.EndTime) {
+		return ErrVoteClosed
+	}
+
+	return nil
+}
+
+// CheckBallotWindow 檢查在 now 是否可以送出選票。
+// 投票結束後，於結束前登入（loginAt）的投票者仍可在寬限期內送出選票；提前結束時寬限期由實際結束的時間起算。
+func CheckBallotWindow(vote model.Vote, loginAt time.Time, now time.Time) error {
+	err := CheckVoteOpen(vote, now)
+	if !errors.Is(err, ErrVoteClosed) {
+		return err
+	}
+	// 開票後不再接受選票
+	if vote.Status > enum.Closed {
+		return ErrVoteClosed
+	}
+
+	closedAt := voteClosedAt(vote)
+	grace := time.Duration(vote.GracePeriod) * time.Second
+	if loginAt.Before(closedAt) && now.Before(closedAt.Add(grace)) {
+		return nil
+	}
+
+	return ErrVoteClosed
+}
+
+// voteClosedAt 投票實際結束的時間：提前結束時為結束投票的時間，否則為 EndTime
+func voteClosedAt(vote model.Vote) time.Time {
+	if vote.ClosedAt != nil && vote.ClosedAt.Before(vote.EndTime) {
+		return *vote.ClosedAt
+	}
+
+	return vote.EndTime
+}
+
 // GetVotes 檢索所有投票。
 func (v VoteService) GetVotes(isAdmin bool, userId uint64, voteQuery *model.VoteQuery) ([]*model.VoteConnection, error) {
 	// 查詢資料
@@ -73,8 +125,22 @@ func (v VoteService) CreateVote(form model.VoteCreate) (*model.Vote, error) {
 
 // UpdateOneVote 更新投票。
 func (v VoteService) UpdateVote(uuid uuid.UUID, form model.VoteUpdate) (*model.Vote, error) {
+	if form.AllowRevote != nil {
+		current, err := v.GetVote(uuid)
+		if err != nil {
+			return nil, err
+		}
+		if *form.AllowRevote != current.AllowRevote && current.Status != enum.Draft {
+			return nil, ErrAllowRevoteLocked
+		}
+	}
+
 	// 更新投票並掃描返回的結果
 	vote, updateErr := repository.NewVoteRepository().UpdateVote(uuid, form)
+	// 開啟或關閉即時結果會改變訂閱者收到的統計
+	if updateErr == nil && form.LiveResults != nil {
+		NotifyLiveResult(uuid)
+	}
 
 	return vote, updateErr
 }
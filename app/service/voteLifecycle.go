@@ -0,0 +1,145 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/model"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrVoteLocked 投票開始後不可再修改問題與候選人
+var ErrVoteLocked = errors.New("questions and candidates cannot be changed once the vote is open")
+
+// TransitionError 投票狀態無法轉換
+type TransitionError struct {
+	From   enum.VoteStatus
+	To     enum.VoteStatus
+	Reason string
+}
+
+func (e *TransitionError) Error() string {
+	return fmt.Sprintf("cannot change vote status from %s to %s: %s", e.From, e.To, e.Reason)
+}
+
+// TransitionVote 將投票轉換至新的狀態並記錄操作者，actorId 為 nil 表示由系統執行
+func (v VoteService) TransitionVote(voteId uuid.UUID, to enum.VoteStatus, actorId *uint64) (*model.Vote, error) {
+	vote := &model.Vote{}
+	err := database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("uuid = ?", voteId).
+			First(vote).Error
+		if err != nil {
+			return err
+		}
+
+		if err := checkTransition(tx, *vote, to); err != nil {
+			return err
+		}
+
+		return transitionVote(tx, vote, to, actorId)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return vote, nil
+}
+
+// GetVoteTransitions 取得投票的狀態轉換紀錄，依時間排序
+func (v VoteService) GetVoteTransitions(voteId uuid.UUID) ([]model.VoteTransition, error) {
+	var transitions []model.VoteTransition
+	err := database.SqlSession.
+		Where("vote_id = ?", voteId).
+		Order("id ASC").
+		Find(&transitions).Error
+
+	return transitions, err
+}
+
+// CheckVoteEditable 檢查投票的問題與候選人是否仍可編輯
+func CheckVoteEditable(vote model.Vote) error {
+	if !vote.Status.IsEditable() {
+		return ErrVoteLocked
+	}
+
+	return nil
+}
+
+// checkTransition 檢查狀態是否允許轉換，以及轉換前必須滿足的條件
+func checkTransition(tx *gorm.DB, vote model.Vote, to enum.VoteStatus) error {
+	if !vote.Status.CanTransitionTo(to) {
+		return &TransitionError{From: vote.Status, To: to, Reason: "transition not allowed"}
+	}
+
+	switch to {
+	case enum.Scheduled, enum.Open:
+		if !vote.EndTime.After(vote.StartTime) {
+			return &TransitionError{From: vote.Status, To: to, Reason: "end time must be after start time"}
+		}
+		var questions int64
+		err := tx.Model(&model.Question{}).Where("vote_id = ?", vote.Uuid).Count(&questions).Error
+		if err != nil {
+			return err
+		}
+		if questions == 0 {
+			return &TransitionError{From: vote.Status, To: to, Reason: "vote has no questions"}
+		}
+		if vote.Encrypted {
+			if vote.PublicKey == nil {
+				return &TransitionError{From: vote.Status, To: to, Reason: "trustee key ceremony is not complete"}
+			}
+			var ceremonyErr *CeremonyError
+			if err := checkEncryptableQuestions(tx, vote.Uuid); errors.As(err, &ceremonyErr) {
+				return &TransitionError{From: vote.Status, To: to, Reason: ceremonyErr.Reason}
+			} else if err != nil {
+				return err
+			}
+		}
+	case enum.Tallied:
+		var results int64
+		err := tx.Model(&model.VoteResult{}).Where("vote_id = ?", vote.Uuid).Count(&results).Error
+		if err != nil {
+			return err
+		}
+		if results == 0 {
+			return &TransitionError{From: vote.Status, To: to, Reason: "vote has not been tallied"}
+		}
+	}
+
+	return nil
+}
+
+// transitionVote 更新投票狀態並寫入轉換紀錄，並通知即時統計的訂閱者。
+// 排程、手動變更與開票都經過這裡；統計在 Throttle 後才進行，屆時交易已提交。
+func transitionVote(tx *gorm.DB, vote *model.Vote, to enum.VoteStatus, actorId *uint64) error {
+	transition := model.VoteTransition{
+		VoteID:     vote.Uuid,
+		FromStatus: vote.Status,
+		ToStatus:   to,
+		ActorID:    actorId,
+	}
+
+	columns := map[string]any{"status": to}
+	if to == enum.Closed {
+		now := time.Now()
+		columns["closed_at"] = now
+		vote.ClosedAt = &now
+	}
+	err := tx.Model(&model.Vote{}).Where("id = ?", vote.ID).Updates(columns).Error
+	if err != nil {
+		return err
+	}
+	vote.Status = to
+	if err := tx.Create(&transition).Error; err != nil {
+		return err
+	}
+	NotifyLiveResult(vote.Uuid)
+
+	return nil
+}
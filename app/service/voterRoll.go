@@ -0,0 +1,180 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/mail"
+	"vote/app/model"
+	"vote/app/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type VoterRollService struct {
+}
+
+func NewVoterRollService() VoterRollService {
+	return VoterRollService{}
+}
+
+// CreateVoters 將投票者加入名冊，並為每位投票者發放一組密碼
+func (v VoterRollService) CreateVoters(voteId uuid.UUID, form model.VoterRollCreate) ([]model.Voter, error) {
+	if err := v.checkDuplicates(voteId, form.Voters); err != nil {
+		return nil, err
+	}
+
+	passwords, err := NewPasswordService().generatePasswords(voteId, len(form.Voters), form.Length, form.Format)
+	if err != nil {
+		return nil, err
+	}
+	for i := range passwords {
+		passwords[i].Status = form.Active
+		passwords[i].State = credentialState(form.Active)
+	}
+
+	voters := make([]model.Voter, len(form.Voters))
+	err = database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		if err := tx.CreateInBatches(&passwords, 100).Error; err != nil {
+			return err
+		}
+
+		for i, voter := range form.Voters {
+			voters[i] = model.Voter{
+				VoteID:         voteId,
+				Name:           voter.Name,
+				Email:          voter.Email,
+				ExternalID:     voter.ExternalID,
+				Weight:         max(voter.Weight, 1),
+				PasswordID:     passwords[i].ID,
+				DeliveryStatus: enum.DeliveryPending,
+			}
+		}
+
+		return tx.CreateInBatches(&voters, 100).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return voters, nil
+}
+
+// GetVoters 取得投票的名冊
+func (v VoterRollService) GetVoters(voteId uuid.UUID) ([]model.Voter, error) {
+	var voters []model.Voter
+	err := database.SqlSession.
+		Where("vote_id = ?", voteId).
+		Order("id ASC").
+		Find(&voters).Error
+
+	return voters, err
+}
+
+// SendCredentials 將密碼郵件排入佇列，寄給名冊中尚未寄送成功的投票者，回傳排入佇列的人數
+func (v VoterRollService) SendCredentials(vote model.Vote) (int, error) {
+	queued := 0
+	err := database.SqlSession.Transaction(func(tx *gorm.DB) error {
+		var voters []model.Voter
+		err := tx.
+			Where("vote_id = ? AND delivery_status <> ?", vote.Uuid, enum.DeliverySent).
+			Order("id ASC").
+			Find(&voters).Error
+		if err != nil {
+			return err
+		}
+
+		queued, err = NewMailQueueService().EnqueueVoters(tx, voters, mail.Invitation)
+		return err
+	})
+
+	return queued, err
+}
+
+// ResendCredential 將密碼郵件重新排入佇列寄給單一投票者，已有密碼郵件在佇列中時不重複排入。
+// 寄送結果由郵件工作者寫回投票者的寄送狀態。
+func (v VoterRollService) ResendCredential(vote model.Vote, voterId uint64) (*model.Voter, int, error) {
+	voter := &model.Voter{}
+	err := database.SqlSession.
+		Where("vote_id = ? AND id = ?", vote.Uuid, voterId).
+		First(voter).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	queued, err := NewMailQueueService().EnqueueVoters(database.SqlSession, []model.Voter{*voter}, mail.Invitation)
+
+	return voter, queued, err
+}
+
+// invitation 產生寄給投票者的邀請郵件內容
+func (v VoterRollService) invitation(vote model.Vote, voter model.Voter) (mail.InvitationData, error) {
+	password := model.Password{}
+	if err := database.SqlSession.Select([]string{"id", "password"}).First(&password, voter.PasswordID).Error; err != nil {
+		return mail.InvitationData{}, err
+	}
+	plain, err := (&utils.Password{}).Decrypt(password.Password)
+	if err != nil {
+		return mail.InvitationData{}, err
+	}
+
+	return mail.InvitationData{
+		Name:      voter.Name,
+		VoteTitle: vote.Title,
+		StartTime: vote.StartTime,
+		EndTime:   vote.EndTime,
+		Password:  plain,
+		LoginURL:  VoterLoginURL(vote, plain),
+	}, nil
+}
+
+// checkDuplicates 檢查電子郵件與外部編號在名冊中不重複
+func (v VoterRollService) checkDuplicates(voteId uuid.UUID, voters []model.VoterCreate) error {
+	emails := make(map[string]struct{}, len(voters))
+	externalIds := make(map[string]struct{}, len(voters))
+	for _, voter := range voters {
+		email := strings.ToLower(voter.Email)
+		if _, ok := emails[email]; ok {
+			return fmt.Errorf("duplicate email %s", voter.Email)
+		}
+		emails[email] = struct{}{}
+
+		if voter.ExternalID == "" {
+			continue
+		}
+		if _, ok := externalIds[voter.ExternalID]; ok {
+			return fmt.Errorf("duplicate external id %s", voter.ExternalID)
+		}
+		externalIds[voter.ExternalID] = struct{}{}
+	}
+
+	var existing []model.Voter
+	err := database.SqlSession.
+		Select([]string{"email", "external_id"}).
+		Where("vote_id = ?", voteId).
+		Find(&existing).Error
+	if err != nil {
+		return err
+	}
+	for _, voter := range existing {
+		if _, ok := emails[strings.ToLower(voter.Email)]; ok {
+			return fmt.Errorf("email %s is already on the roll", voter.Email)
+		}
+		if _, ok := externalIds[voter.ExternalID]; ok && voter.ExternalID != "" {
+			return fmt.Errorf("external id %s is already on the roll", voter.ExternalID)
+		}
+	}
+
+	return nil
+}
+
+// truncate 截斷字串至指定長度
+func truncate(text string, length int) string {
+	if len(text) <= length {
+		return text
+	}
+
+	return text[:length]
+}
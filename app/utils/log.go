@@ -9,16 +9,18 @@ import (
 )
 
 // Logger 建立並返回一個配置好的 logrus.Logger 實例。
-// 它會在當前工作目錄下創建一個 logs 目錄，並在其中生成一個以當前日期命名的日誌文件。
+// 它會在 LOG_DIR 指定的目錄（未設定時為當前工作目錄下的 logs 目錄）中生成一個以當前日期命名的日誌文件。
 // 日誌文件會以追加模式打開，並設置日誌級別為 Debug。
 // 日誌的時間戳格式為 "2006-01-02 15:04:05"。
 func Logger() *logrus.Logger {
 	// 取得當前時間
 	now := time.Now()
 	// 設定日誌文件路徑
-	logFilePath := ""
-	if dir, err := os.Getwd(); err == nil {
-		logFilePath = dir + "/logs/"
+	logFilePath := os.Getenv("LOG_DIR")
+	if logFilePath == "" {
+		if dir, err := os.Getwd(); err == nil {
+			logFilePath = dir + "/logs/"
+		}
 	}
 	// 創建 logs 目錄
 	if err := os.MkdirAll(logFilePath, 0777); err != nil {
@@ -3,8 +3,11 @@ package utils
 import (
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/hmac"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"math/big"
 	"os"
@@ -147,3 +150,19 @@ func (p *Password) Decrypt(text string) (string, error) {
 	// 返回解密後的字串，從 IV 之後開始
 	return string(ciphertext[aes.BlockSize:]), nil
 }
+
+// Digest 以 APP_HMAC_KEY 計算固定的 HMAC-SHA256 摘要，用於查詢密碼。
+// 相同的 scope 與字串永遠得到相同的摘要，scope 用來區分不同的投票。
+func (p *Password) Digest(scope string, text string) (string, error) {
+	key := os.Getenv("APP_HMAC_KEY")
+	if key == "" {
+		return "", errors.New("APP_HMAC_KEY is not set")
+	}
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(scope))
+	mac.Write([]byte{0})
+	mac.Write([]byte(text))
+
+	return hex.EncodeToString(mac.Sum(nil)), nil
+}
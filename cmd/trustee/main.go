@@ -0,0 +1,240 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+
+	"vote/app/elgamal"
+	"vote/app/model"
+	"vote/app/service"
+)
+
+var (
+	// 後端網址，例如 http://localhost:8080
+	serverFlag = flag.String("server", "http://localhost:8080", "backend base URL")
+	// 主辦單位交給受託人的權杖
+	tokenFlag = flag.String("token", os.Getenv("TRUSTEE_TOKEN"), "trustee token, defaults to TRUSTEE_TOKEN")
+	// 保存傳輸私鑰的檔案，只存在受託人自己的電腦上
+	stateFlag = flag.String("state", "trustee.json", "file holding the transport secret key")
+)
+
+// trusteeState 受託人本機保存的資料
+type trusteeState struct {
+	TransportSecret *elgamal.Int `json:"transport_secret"`
+}
+
+// 受託人端的金鑰產生與部分解密，私鑰與金鑰分片都不會離開受託人的電腦。
+// 依序執行 key、deal，投票結束後執行 decrypt；status 顯示目前進度。
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: trustee [flags] key|deal|decrypt|status")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 || *tokenFlag == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var err error
+	switch flag.Arg(0) {
+	case "key":
+		err = uploadKey()
+	case "deal":
+		err = deal()
+	case "decrypt":
+		err = decrypt()
+	case "status":
+		err = status()
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// uploadKey 產生（或沿用）傳輸金鑰並上傳公鑰
+func uploadKey() error {
+	state, err := loadState()
+	if errors.Is(err, os.ErrNotExist) {
+		secret, _, err := elgamal.GenerateKey(nil)
+		if err != nil {
+			return err
+		}
+		state = &trusteeState{TransportSecret: elgamal.NewInt(secret)}
+		if err := saveState(state); err != nil {
+			return err
+		}
+	} else if err != nil {
+		return err
+	}
+
+	public := new(big.Int).Exp(elgamal.G, state.TransportSecret.Big(), elgamal.P)
+	if err := request(http.MethodPut, "/v1/trustee/key", model.TrusteeKey{TransportKey: elgamal.NewInt(public)}, nil); err != nil {
+		return err
+	}
+	fmt.Println("transport key uploaded, keep", *stateFlag, "safe until the tally is decrypted")
+
+	return nil
+}
+
+// deal 所有受託人都上傳傳輸公鑰後，產生秘密多項式並送出承諾與加密分片
+func deal() error {
+	ceremony, err := getCeremony()
+	if err != nil {
+		return err
+	}
+
+	form, err := service.DealShares(ceremony.VoteID, ceremony.Number, ceremony.Threshold, ceremony.Trustees, nil)
+	if err != nil {
+		return err
+	}
+	if err := request(http.MethodPost, "/v1/trustee/deal", form, nil); err != nil {
+		return err
+	}
+	fmt.Println("shares dealt to", len(form.Shares), "trustees")
+
+	return nil
+}
+
+// decrypt 驗證收到的分片，對合計密文部分解密並送出
+func decrypt() error {
+	state, err := loadState()
+	if err != nil {
+		return err
+	}
+	ceremony, err := getCeremony()
+	if err != nil {
+		return err
+	}
+	if len(ceremony.Tally) == 0 {
+		return fmt.Errorf("nothing to decrypt yet, ceremony status is %s", ceremony.Status)
+	}
+
+	share, err := service.OpenTrusteeShare(ceremony.VoteID, ceremony.Number, state.TransportSecret.Big(), ceremony.Trustees, ceremony.Shares)
+	if err != nil {
+		return err
+	}
+	partials, err := service.PartialDecryptions(ceremony.VoteID, ceremony.Number, share, ceremony.Tally, nil)
+	if err != nil {
+		return err
+	}
+
+	var result *model.VoteResult
+	if err := request(http.MethodPost, "/v1/trustee/decrypt", model.TrusteeDecrypt{Partials: partials}, &result); err != nil {
+		return err
+	}
+	if result == nil {
+		fmt.Println("partial decryption accepted, waiting for more trustees")
+		return nil
+	}
+	for _, question := range result.Questions {
+		fmt.Printf("question %d %s\n", question.QuestionID, question.Title)
+		for _, candidate := range question.Candidates {
+			fmt.Printf("  %-30s %d\n", candidate.Name, candidate.Votes)
+		}
+	}
+
+	return nil
+}
+
+func status() error {
+	ceremony, err := getCeremony()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("vote %s: %s, trustee %d of %d, threshold %d\n", ceremony.VoteID, ceremony.Status, ceremony.Number, len(ceremony.Trustees), ceremony.Threshold)
+	for _, trustee := range ceremony.Trustees {
+		fmt.Printf("  %d %-20s key:%-5t dealt:%-5t decrypted:%t\n", trustee.Number, trustee.Name, trustee.TransportKey != nil, trustee.Commitments != nil, trustee.Decrypted)
+	}
+
+	return nil
+}
+
+func getCeremony() (*model.TrusteeCeremony, error) {
+	ceremony := &model.TrusteeCeremony{}
+	if err := request(http.MethodGet, "/v1/trustee/ceremony", nil, ceremony); err != nil {
+		return nil, err
+	}
+
+	return ceremony, nil
+}
+
+// request 呼叫後端 API，out 為回應中的 data
+func request(method string, path string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequest(method, strings.TrimRight(*serverFlag, "/")+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Trustee-Token", *tokenFlag)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	envelope := struct {
+		Msg  string          `json:"msg"`
+		Data json.RawMessage `json:"data"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: %s", method, path, envelope.Msg)
+	}
+	if out != nil && len(envelope.Data) > 0 {
+		return json.Unmarshal(envelope.Data, out)
+	}
+
+	return nil
+}
+
+func loadState() (*trusteeState, error) {
+	data, err := os.ReadFile(*stateFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	state := &trusteeState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, err
+	}
+	if state.TransportSecret == nil {
+		return nil, fmt.Errorf("%s has no transport secret", *stateFlag)
+	}
+
+	return state, nil
+}
+
+func saveState(state *trusteeState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(*stateFlag, data, 0o600)
+}
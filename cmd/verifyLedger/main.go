@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	"github.com/joho/godotenv"
+
+	"vote/app/database"
+	"vote/app/model"
+	"vote/app/service"
+)
+
+var (
+	// 要驗證的投票，未指定時驗證所有投票
+	voteFlag = flag.String("vote", "", "vote UUID to verify, all votes when empty")
+	// 事先公布的雜湊鏈最後一筆雜湊，用來偵測整條鏈被重新計算
+	headFlag = flag.String("head", "", "expected head hash published when the polls closed")
+)
+
+// 重新計算選票雜湊鏈，發現竄改或遺失時輸出第一筆問題並以狀態碼 1 結束
+func main() {
+	flag.Parse()
+
+	// 載入 .env 檔案
+	if err := godotenv.Load(); err != nil {
+		panic(err)
+	}
+
+	// 從環境變數取得資料庫設定並初始化
+	if _, err := database.Initialize(database.DbConfig()); err != nil {
+		panic(err)
+	}
+
+	var voteIds []uuid.UUID
+	if *voteFlag != "" {
+		voteId, err := uuid.Parse(*voteFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "invalid vote UUID:", err)
+			os.Exit(2)
+		}
+		voteIds = append(voteIds, voteId)
+	} else {
+		if *headFlag != "" {
+			fmt.Fprintln(os.Stderr, "-head requires -vote")
+			os.Exit(2)
+		}
+		err := database.SqlSession.Model(&model.Vote{}).Order("id ASC").Pluck("uuid", &voteIds).Error
+		if err != nil {
+			panic(err)
+		}
+	}
+
+	failed := false
+	for _, voteId := range voteIds {
+		report, err := service.NewLedgerService().Verify(voteId)
+		if err != nil {
+			panic(err)
+		}
+
+		if report.Valid && *headFlag != "" && report.HeadHash != *headFlag {
+			report.Valid = false
+			report.Issue = &model.LedgerIssue{
+				Sequence: report.Entries,
+				Kind:     "head",
+				Message:  fmt.Sprintf("head hash %s does not match the published %s", report.HeadHash, *headFlag),
+			}
+		}
+
+		if report.Valid {
+			fmt.Printf("OK       %s entries=%d head=%s\n", voteId, report.Entries, report.HeadHash)
+			continue
+		}
+		failed = true
+		fmt.Printf("TAMPERED %s sequence=%d ballot=%d kind=%s: %s\n",
+			voteId, report.Issue.Sequence, report.Issue.BallotID, report.Issue.Kind, report.Issue.Message)
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
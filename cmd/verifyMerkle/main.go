@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"vote/app/model"
+	"vote/app/service"
+)
+
+var (
+	// 由 GET /v1/merkle/proof/{code} 下載的 JSON，未指定時從標準輸入讀取
+	fileFlag = flag.String("file", "", "proof bundle JSON, stdin when empty")
+	// 投票時取得的回條代碼，用來重新計算葉節點，不能取自下載的證明
+	codeFlag = flag.String("code", "", "receipt code received when voting")
+	// 預期的後端驗證金鑰地址，未指定時只檢查簽章與公布的地址一致
+	signerFlag = flag.String("signer", "", "expected signer address of the backend verifier key")
+)
+
+// 離線驗證選票 Merkle 樹根的簽章與包含證明，不需要連線資料庫，驗證失敗時以狀態碼 1 結束。
+// 通過時表示簽署的樹根包含此回條代碼的選票；選票內容是否遭竄改由 verifyLedger 檢查。
+func main() {
+	flag.Parse()
+	if *codeFlag == "" {
+		fmt.Fprintln(os.Stderr, "-code is required")
+		os.Exit(2)
+	}
+
+	input := io.Reader(os.Stdin)
+	if *fileFlag != "" {
+		file, err := os.Open(*fileFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		defer file.Close()
+		input = file
+	}
+
+	raw, err := io.ReadAll(input)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// 同時接受 API 回應與單獨的證明內容
+	var response struct {
+		Data *model.MerkleProofBundle `json:"data"`
+	}
+	bundle := model.MerkleProofBundle{}
+	if err := json.Unmarshal(raw, &response); err == nil && response.Data != nil {
+		bundle = *response.Data
+	} else if err := json.Unmarshal(raw, &bundle); err != nil {
+		fmt.Fprintln(os.Stderr, "invalid proof bundle:", err)
+		os.Exit(2)
+	}
+
+	if err := service.VerifyMerkleBundle(bundle, *codeFlag, *signerFlag); err != nil {
+		fmt.Printf("vote %s: FAILED %s\n", bundle.VoteID, err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("vote %s: ok, root %s signed by %s, %d ballots of receipt %s among %d proven\n",
+		bundle.VoteID, bundle.Root, bundle.Signer, len(bundle.Proofs), *codeFlag, bundle.LeafCount)
+	if bundle.AnchorTx != "" {
+		fmt.Printf("anchored in transaction %s\n", bundle.AnchorTx)
+	}
+}
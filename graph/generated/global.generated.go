@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"sync/atomic"
 	"time"
+	"vote/app/enum"
 	"vote/app/model"
 	model1 "vote/graph/model"
 
@@ -256,6 +257,16 @@ func (ec *executionContext) marshalNPageInfo2voteᚋappᚋmodelᚐPageInfo(ctx c
 	return ec._PageInfo(ctx, sel, &v)
 }
 
+func (ec *executionContext) unmarshalNTieBreak2voteᚋappᚋenumᚐTieBreak(ctx context.Context, v any) (enum.TieBreak, error) {
+	var res enum.TieBreak
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNTieBreak2voteᚋappᚋenumᚐTieBreak(ctx context.Context, sel ast.SelectionSet, v enum.TieBreak) graphql.Marshaler {
+	return v
+}
+
 func (ec *executionContext) unmarshalNTime2timeᚐTime(ctx context.Context, v any) (time.Time, error) {
 	res, err := graphql.UnmarshalTime(v)
 	return res, graphql.ErrorOnPath(ctx, err)
@@ -318,6 +329,26 @@ func (ec *executionContext) marshalNUUID2ᚕgithubᚗcomᚋgoogleᚋuuidᚐUUID
 	return ret
 }
 
+func (ec *executionContext) unmarshalNVoteStatus2voteᚋappᚋenumᚐVoteStatus(ctx context.Context, v any) (enum.VoteStatus, error) {
+	var res enum.VoteStatus
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNVoteStatus2voteᚋappᚋenumᚐVoteStatus(ctx context.Context, sel ast.SelectionSet, v enum.VoteStatus) graphql.Marshaler {
+	return v
+}
+
+func (ec *executionContext) unmarshalNVotingMethod2voteᚋappᚋenumᚐVotingMethod(ctx context.Context, v any) (enum.VotingMethod, error) {
+	var res enum.VotingMethod
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalNVotingMethod2voteᚋappᚋenumᚐVotingMethod(ctx context.Context, sel ast.SelectionSet, v enum.VotingMethod) graphql.Marshaler {
+	return v
+}
+
 func (ec *executionContext) unmarshalOInt642int(ctx context.Context, v any) (int, error) {
 	res, err := graphql.UnmarshalInt(v)
 	return res, graphql.ErrorOnPath(ctx, err)
@@ -330,6 +361,16 @@ func (ec *executionContext) marshalOInt642int(ctx context.Context, sel ast.Selec
 	return res
 }
 
+func (ec *executionContext) unmarshalOTieBreak2voteᚋappᚋenumᚐTieBreak(ctx context.Context, v any) (enum.TieBreak, error) {
+	var res enum.TieBreak
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOTieBreak2voteᚋappᚋenumᚐTieBreak(ctx context.Context, sel ast.SelectionSet, v enum.TieBreak) graphql.Marshaler {
+	return v
+}
+
 func (ec *executionContext) unmarshalOTime2timeᚐTime(ctx context.Context, v any) (time.Time, error) {
 	res, err := graphql.UnmarshalTime(v)
 	return res, graphql.ErrorOnPath(ctx, err)
@@ -354,4 +395,14 @@ func (ec *executionContext) marshalOUUID2githubᚗcomᚋgoogleᚋuuidᚐUUID(ctx
 	return res
 }
 
+func (ec *executionContext) unmarshalOVotingMethod2voteᚋappᚋenumᚐVotingMethod(ctx context.Context, v any) (enum.VotingMethod, error) {
+	var res enum.VotingMethod
+	err := res.UnmarshalGQL(v)
+	return res, graphql.ErrorOnPath(ctx, err)
+}
+
+func (ec *executionContext) marshalOVotingMethod2voteᚋappᚋenumᚐVotingMethod(ctx context.Context, sel ast.SelectionSet, v enum.VotingMethod) graphql.Marshaler {
+	return v
+}
+
 // endregion ***************************** type.gotpl *****************************
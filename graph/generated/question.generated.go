@@ -17,6 +17,10 @@ import (
 
 // region    ************************** generated!.gotpl **************************
 
+type QuestionResolver interface {
+	Result(ctx context.Context, obj *model.Question) (*model.QuestionResult, error)
+}
+
 // endregion ************************** generated!.gotpl **************************
 
 // region    ***************************** args.gotpl *****************************
@@ -156,6 +160,151 @@ func (ec *executionContext) fieldContext_Question_description(_ context.Context,
 	return fc, nil
 }
 
+func (ec *executionContext) _Question_method(ctx context.Context, field graphql.CollectedField, obj *model.Question) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Question_method,
+		func(ctx context.Context) (any, error) {
+			return obj.Method, nil
+		},
+		nil,
+		ec.marshalNVotingMethod2voteᚋappᚋenumᚐVotingMethod,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Question_method(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Question",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type VotingMethod does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Question_seats(ctx context.Context, field graphql.CollectedField, obj *model.Question) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Question_seats,
+		func(ctx context.Context) (any, error) {
+			return obj.Seats, nil
+		},
+		nil,
+		ec.marshalNInt642int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Question_seats(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Question",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int64 does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Question_maxScore(ctx context.Context, field graphql.CollectedField, obj *model.Question) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Question_maxScore,
+		func(ctx context.Context) (any, error) {
+			return obj.MaxScore, nil
+		},
+		nil,
+		ec.marshalNInt642int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Question_maxScore(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Question",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int64 does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Question_minSelections(ctx context.Context, field graphql.CollectedField, obj *model.Question) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Question_minSelections,
+		func(ctx context.Context) (any, error) {
+			return obj.MinSelections, nil
+		},
+		nil,
+		ec.marshalNInt642int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Question_minSelections(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Question",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int64 does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Question_maxSelections(ctx context.Context, field graphql.CollectedField, obj *model.Question) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Question_maxSelections,
+		func(ctx context.Context) (any, error) {
+			return obj.MaxSelections, nil
+		},
+		nil,
+		ec.marshalNInt642int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Question_maxSelections(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Question",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Int64 does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Question_createdAt(ctx context.Context, field graphql.CollectedField, obj *model.Question) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -242,10 +391,10 @@ func (ec *executionContext) fieldContext_Question_candidates(_ context.Context,
 				return ec.fieldContext_Candidate_id(ctx, field)
 			case "questionId":
 				return ec.fieldContext_Candidate_questionId(ctx, field)
-			case "Name":
-				return ec.fieldContext_Candidate_Name(ctx, field)
-			case "Result":
-				return ec.fieldContext_Candidate_Result(ctx, field)
+			case "name":
+				return ec.fieldContext_Candidate_name(ctx, field)
+			case "result":
+				return ec.fieldContext_Candidate_result(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_Candidate_createdAt(ctx, field)
 			case "updatedAt":
@@ -257,6 +406,67 @@ func (ec *executionContext) fieldContext_Question_candidates(_ context.Context,
 	return fc, nil
 }
 
+func (ec *executionContext) _Question_result(ctx context.Context, field graphql.CollectedField, obj *model.Question) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Question_result,
+		func(ctx context.Context) (any, error) {
+			return ec.resolvers.Question().Result(ctx, obj)
+		},
+		nil,
+		ec.marshalOQuestionResult2ᚖvoteᚋappᚋmodelᚐQuestionResult,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Question_result(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Question",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "questionId":
+				return ec.fieldContext_QuestionResult_questionId(ctx, field)
+			case "title":
+				return ec.fieldContext_QuestionResult_title(ctx, field)
+			case "method":
+				return ec.fieldContext_QuestionResult_method(ctx, field)
+			case "seats":
+				return ec.fieldContext_QuestionResult_seats(ctx, field)
+			case "quota":
+				return ec.fieldContext_QuestionResult_quota(ctx, field)
+			case "totalBallots":
+				return ec.fieldContext_QuestionResult_totalBallots(ctx, field)
+			case "validBallots":
+				return ec.fieldContext_QuestionResult_validBallots(ctx, field)
+			case "blankBallots":
+				return ec.fieldContext_QuestionResult_blankBallots(ctx, field)
+			case "abstentions":
+				return ec.fieldContext_QuestionResult_abstentions(ctx, field)
+			case "candidates":
+				return ec.fieldContext_QuestionResult_candidates(ctx, field)
+			case "winners":
+				return ec.fieldContext_QuestionResult_winners(ctx, field)
+			case "rounds":
+				return ec.fieldContext_QuestionResult_rounds(ctx, field)
+			case "pairwise":
+				return ec.fieldContext_QuestionResult_pairwise(ctx, field)
+			case "condorcetWinner":
+				return ec.fieldContext_QuestionResult_condorcetWinner(ctx, field)
+			case "ranking":
+				return ec.fieldContext_QuestionResult_ranking(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type QuestionResult", field.Name)
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _QuestionConnection_edges(ctx context.Context, field graphql.CollectedField, obj *model.QuestionConnection) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -392,12 +602,24 @@ func (ec *executionContext) fieldContext_QuestionEdge_node(_ context.Context, fi
 				return ec.fieldContext_Question_title(ctx, field)
 			case "description":
 				return ec.fieldContext_Question_description(ctx, field)
+			case "method":
+				return ec.fieldContext_Question_method(ctx, field)
+			case "seats":
+				return ec.fieldContext_Question_seats(ctx, field)
+			case "maxScore":
+				return ec.fieldContext_Question_maxScore(ctx, field)
+			case "minSelections":
+				return ec.fieldContext_Question_minSelections(ctx, field)
+			case "maxSelections":
+				return ec.fieldContext_Question_maxSelections(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_Question_createdAt(ctx, field)
 			case "updatedAt":
 				return ec.fieldContext_Question_updatedAt(ctx, field)
 			case "candidates":
 				return ec.fieldContext_Question_candidates(ctx, field)
+			case "result":
+				return ec.fieldContext_Question_result(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type Question", field.Name)
 		},
@@ -445,7 +667,7 @@ func (ec *executionContext) unmarshalInputQuestionCreate(ctx context.Context, ob
 		asMap[k] = v
 	}
 
-	fieldsInOrder := [...]string{"voteId", "title", "description"}
+	fieldsInOrder := [...]string{"voteId", "title", "description", "method", "seats", "maxScore", "minSelections", "maxSelections"}
 	for _, k := range fieldsInOrder {
 		v, ok := asMap[k]
 		if !ok {
@@ -473,6 +695,41 @@ func (ec *executionContext) unmarshalInputQuestionCreate(ctx context.Context, ob
 				return it, err
 			}
 			it.Description = data
+		case "method":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("method"))
+			data, err := ec.unmarshalOVotingMethod2voteᚋappᚋenumᚐVotingMethod(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Method = data
+		case "seats":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("seats"))
+			data, err := ec.unmarshalOInt642int(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.Seats = data
+		case "maxScore":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("maxScore"))
+			data, err := ec.unmarshalOInt642int(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.MaxScore = data
+		case "minSelections":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("minSelections"))
+			data, err := ec.unmarshalOInt642int(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.MinSelections = data
+		case "maxSelections":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("maxSelections"))
+			data, err := ec.unmarshalOInt642int(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.MaxSelections = data
 		}
 	}
 
@@ -563,38 +820,96 @@ func (ec *executionContext) _Question(ctx context.Context, sel ast.SelectionSet,
 		case "id":
 			out.Values[i] = ec._Question_id(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
-				out.Invalids++
+				atomic.AddUint32(&out.Invalids, 1)
 			}
 		case "voteId":
 			out.Values[i] = ec._Question_voteId(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
-				out.Invalids++
+				atomic.AddUint32(&out.Invalids, 1)
 			}
 		case "title":
 			out.Values[i] = ec._Question_title(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
-				out.Invalids++
+				atomic.AddUint32(&out.Invalids, 1)
 			}
 		case "description":
 			out.Values[i] = ec._Question_description(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
-				out.Invalids++
+				atomic.AddUint32(&out.Invalids, 1)
+			}
+		case "method":
+			out.Values[i] = ec._Question_method(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&out.Invalids, 1)
+			}
+		case "seats":
+			out.Values[i] = ec._Question_seats(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&out.Invalids, 1)
+			}
+		case "maxScore":
+			out.Values[i] = ec._Question_maxScore(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&out.Invalids, 1)
+			}
+		case "minSelections":
+			out.Values[i] = ec._Question_minSelections(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&out.Invalids, 1)
+			}
+		case "maxSelections":
+			out.Values[i] = ec._Question_maxSelections(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&out.Invalids, 1)
 			}
 		case "createdAt":
 			out.Values[i] = ec._Question_createdAt(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
-				out.Invalids++
+				atomic.AddUint32(&out.Invalids, 1)
 			}
 		case "updatedAt":
 			out.Values[i] = ec._Question_updatedAt(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
-				out.Invalids++
+				atomic.AddUint32(&out.Invalids, 1)
 			}
 		case "candidates":
 			out.Values[i] = ec._Question_candidates(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
-				out.Invalids++
+				atomic.AddUint32(&out.Invalids, 1)
+			}
+		case "result":
+			field := field
+
+			innerFunc := func(ctx context.Context, _ *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Question_result(ctx, field, obj)
+				return res
 			}
+
+			if field.Deferrable != nil {
+				dfs, ok := deferred[field.Deferrable.Label]
+				di := 0
+				if ok {
+					dfs.AddField(field)
+					di = len(dfs.Values) - 1
+				} else {
+					dfs = graphql.NewFieldSet([]graphql.CollectedField{field})
+					deferred[field.Deferrable.Label] = dfs
+				}
+				dfs.Concurrently(di, func(ctx context.Context) graphql.Marshaler {
+					return innerFunc(ctx, dfs)
+				})
+
+				// don't run the out.Concurrently() call below
+				out.Values[i] = graphql.Null
+				continue
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"sync/atomic"
+	"vote/app/enum"
 	"vote/app/model"
 
 	"github.com/99designs/gqlgen/graphql"
@@ -37,6 +38,8 @@ type ResolverRoot interface {
 	Candidate() CandidateResolver
 	Mutation() MutationResolver
 	Query() QueryResolver
+	Question() QuestionResolver
+	Subscription() SubscriptionResolver
 	Vote() VoteResolver
 }
 
@@ -54,11 +57,41 @@ type ComplexityRoot struct {
 		UpdatedAt  func(childComplexity int) int
 	}
 
+	CandidateResult struct {
+		Average     func(childComplexity int) int
+		CandidateID func(childComplexity int) int
+		Name        func(childComplexity int) int
+		Percentage  func(childComplexity int) int
+		Votes       func(childComplexity int) int
+	}
+
+	LiveCount struct {
+		CandidateID func(childComplexity int) int
+		Votes       func(childComplexity int) int
+	}
+
+	LiveQuestion struct {
+		Ballots    func(childComplexity int) int
+		Candidates func(childComplexity int) int
+		QuestionID func(childComplexity int) int
+	}
+
+	LiveResult struct {
+		Eligible  func(childComplexity int) int
+		Questions func(childComplexity int) int
+		Status    func(childComplexity int) int
+		Turnout   func(childComplexity int) int
+		UpdatedAt func(childComplexity int) int
+		VoteID    func(childComplexity int) int
+		Voted     func(childComplexity int) int
+	}
+
 	Mutation struct {
 		CreateQuestion func(childComplexity int, input model.QuestionCreate) int
 		CreateUser     func(childComplexity int, input model.UserCreate) int
 		CreateVote     func(childComplexity int, input model.VoteCreate) int
 		DeleteVote     func(childComplexity int, uuids []uuid.UUID) int
+		TransitionVote func(childComplexity int, uuid uuid.UUID, status enum.VoteStatus) int
 		UpdateVote     func(childComplexity int, uuid uuid.UUID, input model.VoteUpdate) int
 	}
 
@@ -69,6 +102,16 @@ type ComplexityRoot struct {
 		StartCursor     func(childComplexity int) int
 	}
 
+	PairwiseCount struct {
+		CandidateID func(childComplexity int) int
+		Votes       func(childComplexity int) int
+	}
+
+	PairwiseRow struct {
+		Against     func(childComplexity int) int
+		CandidateID func(childComplexity int) int
+	}
+
 	Query struct {
 		Questions func(childComplexity int, input *model.QuestionQuery, withCandidates bool) int
 		Users     func(childComplexity int) int
@@ -76,13 +119,19 @@ type ComplexityRoot struct {
 	}
 
 	Question struct {
-		Candidates  func(childComplexity int) int
-		CreatedAt   func(childComplexity int) int
-		Description func(childComplexity int) int
-		ID          func(childComplexity int) int
-		Title       func(childComplexity int) int
-		UpdatedAt   func(childComplexity int) int
-		VoteID      func(childComplexity int) int
+		Candidates    func(childComplexity int) int
+		CreatedAt     func(childComplexity int) int
+		Description   func(childComplexity int) int
+		ID            func(childComplexity int) int
+		MaxScore      func(childComplexity int) int
+		MaxSelections func(childComplexity int) int
+		Method        func(childComplexity int) int
+		MinSelections func(childComplexity int) int
+		Result        func(childComplexity int) int
+		Seats         func(childComplexity int) int
+		Title         func(childComplexity int) int
+		UpdatedAt     func(childComplexity int) int
+		VoteID        func(childComplexity int) int
 	}
 
 	QuestionConnection struct {
@@ -96,6 +145,48 @@ type ComplexityRoot struct {
 		Node   func(childComplexity int) int
 	}
 
+	QuestionResult struct {
+		Abstentions     func(childComplexity int) int
+		BlankBallots    func(childComplexity int) int
+		Candidates      func(childComplexity int) int
+		CondorcetWinner func(childComplexity int) int
+		Method          func(childComplexity int) int
+		Pairwise        func(childComplexity int) int
+		QuestionID      func(childComplexity int) int
+		Quota           func(childComplexity int) int
+		Ranking         func(childComplexity int) int
+		Rounds          func(childComplexity int) int
+		Seats           func(childComplexity int) int
+		Title           func(childComplexity int) int
+		TotalBallots    func(childComplexity int) int
+		ValidBallots    func(childComplexity int) int
+		Winners         func(childComplexity int) int
+	}
+
+	RoundCount struct {
+		CandidateID func(childComplexity int) int
+		Votes       func(childComplexity int) int
+	}
+
+	RoundTransfer struct {
+		From  func(childComplexity int) int
+		To    func(childComplexity int) int
+		Votes func(childComplexity int) int
+	}
+
+	Subscription struct {
+		LiveResult func(childComplexity int, uuid uuid.UUID) int
+	}
+
+	TallyRound struct {
+		Counts     func(childComplexity int) int
+		Elected    func(childComplexity int) int
+		Eliminated func(childComplexity int) int
+		Exhausted  func(childComplexity int) int
+		Round      func(childComplexity int) int
+		Transfers  func(childComplexity int) int
+	}
+
 	User struct {
 		Account func(childComplexity int) int
 		Email   func(childComplexity int) int
@@ -103,14 +194,20 @@ type ComplexityRoot struct {
 	}
 
 	Vote struct {
+		AllowRevote func(childComplexity int) int
 		Creator     func(childComplexity int) int
 		Description func(childComplexity int) int
 		EndTime     func(childComplexity int) int
+		GracePeriod func(childComplexity int) int
 		ID          func(childComplexity int) int
+		LiveResults func(childComplexity int) int
 		Questions   func(childComplexity int) int
+		Results     func(childComplexity int) int
 		StartTime   func(childComplexity int) int
 		Status      func(childComplexity int) int
+		TieBreak    func(childComplexity int) int
 		Title       func(childComplexity int) int
+		Transitions func(childComplexity int) int
 		Uuid        func(childComplexity int) int
 	}
 
@@ -124,6 +221,23 @@ type ComplexityRoot struct {
 		Cursor func(childComplexity int) int
 		Node   func(childComplexity int) int
 	}
+
+	VoteResult struct {
+		CreatedAt func(childComplexity int) int
+		Questions func(childComplexity int) int
+		UpdatedAt func(childComplexity int) int
+		VoteID    func(childComplexity int) int
+		Voters    func(childComplexity int) int
+	}
+
+	VoteTransition struct {
+		ActorID    func(childComplexity int) int
+		CreatedAt  func(childComplexity int) int
+		FromStatus func(childComplexity int) int
+		ID         func(childComplexity int) int
+		ToStatus   func(childComplexity int) int
+		VoteID     func(childComplexity int) int
+	}
 }
 
 type executableSchema struct {
@@ -159,7 +273,7 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Candidate.ID(childComplexity), true
 
-	case "Candidate.Name":
+	case "Candidate.name":
 		if e.complexity.Candidate.Name == nil {
 			break
 		}
@@ -173,7 +287,7 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Candidate.QuestionID(childComplexity), true
 
-	case "Candidate.Result":
+	case "Candidate.result":
 		if e.complexity.Candidate.Result == nil {
 			break
 		}
@@ -187,6 +301,125 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Candidate.UpdatedAt(childComplexity), true
 
+	case "CandidateResult.average":
+		if e.complexity.CandidateResult.Average == nil {
+			break
+		}
+
+		return e.complexity.CandidateResult.Average(childComplexity), true
+
+	case "CandidateResult.candidateId":
+		if e.complexity.CandidateResult.CandidateID == nil {
+			break
+		}
+
+		return e.complexity.CandidateResult.CandidateID(childComplexity), true
+
+	case "CandidateResult.name":
+		if e.complexity.CandidateResult.Name == nil {
+			break
+		}
+
+		return e.complexity.CandidateResult.Name(childComplexity), true
+
+	case "CandidateResult.percentage":
+		if e.complexity.CandidateResult.Percentage == nil {
+			break
+		}
+
+		return e.complexity.CandidateResult.Percentage(childComplexity), true
+
+	case "CandidateResult.votes":
+		if e.complexity.CandidateResult.Votes == nil {
+			break
+		}
+
+		return e.complexity.CandidateResult.Votes(childComplexity), true
+
+	case "LiveCount.candidateId":
+		if e.complexity.LiveCount.CandidateID == nil {
+			break
+		}
+
+		return e.complexity.LiveCount.CandidateID(childComplexity), true
+
+	case "LiveCount.votes":
+		if e.complexity.LiveCount.Votes == nil {
+			break
+		}
+
+		return e.complexity.LiveCount.Votes(childComplexity), true
+
+	case "LiveQuestion.ballots":
+		if e.complexity.LiveQuestion.Ballots == nil {
+			break
+		}
+
+		return e.complexity.LiveQuestion.Ballots(childComplexity), true
+
+	case "LiveQuestion.candidates":
+		if e.complexity.LiveQuestion.Candidates == nil {
+			break
+		}
+
+		return e.complexity.LiveQuestion.Candidates(childComplexity), true
+
+	case "LiveQuestion.questionId":
+		if e.complexity.LiveQuestion.QuestionID == nil {
+			break
+		}
+
+		return e.complexity.LiveQuestion.QuestionID(childComplexity), true
+
+	case "LiveResult.eligible":
+		if e.complexity.LiveResult.Eligible == nil {
+			break
+		}
+
+		return e.complexity.LiveResult.Eligible(childComplexity), true
+
+	case "LiveResult.questions":
+		if e.complexity.LiveResult.Questions == nil {
+			break
+		}
+
+		return e.complexity.LiveResult.Questions(childComplexity), true
+
+	case "LiveResult.status":
+		if e.complexity.LiveResult.Status == nil {
+			break
+		}
+
+		return e.complexity.LiveResult.Status(childComplexity), true
+
+	case "LiveResult.turnout":
+		if e.complexity.LiveResult.Turnout == nil {
+			break
+		}
+
+		return e.complexity.LiveResult.Turnout(childComplexity), true
+
+	case "LiveResult.updatedAt":
+		if e.complexity.LiveResult.UpdatedAt == nil {
+			break
+		}
+
+		return e.complexity.LiveResult.UpdatedAt(childComplexity), true
+
+	case "LiveResult.voteId":
+		if e.complexity.LiveResult.VoteID == nil {
+			break
+		}
+
+		return e.complexity.LiveResult.VoteID(childComplexity), true
+
+	case "LiveResult.voted":
+		if e.complexity.LiveResult.Voted == nil {
+			break
+		}
+
+		return e.complexity.LiveResult.Voted(childComplexity), true
+
 	case "Mutation.createQuestion":
 		if e.complexity.Mutation.CreateQuestion == nil {
 			break
@@ -235,6 +468,18 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Mutation.DeleteVote(childComplexity, args["uuids"].([]uuid.UUID)), true
 
+	case "Mutation.transitionVote":
+		if e.complexity.Mutation.TransitionVote == nil {
+			break
+		}
+
+		args, err := ec.field_Mutation_transitionVote_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Mutation.TransitionVote(childComplexity, args["uuid"].(uuid.UUID), args["status"].(enum.VoteStatus)), true
+
 	case "Mutation.updateVote":
 		if e.complexity.Mutation.UpdateVote == nil {
 			break
@@ -275,6 +520,34 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.PageInfo.StartCursor(childComplexity), true
 
+	case "PairwiseCount.candidateId":
+		if e.complexity.PairwiseCount.CandidateID == nil {
+			break
+		}
+
+		return e.complexity.PairwiseCount.CandidateID(childComplexity), true
+
+	case "PairwiseCount.votes":
+		if e.complexity.PairwiseCount.Votes == nil {
+			break
+		}
+
+		return e.complexity.PairwiseCount.Votes(childComplexity), true
+
+	case "PairwiseRow.against":
+		if e.complexity.PairwiseRow.Against == nil {
+			break
+		}
+
+		return e.complexity.PairwiseRow.Against(childComplexity), true
+
+	case "PairwiseRow.candidateId":
+		if e.complexity.PairwiseRow.CandidateID == nil {
+			break
+		}
+
+		return e.complexity.PairwiseRow.CandidateID(childComplexity), true
+
 	case "Query.questions":
 		if e.complexity.Query.Questions == nil {
 			break
@@ -334,6 +607,48 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Question.ID(childComplexity), true
 
+	case "Question.maxScore":
+		if e.complexity.Question.MaxScore == nil {
+			break
+		}
+
+		return e.complexity.Question.MaxScore(childComplexity), true
+
+	case "Question.maxSelections":
+		if e.complexity.Question.MaxSelections == nil {
+			break
+		}
+
+		return e.complexity.Question.MaxSelections(childComplexity), true
+
+	case "Question.method":
+		if e.complexity.Question.Method == nil {
+			break
+		}
+
+		return e.complexity.Question.Method(childComplexity), true
+
+	case "Question.minSelections":
+		if e.complexity.Question.MinSelections == nil {
+			break
+		}
+
+		return e.complexity.Question.MinSelections(childComplexity), true
+
+	case "Question.result":
+		if e.complexity.Question.Result == nil {
+			break
+		}
+
+		return e.complexity.Question.Result(childComplexity), true
+
+	case "Question.seats":
+		if e.complexity.Question.Seats == nil {
+			break
+		}
+
+		return e.complexity.Question.Seats(childComplexity), true
+
 	case "Question.title":
 		if e.complexity.Question.Title == nil {
 			break
@@ -390,6 +705,200 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.QuestionEdge.Node(childComplexity), true
 
+	case "QuestionResult.abstentions":
+		if e.complexity.QuestionResult.Abstentions == nil {
+			break
+		}
+
+		return e.complexity.QuestionResult.Abstentions(childComplexity), true
+
+	case "QuestionResult.blankBallots":
+		if e.complexity.QuestionResult.BlankBallots == nil {
+			break
+		}
+
+		return e.complexity.QuestionResult.BlankBallots(childComplexity), true
+
+	case "QuestionResult.candidates":
+		if e.complexity.QuestionResult.Candidates == nil {
+			break
+		}
+
+		return e.complexity.QuestionResult.Candidates(childComplexity), true
+
+	case "QuestionResult.condorcetWinner":
+		if e.complexity.QuestionResult.CondorcetWinner == nil {
+			break
+		}
+
+		return e.complexity.QuestionResult.CondorcetWinner(childComplexity), true
+
+	case "QuestionResult.method":
+		if e.complexity.QuestionResult.Method == nil {
+			break
+		}
+
+		return e.complexity.QuestionResult.Method(childComplexity), true
+
+	case "QuestionResult.pairwise":
+		if e.complexity.QuestionResult.Pairwise == nil {
+			break
+		}
+
+		return e.complexity.QuestionResult.Pairwise(childComplexity), true
+
+	case "QuestionResult.questionId":
+		if e.complexity.QuestionResult.QuestionID == nil {
+			break
+		}
+
+		return e.complexity.QuestionResult.QuestionID(childComplexity), true
+
+	case "QuestionResult.quota":
+		if e.complexity.QuestionResult.Quota == nil {
+			break
+		}
+
+		return e.complexity.QuestionResult.Quota(childComplexity), true
+
+	case "QuestionResult.ranking":
+		if e.complexity.QuestionResult.Ranking == nil {
+			break
+		}
+
+		return e.complexity.QuestionResult.Ranking(childComplexity), true
+
+	case "QuestionResult.rounds":
+		if e.complexity.QuestionResult.Rounds == nil {
+			break
+		}
+
+		return e.complexity.QuestionResult.Rounds(childComplexity), true
+
+	case "QuestionResult.seats":
+		if e.complexity.QuestionResult.Seats == nil {
+			break
+		}
+
+		return e.complexity.QuestionResult.Seats(childComplexity), true
+
+	case "QuestionResult.title":
+		if e.complexity.QuestionResult.Title == nil {
+			break
+		}
+
+		return e.complexity.QuestionResult.Title(childComplexity), true
+
+	case "QuestionResult.totalBallots":
+		if e.complexity.QuestionResult.TotalBallots == nil {
+			break
+		}
+
+		return e.complexity.QuestionResult.TotalBallots(childComplexity), true
+
+	case "QuestionResult.validBallots":
+		if e.complexity.QuestionResult.ValidBallots == nil {
+			break
+		}
+
+		return e.complexity.QuestionResult.ValidBallots(childComplexity), true
+
+	case "QuestionResult.winners":
+		if e.complexity.QuestionResult.Winners == nil {
+			break
+		}
+
+		return e.complexity.QuestionResult.Winners(childComplexity), true
+
+	case "RoundCount.candidateId":
+		if e.complexity.RoundCount.CandidateID == nil {
+			break
+		}
+
+		return e.complexity.RoundCount.CandidateID(childComplexity), true
+
+	case "RoundCount.votes":
+		if e.complexity.RoundCount.Votes == nil {
+			break
+		}
+
+		return e.complexity.RoundCount.Votes(childComplexity), true
+
+	case "RoundTransfer.from":
+		if e.complexity.RoundTransfer.From == nil {
+			break
+		}
+
+		return e.complexity.RoundTransfer.From(childComplexity), true
+
+	case "RoundTransfer.to":
+		if e.complexity.RoundTransfer.To == nil {
+			break
+		}
+
+		return e.complexity.RoundTransfer.To(childComplexity), true
+
+	case "RoundTransfer.votes":
+		if e.complexity.RoundTransfer.Votes == nil {
+			break
+		}
+
+		return e.complexity.RoundTransfer.Votes(childComplexity), true
+
+	case "Subscription.liveResult":
+		if e.complexity.Subscription.LiveResult == nil {
+			break
+		}
+
+		args, err := ec.field_Subscription_liveResult_args(ctx, rawArgs)
+		if err != nil {
+			return 0, false
+		}
+
+		return e.complexity.Subscription.LiveResult(childComplexity, args["uuid"].(uuid.UUID)), true
+
+	case "TallyRound.counts":
+		if e.complexity.TallyRound.Counts == nil {
+			break
+		}
+
+		return e.complexity.TallyRound.Counts(childComplexity), true
+
+	case "TallyRound.elected":
+		if e.complexity.TallyRound.Elected == nil {
+			break
+		}
+
+		return e.complexity.TallyRound.Elected(childComplexity), true
+
+	case "TallyRound.eliminated":
+		if e.complexity.TallyRound.Eliminated == nil {
+			break
+		}
+
+		return e.complexity.TallyRound.Eliminated(childComplexity), true
+
+	case "TallyRound.exhausted":
+		if e.complexity.TallyRound.Exhausted == nil {
+			break
+		}
+
+		return e.complexity.TallyRound.Exhausted(childComplexity), true
+
+	case "TallyRound.round":
+		if e.complexity.TallyRound.Round == nil {
+			break
+		}
+
+		return e.complexity.TallyRound.Round(childComplexity), true
+
+	case "TallyRound.transfers":
+		if e.complexity.TallyRound.Transfers == nil {
+			break
+		}
+
+		return e.complexity.TallyRound.Transfers(childComplexity), true
+
 	case "User.account":
 		if e.complexity.User.Account == nil {
 			break
@@ -411,6 +920,13 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.User.ID(childComplexity), true
 
+	case "Vote.allowRevote":
+		if e.complexity.Vote.AllowRevote == nil {
+			break
+		}
+
+		return e.complexity.Vote.AllowRevote(childComplexity), true
+
 	case "Vote.creator":
 		if e.complexity.Vote.Creator == nil {
 			break
@@ -432,6 +948,13 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Vote.EndTime(childComplexity), true
 
+	case "Vote.gracePeriod":
+		if e.complexity.Vote.GracePeriod == nil {
+			break
+		}
+
+		return e.complexity.Vote.GracePeriod(childComplexity), true
+
 	case "Vote.id":
 		if e.complexity.Vote.ID == nil {
 			break
@@ -439,6 +962,13 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Vote.ID(childComplexity), true
 
+	case "Vote.liveResults":
+		if e.complexity.Vote.LiveResults == nil {
+			break
+		}
+
+		return e.complexity.Vote.LiveResults(childComplexity), true
+
 	case "Vote.questions":
 		if e.complexity.Vote.Questions == nil {
 			break
@@ -446,6 +976,13 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Vote.Questions(childComplexity), true
 
+	case "Vote.results":
+		if e.complexity.Vote.Results == nil {
+			break
+		}
+
+		return e.complexity.Vote.Results(childComplexity), true
+
 	case "Vote.startTime":
 		if e.complexity.Vote.StartTime == nil {
 			break
@@ -460,6 +997,13 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Vote.Status(childComplexity), true
 
+	case "Vote.tieBreak":
+		if e.complexity.Vote.TieBreak == nil {
+			break
+		}
+
+		return e.complexity.Vote.TieBreak(childComplexity), true
+
 	case "Vote.title":
 		if e.complexity.Vote.Title == nil {
 			break
@@ -467,6 +1011,13 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.Vote.Title(childComplexity), true
 
+	case "Vote.transitions":
+		if e.complexity.Vote.Transitions == nil {
+			break
+		}
+
+		return e.complexity.Vote.Transitions(childComplexity), true
+
 	case "Vote.uuid":
 		if e.complexity.Vote.Uuid == nil {
 			break
@@ -509,6 +1060,83 @@ func (e *executableSchema) Complexity(ctx context.Context, typeName, field strin
 
 		return e.complexity.VoteEdge.Node(childComplexity), true
 
+	case "VoteResult.createdAt":
+		if e.complexity.VoteResult.CreatedAt == nil {
+			break
+		}
+
+		return e.complexity.VoteResult.CreatedAt(childComplexity), true
+
+	case "VoteResult.questions":
+		if e.complexity.VoteResult.Questions == nil {
+			break
+		}
+
+		return e.complexity.VoteResult.Questions(childComplexity), true
+
+	case "VoteResult.updatedAt":
+		if e.complexity.VoteResult.UpdatedAt == nil {
+			break
+		}
+
+		return e.complexity.VoteResult.UpdatedAt(childComplexity), true
+
+	case "VoteResult.voteId":
+		if e.complexity.VoteResult.VoteID == nil {
+			break
+		}
+
+		return e.complexity.VoteResult.VoteID(childComplexity), true
+
+	case "VoteResult.voters":
+		if e.complexity.VoteResult.Voters == nil {
+			break
+		}
+
+		return e.complexity.VoteResult.Voters(childComplexity), true
+
+	case "VoteTransition.actorId":
+		if e.complexity.VoteTransition.ActorID == nil {
+			break
+		}
+
+		return e.complexity.VoteTransition.ActorID(childComplexity), true
+
+	case "VoteTransition.createdAt":
+		if e.complexity.VoteTransition.CreatedAt == nil {
+			break
+		}
+
+		return e.complexity.VoteTransition.CreatedAt(childComplexity), true
+
+	case "VoteTransition.from":
+		if e.complexity.VoteTransition.FromStatus == nil {
+			break
+		}
+
+		return e.complexity.VoteTransition.FromStatus(childComplexity), true
+
+	case "VoteTransition.id":
+		if e.complexity.VoteTransition.ID == nil {
+			break
+		}
+
+		return e.complexity.VoteTransition.ID(childComplexity), true
+
+	case "VoteTransition.to":
+		if e.complexity.VoteTransition.ToStatus == nil {
+			break
+		}
+
+		return e.complexity.VoteTransition.ToStatus(childComplexity), true
+
+	case "VoteTransition.voteId":
+		if e.complexity.VoteTransition.VoteID == nil {
+			break
+		}
+
+		return e.complexity.VoteTransition.VoteID(childComplexity), true
+
 	}
 	return 0, false
 }
@@ -568,6 +1196,23 @@ func (e *executableSchema) Exec(ctx context.Context) graphql.ResponseHandler {
 			var buf bytes.Buffer
 			data.MarshalGQL(&buf)
 
+			return &graphql.Response{
+				Data: buf.Bytes(),
+			}
+		}
+	case ast.Subscription:
+		next := ec._Subscription(ctx, opCtx.Operation.SelectionSet)
+
+		var buf bytes.Buffer
+		return func(ctx context.Context) *graphql.Response {
+			buf.Reset()
+			data := next(ctx)
+
+			if data == nil {
+				return nil
+			}
+			data.MarshalGQL(&buf)
+
 			return &graphql.Response{
 				Data: buf.Bytes(),
 			}
@@ -623,8 +1268,8 @@ var sources = []*ast.Source{
 	{Name: "../candidate.graphqls", Input: `type Candidate {
   id: ID!
   questionId: UUID!
-  Name: String!
-  Result: String!
+  name: String!
+  result: String!
   createdAt: Time!
   updatedAt: Time!
 }`, BuiltIn: false},
@@ -632,6 +1277,42 @@ var sources = []*ast.Source{
 scalar UUID
 scalar Int64
 
+"""
+How a question is counted. Ranked methods expect ballots to carry a rank per candidate.
+"""
+enum VotingMethod {
+  plurality
+  approval
+  borda
+  score
+  irv
+  stv
+  condorcet
+}
+
+"""
+Lifecycle of a vote: draft -> scheduled -> open -> closed -> tallied -> published -> archived.
+"""
+enum VoteStatus {
+  draft
+  scheduled
+  open
+  closed
+  tallied
+  published
+  archived
+}
+
+"""
+How ties are broken during a count. History based rules fall back to candidate order.
+"""
+enum TieBreak {
+  backward
+  forward
+  candidate
+  seeded
+}
+
 """
 Pagination information for paginated results.
 """
@@ -648,6 +1329,43 @@ interface pageQuery {
   last: Int64
   before: String
 }`, BuiltIn: false},
+	{Name: "../live.graphqls", Input: `"""
+Running statistics of a vote, pushed whenever ballots arrive or the status changes.
+"""
+type LiveResult {
+  voteId: UUID!
+  status: VoteStatus!
+  """
+  Credentials that have not been revoked.
+  """
+  eligible: Int64!
+  voted: Int64!
+  turnout: Float!
+  """
+  Empty unless the vote enables liveResults. Encrypted votes never expose running counts.
+  """
+  questions: [LiveQuestion!]!
+  updatedAt: Time!
+}
+
+type LiveQuestion {
+  questionId: ID!
+  ballots: Int64!
+  candidates: [LiveCount!]!
+}
+
+"""
+First preferences for ranked methods, otherwise the number of ballots marking the candidate.
+"""
+type LiveCount {
+  candidateId: ID!
+  votes: Int64!
+}
+
+type Subscription {
+  liveResult(uuid: UUID!): LiveResult!
+}
+`, BuiltIn: false},
 	{Name: "../question.graphqls", Input: `directive @withCandidates(withCandidates: Boolean!) on FIELD_DEFINITION
 
 type Question {
@@ -655,9 +1373,15 @@ type Question {
   voteId: UUID!
   title: String!
   description: String!
+  method: VotingMethod!
+  seats: Int64!
+  maxScore: Int64!
+  minSelections: Int64!
+  maxSelections: Int64!
   createdAt: Time!
   updatedAt: Time!
   candidates: [Candidate!]!
+  result: QuestionResult
 }
 
 type QuestionConnection {
@@ -675,6 +1399,11 @@ input QuestionCreate {
   voteId: UUID!
   title: String!
   description: String!
+  method: VotingMethod
+  seats: Int64
+  maxScore: Int64
+  minSelections: Int64
+  maxSelections: Int64
 }
 
 input QuestionQuery {
@@ -693,6 +1422,82 @@ extend type Query {
 extend type Mutation {
   createQuestion(input: QuestionCreate!): Question!
 }`, BuiltIn: false},
+	{Name: "../result.graphqls", Input: `"""
+Tally result of a vote.
+"""
+type VoteResult {
+  voteId: UUID!
+  voters: Int64!
+  questions: [QuestionResult!]!
+  createdAt: Time!
+  updatedAt: Time!
+}
+
+type QuestionResult {
+  questionId: ID!
+  title: String!
+  method: VotingMethod!
+  seats: Int64!
+  quota: Float!
+  totalBallots: Int64!
+  validBallots: Int64!
+  blankBallots: Int64!
+  abstentions: Int64!
+  candidates: [CandidateResult!]!
+  winners: [ID!]!
+  rounds: [TallyRound!]!
+  pairwise: [PairwiseRow!]!
+  condorcetWinner: ID
+  ranking: [ID!]!
+}
+
+type CandidateResult {
+  candidateId: ID!
+  name: String!
+  votes: Int64!
+  percentage: Float!
+  average: Float!
+}
+
+"""
+Round-by-round report of a ranked count.
+"""
+type TallyRound {
+  round: Int64!
+  counts: [RoundCount!]!
+  exhausted: Float!
+  elected: [ID!]!
+  eliminated: [ID!]!
+  transfers: [RoundTransfer!]!
+}
+
+type RoundCount {
+  candidateId: ID!
+  votes: Float!
+}
+
+"""
+Votes moved away from an eliminated or elected candidate. A ` + "`" + `to` + "`" + ` of 0 means the ballots were exhausted.
+"""
+type RoundTransfer {
+  from: ID!
+  to: ID!
+  votes: Float!
+}
+
+"""
+Row of the pairwise preference matrix: how many ballots prefer ` + "`" + `candidateId` + "`" + ` over each other candidate.
+"""
+type PairwiseRow {
+  candidateId: ID!
+  against: [PairwiseCount!]!
+}
+
+type PairwiseCount {
+  candidateId: ID!
+  votes: Int64!
+}
+`, BuiltIn: false},
 	{Name: "../user.graphqls", Input: `type User {
   id: ID!
   account: String!
@@ -720,8 +1525,35 @@ type Mutation {
   startTime: Time!
   endTime: Time!
   creator: User!
-  status: Int64!
+  status: VoteStatus!
+  tieBreak: TieBreak!
+  """
+  Seconds after endTime during which voters who logged in before the close may still cast their ballot.
+  """
+  gracePeriod: Int64!
+  """
+  Voters may cast a new ballot until the vote closes; only the last ballot of each credential is counted.
+  """
+  allowRevote: Boolean!
+  """
+  Running per-candidate counts are streamed while the vote is open.
+  """
+  liveResults: Boolean!
   questions: [Question!]!
+  results: VoteResult
+  transitions: [VoteTransition!]!
+}
+
+"""
+A recorded status change. A null actorId means the change was made by the system.
+"""
+type VoteTransition {
+  id: ID!
+  voteId: UUID!
+  from: VoteStatus!
+  to: VoteStatus!
+  actorId: ID
+  createdAt: Time!
 }
 
 type VoteConnection {
@@ -744,6 +1576,10 @@ input VoteCreate {
   description: String
   startTime: Time!
   endTime: Time!
+  tieBreak: TieBreak
+  gracePeriod: Int64
+  allowRevote: Boolean
+  liveResults: Boolean
 }
 
 input VoteUpdate {
@@ -751,6 +1587,13 @@ input VoteUpdate {
   description: String
   startTime: Time
   endTime: Time
+  tieBreak: TieBreak
+  gracePeriod: Int64
+  """
+  Can only be changed while the vote is a draft.
+  """
+  allowRevote: Boolean
+  liveResults: Boolean
   UpdatedAt: Time
 }
 
@@ -774,6 +1617,7 @@ extend type Mutation {
   createVote(input: VoteCreate!): Vote!
   updateVote(uuid: UUID!, input: VoteUpdate!): Vote!
   deleteVote(uuids: [UUID!]!): [Vote!]!
+  transitionVote(uuid: UUID!, status: VoteStatus!): Vote!
 }`, BuiltIn: false},
 }
 var parsedSchema = gqlparser.MustLoadSchema(sources...)
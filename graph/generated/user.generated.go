@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"sync"
 	"sync/atomic"
+	"vote/app/enum"
 	"vote/app/model"
 
 	"github.com/99designs/gqlgen/graphql"
@@ -24,6 +25,7 @@ type MutationResolver interface {
 	CreateVote(ctx context.Context, input model.VoteCreate) (*model.Vote, error)
 	UpdateVote(ctx context.Context, uuid uuid.UUID, input model.VoteUpdate) (*model.Vote, error)
 	DeleteVote(ctx context.Context, uuids []uuid.UUID) ([]*model.Vote, error)
+	TransitionVote(ctx context.Context, uuid uuid.UUID, status enum.VoteStatus) (*model.Vote, error)
 }
 type QueryResolver interface {
 	Users(ctx context.Context) ([]*model.User, error)
@@ -79,6 +81,22 @@ func (ec *executionContext) field_Mutation_deleteVote_args(ctx context.Context,
 	return args, nil
 }
 
+func (ec *executionContext) field_Mutation_transitionVote_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
+	var err error
+	args := map[string]any{}
+	arg0, err := graphql.ProcessArgField(ctx, rawArgs, "uuid", ec.unmarshalNUUID2githubᚗcomᚋgoogleᚋuuidᚐUUID)
+	if err != nil {
+		return nil, err
+	}
+	args["uuid"] = arg0
+	arg1, err := graphql.ProcessArgField(ctx, rawArgs, "status", ec.unmarshalNVoteStatus2voteᚋappᚋenumᚐVoteStatus)
+	if err != nil {
+		return nil, err
+	}
+	args["status"] = arg1
+	return args, nil
+}
+
 func (ec *executionContext) field_Mutation_updateVote_args(ctx context.Context, rawArgs map[string]any) (map[string]any, error) {
 	var err error
 	args := map[string]any{}
@@ -228,12 +246,24 @@ func (ec *executionContext) fieldContext_Mutation_createQuestion(ctx context.Con
 				return ec.fieldContext_Question_title(ctx, field)
 			case "description":
 				return ec.fieldContext_Question_description(ctx, field)
+			case "method":
+				return ec.fieldContext_Question_method(ctx, field)
+			case "seats":
+				return ec.fieldContext_Question_seats(ctx, field)
+			case "maxScore":
+				return ec.fieldContext_Question_maxScore(ctx, field)
+			case "minSelections":
+				return ec.fieldContext_Question_minSelections(ctx, field)
+			case "maxSelections":
+				return ec.fieldContext_Question_maxSelections(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_Question_createdAt(ctx, field)
 			case "updatedAt":
 				return ec.fieldContext_Question_updatedAt(ctx, field)
 			case "candidates":
 				return ec.fieldContext_Question_candidates(ctx, field)
+			case "result":
+				return ec.fieldContext_Question_result(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type Question", field.Name)
 		},
@@ -293,8 +323,20 @@ func (ec *executionContext) fieldContext_Mutation_createVote(ctx context.Context
 				return ec.fieldContext_Vote_creator(ctx, field)
 			case "status":
 				return ec.fieldContext_Vote_status(ctx, field)
+			case "tieBreak":
+				return ec.fieldContext_Vote_tieBreak(ctx, field)
+			case "gracePeriod":
+				return ec.fieldContext_Vote_gracePeriod(ctx, field)
+			case "allowRevote":
+				return ec.fieldContext_Vote_allowRevote(ctx, field)
+			case "liveResults":
+				return ec.fieldContext_Vote_liveResults(ctx, field)
 			case "questions":
 				return ec.fieldContext_Vote_questions(ctx, field)
+			case "results":
+				return ec.fieldContext_Vote_results(ctx, field)
+			case "transitions":
+				return ec.fieldContext_Vote_transitions(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type Vote", field.Name)
 		},
@@ -354,8 +396,20 @@ func (ec *executionContext) fieldContext_Mutation_updateVote(ctx context.Context
 				return ec.fieldContext_Vote_creator(ctx, field)
 			case "status":
 				return ec.fieldContext_Vote_status(ctx, field)
+			case "tieBreak":
+				return ec.fieldContext_Vote_tieBreak(ctx, field)
+			case "gracePeriod":
+				return ec.fieldContext_Vote_gracePeriod(ctx, field)
+			case "allowRevote":
+				return ec.fieldContext_Vote_allowRevote(ctx, field)
+			case "liveResults":
+				return ec.fieldContext_Vote_liveResults(ctx, field)
 			case "questions":
 				return ec.fieldContext_Vote_questions(ctx, field)
+			case "results":
+				return ec.fieldContext_Vote_results(ctx, field)
+			case "transitions":
+				return ec.fieldContext_Vote_transitions(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type Vote", field.Name)
 		},
@@ -415,8 +469,20 @@ func (ec *executionContext) fieldContext_Mutation_deleteVote(ctx context.Context
 				return ec.fieldContext_Vote_creator(ctx, field)
 			case "status":
 				return ec.fieldContext_Vote_status(ctx, field)
+			case "tieBreak":
+				return ec.fieldContext_Vote_tieBreak(ctx, field)
+			case "gracePeriod":
+				return ec.fieldContext_Vote_gracePeriod(ctx, field)
+			case "allowRevote":
+				return ec.fieldContext_Vote_allowRevote(ctx, field)
+			case "liveResults":
+				return ec.fieldContext_Vote_liveResults(ctx, field)
 			case "questions":
 				return ec.fieldContext_Vote_questions(ctx, field)
+			case "results":
+				return ec.fieldContext_Vote_results(ctx, field)
+			case "transitions":
+				return ec.fieldContext_Vote_transitions(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type Vote", field.Name)
 		},
@@ -435,6 +501,79 @@ func (ec *executionContext) fieldContext_Mutation_deleteVote(ctx context.Context
 	return fc, nil
 }
 
+func (ec *executionContext) _Mutation_transitionVote(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Mutation_transitionVote,
+		func(ctx context.Context) (any, error) {
+			fc := graphql.GetFieldContext(ctx)
+			return ec.resolvers.Mutation().TransitionVote(ctx, fc.Args["uuid"].(uuid.UUID), fc.Args["status"].(enum.VoteStatus))
+		},
+		nil,
+		ec.marshalNVote2ᚖvoteᚋappᚋmodelᚐVote,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Mutation_transitionVote(ctx context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Mutation",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_Vote_id(ctx, field)
+			case "uuid":
+				return ec.fieldContext_Vote_uuid(ctx, field)
+			case "title":
+				return ec.fieldContext_Vote_title(ctx, field)
+			case "description":
+				return ec.fieldContext_Vote_description(ctx, field)
+			case "startTime":
+				return ec.fieldContext_Vote_startTime(ctx, field)
+			case "endTime":
+				return ec.fieldContext_Vote_endTime(ctx, field)
+			case "creator":
+				return ec.fieldContext_Vote_creator(ctx, field)
+			case "status":
+				return ec.fieldContext_Vote_status(ctx, field)
+			case "tieBreak":
+				return ec.fieldContext_Vote_tieBreak(ctx, field)
+			case "gracePeriod":
+				return ec.fieldContext_Vote_gracePeriod(ctx, field)
+			case "allowRevote":
+				return ec.fieldContext_Vote_allowRevote(ctx, field)
+			case "liveResults":
+				return ec.fieldContext_Vote_liveResults(ctx, field)
+			case "questions":
+				return ec.fieldContext_Vote_questions(ctx, field)
+			case "results":
+				return ec.fieldContext_Vote_results(ctx, field)
+			case "transitions":
+				return ec.fieldContext_Vote_transitions(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type Vote", field.Name)
+		},
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = ec.Recover(ctx, r)
+			ec.Error(ctx, err)
+		}
+	}()
+	ctx = graphql.WithFieldContext(ctx, fc)
+	if fc.Args, err = ec.field_Mutation_transitionVote_args(ctx, field.ArgumentMap(ec.Variables)); err != nil {
+		ec.Error(ctx, err)
+		return fc, err
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Query_users(ctx context.Context, field graphql.CollectedField) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -872,6 +1011,13 @@ func (ec *executionContext) _Mutation(ctx context.Context, sel ast.SelectionSet)
 			if out.Values[i] == graphql.Null {
 				out.Invalids++
 			}
+		case "transitionVote":
+			out.Values[i] = ec.OperationContext.RootResolverMiddleware(innerCtx, func(ctx context.Context) (res graphql.Marshaler) {
+				return ec._Mutation_transitionVote(ctx, field)
+			})
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
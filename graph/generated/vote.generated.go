@@ -19,6 +19,9 @@ import (
 
 type VoteResolver interface {
 	Creator(ctx context.Context, obj *model.Vote) (*model.User, error)
+
+	Results(ctx context.Context, obj *model.Vote) (*model.VoteResult, error)
+	Transitions(ctx context.Context, obj *model.Vote) ([]*model.VoteTransition, error)
 }
 
 // endregion ************************** generated!.gotpl **************************
@@ -254,13 +257,71 @@ func (ec *executionContext) _Vote_status(ctx context.Context, field graphql.Coll
 			return obj.Status, nil
 		},
 		nil,
-		ec.marshalNInt642int,
+		ec.marshalNVoteStatus2voteᚋappᚋenumᚐVoteStatus,
 		true,
 		true,
 	)
 }
 
 func (ec *executionContext) fieldContext_Vote_status(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Vote",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type VoteStatus does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Vote_tieBreak(ctx context.Context, field graphql.CollectedField, obj *model.Vote) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Vote_tieBreak,
+		func(ctx context.Context) (any, error) {
+			return obj.TieBreak, nil
+		},
+		nil,
+		ec.marshalNTieBreak2voteᚋappᚋenumᚐTieBreak,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Vote_tieBreak(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Vote",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type TieBreak does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Vote_gracePeriod(ctx context.Context, field graphql.CollectedField, obj *model.Vote) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Vote_gracePeriod,
+		func(ctx context.Context) (any, error) {
+			return obj.GracePeriod, nil
+		},
+		nil,
+		ec.marshalNInt642int,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Vote_gracePeriod(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
 	fc = &graphql.FieldContext{
 		Object:     "Vote",
 		Field:      field,
@@ -273,6 +334,64 @@ func (ec *executionContext) fieldContext_Vote_status(_ context.Context, field gr
 	return fc, nil
 }
 
+func (ec *executionContext) _Vote_allowRevote(ctx context.Context, field graphql.CollectedField, obj *model.Vote) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Vote_allowRevote,
+		func(ctx context.Context) (any, error) {
+			return obj.AllowRevote, nil
+		},
+		nil,
+		ec.marshalNBoolean2bool,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Vote_allowRevote(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Vote",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Vote_liveResults(ctx context.Context, field graphql.CollectedField, obj *model.Vote) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Vote_liveResults,
+		func(ctx context.Context) (any, error) {
+			return obj.LiveResults, nil
+		},
+		nil,
+		ec.marshalNBoolean2bool,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Vote_liveResults(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Vote",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Boolean does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _Vote_questions(ctx context.Context, field graphql.CollectedField, obj *model.Vote) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -305,12 +424,24 @@ func (ec *executionContext) fieldContext_Vote_questions(_ context.Context, field
 				return ec.fieldContext_Question_title(ctx, field)
 			case "description":
 				return ec.fieldContext_Question_description(ctx, field)
+			case "method":
+				return ec.fieldContext_Question_method(ctx, field)
+			case "seats":
+				return ec.fieldContext_Question_seats(ctx, field)
+			case "maxScore":
+				return ec.fieldContext_Question_maxScore(ctx, field)
+			case "minSelections":
+				return ec.fieldContext_Question_minSelections(ctx, field)
+			case "maxSelections":
+				return ec.fieldContext_Question_maxSelections(ctx, field)
 			case "createdAt":
 				return ec.fieldContext_Question_createdAt(ctx, field)
 			case "updatedAt":
 				return ec.fieldContext_Question_updatedAt(ctx, field)
 			case "candidates":
 				return ec.fieldContext_Question_candidates(ctx, field)
+			case "result":
+				return ec.fieldContext_Question_result(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type Question", field.Name)
 		},
@@ -318,6 +449,90 @@ func (ec *executionContext) fieldContext_Vote_questions(_ context.Context, field
 	return fc, nil
 }
 
+func (ec *executionContext) _Vote_results(ctx context.Context, field graphql.CollectedField, obj *model.Vote) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Vote_results,
+		func(ctx context.Context) (any, error) {
+			return ec.resolvers.Vote().Results(ctx, obj)
+		},
+		nil,
+		ec.marshalOVoteResult2ᚖvoteᚋappᚋmodelᚐVoteResult,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_Vote_results(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Vote",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "voteId":
+				return ec.fieldContext_VoteResult_voteId(ctx, field)
+			case "voters":
+				return ec.fieldContext_VoteResult_voters(ctx, field)
+			case "questions":
+				return ec.fieldContext_VoteResult_questions(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_VoteResult_createdAt(ctx, field)
+			case "updatedAt":
+				return ec.fieldContext_VoteResult_updatedAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type VoteResult", field.Name)
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _Vote_transitions(ctx context.Context, field graphql.CollectedField, obj *model.Vote) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_Vote_transitions,
+		func(ctx context.Context) (any, error) {
+			return ec.resolvers.Vote().Transitions(ctx, obj)
+		},
+		nil,
+		ec.marshalNVoteTransition2ᚕᚖvoteᚋappᚋmodelᚐVoteTransitionᚄ,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_Vote_transitions(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "Vote",
+		Field:      field,
+		IsMethod:   true,
+		IsResolver: true,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			switch field.Name {
+			case "id":
+				return ec.fieldContext_VoteTransition_id(ctx, field)
+			case "voteId":
+				return ec.fieldContext_VoteTransition_voteId(ctx, field)
+			case "from":
+				return ec.fieldContext_VoteTransition_from(ctx, field)
+			case "to":
+				return ec.fieldContext_VoteTransition_to(ctx, field)
+			case "actorId":
+				return ec.fieldContext_VoteTransition_actorId(ctx, field)
+			case "createdAt":
+				return ec.fieldContext_VoteTransition_createdAt(ctx, field)
+			}
+			return nil, fmt.Errorf("no field named %q was found under type VoteTransition", field.Name)
+		},
+	}
+	return fc, nil
+}
+
 func (ec *executionContext) _VoteConnection_edges(ctx context.Context, field graphql.CollectedField, obj *model.VoteConnection) (ret graphql.Marshaler) {
 	return graphql.ResolveField(
 		ctx,
@@ -461,8 +676,20 @@ func (ec *executionContext) fieldContext_VoteEdge_node(_ context.Context, field
 				return ec.fieldContext_Vote_creator(ctx, field)
 			case "status":
 				return ec.fieldContext_Vote_status(ctx, field)
+			case "tieBreak":
+				return ec.fieldContext_Vote_tieBreak(ctx, field)
+			case "gracePeriod":
+				return ec.fieldContext_Vote_gracePeriod(ctx, field)
+			case "allowRevote":
+				return ec.fieldContext_Vote_allowRevote(ctx, field)
+			case "liveResults":
+				return ec.fieldContext_Vote_liveResults(ctx, field)
 			case "questions":
 				return ec.fieldContext_Vote_questions(ctx, field)
+			case "results":
+				return ec.fieldContext_Vote_results(ctx, field)
+			case "transitions":
+				return ec.fieldContext_Vote_transitions(ctx, field)
 			}
 			return nil, fmt.Errorf("no field named %q was found under type Vote", field.Name)
 		},
@@ -499,6 +726,180 @@ func (ec *executionContext) fieldContext_VoteEdge_cursor(_ context.Context, fiel
 	return fc, nil
 }
 
+func (ec *executionContext) _VoteTransition_id(ctx context.Context, field graphql.CollectedField, obj *model.VoteTransition) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_VoteTransition_id,
+		func(ctx context.Context) (any, error) {
+			return obj.ID, nil
+		},
+		nil,
+		ec.marshalNID2uint64,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_VoteTransition_id(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "VoteTransition",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type ID does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _VoteTransition_voteId(ctx context.Context, field graphql.CollectedField, obj *model.VoteTransition) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_VoteTransition_voteId,
+		func(ctx context.Context) (any, error) {
+			return obj.VoteID, nil
+		},
+		nil,
+		ec.marshalNUUID2githubᚗcomᚋgoogleᚋuuidᚐUUID,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_VoteTransition_voteId(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "VoteTransition",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type UUID does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _VoteTransition_from(ctx context.Context, field graphql.CollectedField, obj *model.VoteTransition) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_VoteTransition_from,
+		func(ctx context.Context) (any, error) {
+			return obj.FromStatus, nil
+		},
+		nil,
+		ec.marshalNVoteStatus2voteᚋappᚋenumᚐVoteStatus,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_VoteTransition_from(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "VoteTransition",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type VoteStatus does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _VoteTransition_to(ctx context.Context, field graphql.CollectedField, obj *model.VoteTransition) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_VoteTransition_to,
+		func(ctx context.Context) (any, error) {
+			return obj.ToStatus, nil
+		},
+		nil,
+		ec.marshalNVoteStatus2voteᚋappᚋenumᚐVoteStatus,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_VoteTransition_to(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "VoteTransition",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type VoteStatus does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _VoteTransition_actorId(ctx context.Context, field graphql.CollectedField, obj *model.VoteTransition) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_VoteTransition_actorId,
+		func(ctx context.Context) (any, error) {
+			return obj.ActorID, nil
+		},
+		nil,
+		ec.marshalOID2ᚖuint64,
+		true,
+		false,
+	)
+}
+
+func (ec *executionContext) fieldContext_VoteTransition_actorId(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "VoteTransition",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type ID does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
+func (ec *executionContext) _VoteTransition_createdAt(ctx context.Context, field graphql.CollectedField, obj *model.VoteTransition) (ret graphql.Marshaler) {
+	return graphql.ResolveField(
+		ctx,
+		ec.OperationContext,
+		field,
+		ec.fieldContext_VoteTransition_createdAt,
+		func(ctx context.Context) (any, error) {
+			return obj.CreatedAt, nil
+		},
+		nil,
+		ec.marshalNTime2timeᚐTime,
+		true,
+		true,
+	)
+}
+
+func (ec *executionContext) fieldContext_VoteTransition_createdAt(_ context.Context, field graphql.CollectedField) (fc *graphql.FieldContext, err error) {
+	fc = &graphql.FieldContext{
+		Object:     "VoteTransition",
+		Field:      field,
+		IsMethod:   false,
+		IsResolver: false,
+		Child: func(ctx context.Context, field graphql.CollectedField) (*graphql.FieldContext, error) {
+			return nil, errors.New("field of type Time does not have child fields")
+		},
+	}
+	return fc, nil
+}
+
 // endregion **************************** field.gotpl *****************************
 
 // region    **************************** input.gotpl *****************************
@@ -510,7 +911,7 @@ func (ec *executionContext) unmarshalInputVoteCreate(ctx context.Context, obj an
 		asMap[k] = v
 	}
 
-	fieldsInOrder := [...]string{"title", "description", "startTime", "endTime"}
+	fieldsInOrder := [...]string{"title", "description", "startTime", "endTime", "tieBreak", "gracePeriod", "allowRevote", "liveResults"}
 	for _, k := range fieldsInOrder {
 		v, ok := asMap[k]
 		if !ok {
@@ -545,6 +946,34 @@ func (ec *executionContext) unmarshalInputVoteCreate(ctx context.Context, obj an
 				return it, err
 			}
 			it.EndTime = data
+		case "tieBreak":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("tieBreak"))
+			data, err := ec.unmarshalOTieBreak2voteᚋappᚋenumᚐTieBreak(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.TieBreak = data
+		case "gracePeriod":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("gracePeriod"))
+			data, err := ec.unmarshalOInt642int(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.GracePeriod = data
+		case "allowRevote":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("allowRevote"))
+			data, err := ec.unmarshalOBoolean2bool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.AllowRevote = data
+		case "liveResults":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("liveResults"))
+			data, err := ec.unmarshalOBoolean2bool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.LiveResults = data
 		}
 	}
 
@@ -641,7 +1070,7 @@ func (ec *executionContext) unmarshalInputVoteUpdate(ctx context.Context, obj an
 		asMap[k] = v
 	}
 
-	fieldsInOrder := [...]string{"title", "description", "startTime", "endTime", "UpdatedAt"}
+	fieldsInOrder := [...]string{"title", "description", "startTime", "endTime", "tieBreak", "gracePeriod", "allowRevote", "liveResults", "UpdatedAt"}
 	for _, k := range fieldsInOrder {
 		v, ok := asMap[k]
 		if !ok {
@@ -676,6 +1105,34 @@ func (ec *executionContext) unmarshalInputVoteUpdate(ctx context.Context, obj an
 				return it, err
 			}
 			it.EndTime = data
+		case "tieBreak":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("tieBreak"))
+			data, err := ec.unmarshalOTieBreak2voteᚋappᚋenumᚐTieBreak(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.TieBreak = data
+		case "gracePeriod":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("gracePeriod"))
+			data, err := ec.unmarshalOInt642int(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.GracePeriod = data
+		case "allowRevote":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("allowRevote"))
+			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.AllowRevote = data
+		case "liveResults":
+			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("liveResults"))
+			data, err := ec.unmarshalOBoolean2ᚖbool(ctx, v)
+			if err != nil {
+				return it, err
+			}
+			it.LiveResults = data
 		case "UpdatedAt":
 			ctx := graphql.WithPathContext(ctx, graphql.NewPathWithField("UpdatedAt"))
 			data, err := ec.unmarshalOTime2timeᚐTime(ctx, v)
@@ -779,11 +1236,100 @@ func (ec *executionContext) _Vote(ctx context.Context, sel ast.SelectionSet, obj
 			if out.Values[i] == graphql.Null {
 				atomic.AddUint32(&out.Invalids, 1)
 			}
+		case "tieBreak":
+			out.Values[i] = ec._Vote_tieBreak(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&out.Invalids, 1)
+			}
+		case "gracePeriod":
+			out.Values[i] = ec._Vote_gracePeriod(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&out.Invalids, 1)
+			}
+		case "allowRevote":
+			out.Values[i] = ec._Vote_allowRevote(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&out.Invalids, 1)
+			}
+		case "liveResults":
+			out.Values[i] = ec._Vote_liveResults(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				atomic.AddUint32(&out.Invalids, 1)
+			}
 		case "questions":
 			out.Values[i] = ec._Vote_questions(ctx, field, obj)
 			if out.Values[i] == graphql.Null {
 				atomic.AddUint32(&out.Invalids, 1)
 			}
+		case "results":
+			field := field
+
+			innerFunc := func(ctx context.Context, _ *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Vote_results(ctx, field, obj)
+				return res
+			}
+
+			if field.Deferrable != nil {
+				dfs, ok := deferred[field.Deferrable.Label]
+				di := 0
+				if ok {
+					dfs.AddField(field)
+					di = len(dfs.Values) - 1
+				} else {
+					dfs = graphql.NewFieldSet([]graphql.CollectedField{field})
+					deferred[field.Deferrable.Label] = dfs
+				}
+				dfs.Concurrently(di, func(ctx context.Context) graphql.Marshaler {
+					return innerFunc(ctx, dfs)
+				})
+
+				// don't run the out.Concurrently() call below
+				out.Values[i] = graphql.Null
+				continue
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
+		case "transitions":
+			field := field
+
+			innerFunc := func(ctx context.Context, fs *graphql.FieldSet) (res graphql.Marshaler) {
+				defer func() {
+					if r := recover(); r != nil {
+						ec.Error(ctx, ec.Recover(ctx, r))
+					}
+				}()
+				res = ec._Vote_transitions(ctx, field, obj)
+				if res == graphql.Null {
+					atomic.AddUint32(&fs.Invalids, 1)
+				}
+				return res
+			}
+
+			if field.Deferrable != nil {
+				dfs, ok := deferred[field.Deferrable.Label]
+				di := 0
+				if ok {
+					dfs.AddField(field)
+					di = len(dfs.Values) - 1
+				} else {
+					dfs = graphql.NewFieldSet([]graphql.CollectedField{field})
+					deferred[field.Deferrable.Label] = dfs
+				}
+				dfs.Concurrently(di, func(ctx context.Context) graphql.Marshaler {
+					return innerFunc(ctx, dfs)
+				})
+
+				// don't run the out.Concurrently() call below
+				out.Values[i] = graphql.Null
+				continue
+			}
+
+			out.Concurrently(i, func(ctx context.Context) graphql.Marshaler { return innerFunc(ctx, out) })
 		default:
 			panic("unknown field " + strconv.Quote(field.Name))
 		}
@@ -900,6 +1446,67 @@ func (ec *executionContext) _VoteEdge(ctx context.Context, sel ast.SelectionSet,
 	return out
 }
 
+var voteTransitionImplementors = []string{"VoteTransition"}
+
+func (ec *executionContext) _VoteTransition(ctx context.Context, sel ast.SelectionSet, obj *model.VoteTransition) graphql.Marshaler {
+	fields := graphql.CollectFields(ec.OperationContext, sel, voteTransitionImplementors)
+
+	out := graphql.NewFieldSet(fields)
+	deferred := make(map[string]*graphql.FieldSet)
+	for i, field := range fields {
+		switch field.Name {
+		case "__typename":
+			out.Values[i] = graphql.MarshalString("VoteTransition")
+		case "id":
+			out.Values[i] = ec._VoteTransition_id(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "voteId":
+			out.Values[i] = ec._VoteTransition_voteId(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "from":
+			out.Values[i] = ec._VoteTransition_from(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "to":
+			out.Values[i] = ec._VoteTransition_to(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		case "actorId":
+			out.Values[i] = ec._VoteTransition_actorId(ctx, field, obj)
+		case "createdAt":
+			out.Values[i] = ec._VoteTransition_createdAt(ctx, field, obj)
+			if out.Values[i] == graphql.Null {
+				out.Invalids++
+			}
+		default:
+			panic("unknown field " + strconv.Quote(field.Name))
+		}
+	}
+	out.Dispatch(ctx)
+	if out.Invalids > 0 {
+		return graphql.Null
+	}
+
+	atomic.AddInt32(&ec.deferred, int32(len(deferred)))
+
+	for label, dfs := range deferred {
+		ec.processDeferredGroup(graphql.DeferredGroup{
+			Label:    label,
+			Path:     graphql.GetPath(ctx),
+			FieldSet: dfs,
+			Context:  ctx,
+		})
+	}
+
+	return out
+}
+
 // endregion **************************** object.gotpl ****************************
 
 // region    ***************************** type.gotpl *****************************
@@ -1069,6 +1676,60 @@ func (ec *executionContext) marshalNVoteEdge2ᚕvoteᚋappᚋmodelᚐVoteEdgeᚄ
 	return ret
 }
 
+func (ec *executionContext) marshalNVoteTransition2ᚕᚖvoteᚋappᚋmodelᚐVoteTransitionᚄ(ctx context.Context, sel ast.SelectionSet, v []*model.VoteTransition) graphql.Marshaler {
+	ret := make(graphql.Array, len(v))
+	var wg sync.WaitGroup
+	isLen1 := len(v) == 1
+	if !isLen1 {
+		wg.Add(len(v))
+	}
+	for i := range v {
+		i := i
+		fc := &graphql.FieldContext{
+			Index:  &i,
+			Result: &v[i],
+		}
+		ctx := graphql.WithFieldContext(ctx, fc)
+		f := func(i int) {
+			defer func() {
+				if r := recover(); r != nil {
+					ec.Error(ctx, ec.Recover(ctx, r))
+					ret = nil
+				}
+			}()
+			if !isLen1 {
+				defer wg.Done()
+			}
+			ret[i] = ec.marshalNVoteTransition2ᚖvoteᚋappᚋmodelᚐVoteTransition(ctx, sel, v[i])
+		}
+		if isLen1 {
+			f(i)
+		} else {
+			go f(i)
+		}
+
+	}
+	wg.Wait()
+
+	for _, e := range ret {
+		if e == graphql.Null {
+			return graphql.Null
+		}
+	}
+
+	return ret
+}
+
+func (ec *executionContext) marshalNVoteTransition2ᚖvoteᚋappᚋmodelᚐVoteTransition(ctx context.Context, sel ast.SelectionSet, v *model.VoteTransition) graphql.Marshaler {
+	if v == nil {
+		if !graphql.HasFieldError(ctx, graphql.GetFieldContext(ctx)) {
+			ec.Errorf(ctx, "the requested element is null which the schema does not allow")
+		}
+		return graphql.Null
+	}
+	return ec._VoteTransition(ctx, sel, v)
+}
+
 func (ec *executionContext) unmarshalNVoteUpdate2voteᚋappᚋmodelᚐVoteUpdate(ctx context.Context, v any) (model.VoteUpdate, error) {
 	res, err := ec.unmarshalInputVoteUpdate(ctx, v)
 	return res, graphql.ErrorOnPath(ctx, err)
@@ -15,3 +15,6 @@ type Mutation struct {
 
 type Query struct {
 }
+
+type Subscription struct {
+}
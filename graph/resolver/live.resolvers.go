@@ -0,0 +1,43 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.81
+
+import (
+	"context"
+	"vote/app/model"
+	"vote/app/service"
+	graph "vote/graph/generated"
+
+	"github.com/google/uuid"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// LiveResult is the resolver for the liveResult field.
+func (r *subscriptionResolver) LiveResult(ctx context.Context, uuid uuid.UUID) (<-chan *model.LiveResult, error) {
+	userId, isAdmin, err := service.NewGraphqlService().GetUserInfoFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vote, err := service.NewVoteService().GetVote(uuid)
+	if err != nil {
+		return nil, gqlerror.Errorf("vote not found")
+	}
+	if !isAdmin && vote.UserID != userId {
+		return nil, gqlerror.Errorf("permission denied")
+	}
+
+	updates, err := service.SubscribeLiveResult(ctx, vote.Uuid)
+	if err != nil {
+		return nil, gqlerror.Errorf("failed to subscribe live results: %v", err)
+	}
+
+	return updates, nil
+}
+
+// Subscription returns graph.SubscriptionResolver implementation.
+func (r *Resolver) Subscription() graph.SubscriptionResolver { return &subscriptionResolver{r} }
+
+type subscriptionResolver struct{ *Resolver }
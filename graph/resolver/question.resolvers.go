@@ -6,10 +6,13 @@ package graph
 
 import (
 	"context"
+	"errors"
 	"vote/app/model"
 	"vote/app/service"
+	graph "vote/graph/generated"
 
 	"github.com/vektah/gqlparser/v2/gqlerror"
+	"gorm.io/gorm"
 )
 
 // CreateQuestion is the resolver for the createQuestion field.
@@ -37,3 +40,21 @@ func (r *queryResolver) Questions(ctx context.Context, input *model.QuestionQuer
 
 	return questionConnections, nil
 }
+
+// Result is the resolver for the result field.
+func (r *questionResolver) Result(ctx context.Context, obj *model.Question) (*model.QuestionResult, error) {
+	result, err := service.NewTallyService().GetQuestionResult(*obj)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, gqlerror.Errorf("failed to get question result: %v", err)
+	}
+
+	return result, nil
+}
+
+// Question returns graph.QuestionResolver implementation.
+func (r *Resolver) Question() graph.QuestionResolver { return &questionResolver{r} }
+
+type questionResolver struct{ *Resolver }
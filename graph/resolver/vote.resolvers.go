@@ -6,12 +6,15 @@ package graph
 
 import (
 	"context"
+	"errors"
+	"vote/app/enum"
 	"vote/app/model"
 	"vote/app/service"
 	graph "vote/graph/generated"
 
 	"github.com/google/uuid"
 	"github.com/vektah/gqlparser/v2/gqlerror"
+	"gorm.io/gorm"
 )
 
 // CreateVote is the resolver for the createVote field.
@@ -56,6 +59,29 @@ func (r *mutationResolver) DeleteVote(ctx context.Context, uuids []uuid.UUID) ([
 	return votes, nil
 }
 
+// TransitionVote is the resolver for the transitionVote field.
+func (r *mutationResolver) TransitionVote(ctx context.Context, uuid uuid.UUID, status enum.VoteStatus) (*model.Vote, error) {
+	userId, isAdmin, err := service.NewGraphqlService().GetUserInfoFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vote, err := service.NewVoteService().GetVote(uuid)
+	if err != nil {
+		return nil, gqlerror.Errorf("vote not found")
+	}
+	if !isAdmin && vote.UserID != userId {
+		return nil, gqlerror.Errorf("permission denied")
+	}
+
+	vote, err = service.NewVoteService().TransitionVote(uuid, status, &userId)
+	if err != nil {
+		return nil, gqlerror.Errorf("failed to change vote status: %v", err)
+	}
+
+	return vote, nil
+}
+
 // Votes is the resolver for the votes field.
 func (r *queryResolver) Votes(ctx context.Context, input *model.VoteQuery, withQuestions bool) ([]*model.VoteConnection, error) {
 	userId, isAdmin, err := service.NewGraphqlService().GetUserInfoFromContext(ctx)
@@ -75,6 +101,34 @@ func (r *voteResolver) Creator(ctx context.Context, obj *model.Vote) (*model.Use
 	return user, err
 }
 
+// Results is the resolver for the results field.
+func (r *voteResolver) Results(ctx context.Context, obj *model.Vote) (*model.VoteResult, error) {
+	result, err := service.NewTallyService().GetVoteResult(obj.Uuid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, gqlerror.Errorf("failed to get vote result: %v", err)
+	}
+
+	return result, nil
+}
+
+// Transitions is the resolver for the transitions field.
+func (r *voteResolver) Transitions(ctx context.Context, obj *model.Vote) ([]*model.VoteTransition, error) {
+	transitions, err := service.NewVoteService().GetVoteTransitions(obj.Uuid)
+	if err != nil {
+		return nil, gqlerror.Errorf("failed to get vote transitions: %v", err)
+	}
+
+	result := make([]*model.VoteTransition, 0, len(transitions))
+	for i := range transitions {
+		result = append(result, &transitions[i])
+	}
+
+	return result, nil
+}
+
 // Vote returns graph.VoteResolver implementation.
 func (r *Resolver) Vote() graph.VoteResolver { return &voteResolver{r} }
 
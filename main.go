@@ -1,11 +1,13 @@
 package main
 
 import (
+	"context"
 	"os"
 
 	"vote/app/config"
 	"vote/app/database"
 	"vote/app/middleware"
+	"vote/app/service"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -31,6 +33,18 @@ func main() {
 
 	port := os.Getenv("PORT")
 	server := SetRouter()
+
+	// 背景排程：自動開放、結束投票並開票
+	scheduler := service.NewVoteScheduler()
+	scheduler.OnTransition(service.NotifyVoteOwner)
+	scheduler.Start(context.Background())
+
+	// 即時統計：經由 Redis pub/sub 推送給各服務的訂閱者
+	service.NewLiveHub(database.RedisClient).Start(context.Background())
+
+	// 背景郵件佇列
+	service.NewMailWorker().Start(context.Background())
+
 	err := server.Run(":" + port)
 	if err != nil {
 		panic(err)
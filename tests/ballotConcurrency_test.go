@@ -0,0 +1,306 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+	"vote/app/controller"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/middleware"
+	"vote/app/model"
+	"vote/app/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBallotIdempotency(t *testing.T) {
+	_, err := service.NewBallotIdempotency("short", nil)
+	assert.ErrorIs(t, err, service.ErrIdempotencyKeyInvalid)
+
+	none, err := service.NewBallotIdempotency("", nil)
+	assert.NoError(t, err)
+	assert.Nil(t, none)
+
+	key := uuid.NewString()
+	body := []byte(`{"1":{"10":1}}`)
+	idempotency, err := service.NewBallotIdempotency(key, body)
+	assert.NoError(t, err)
+	sealed, err := idempotency.Seal(7, &model.BallotReceipt{Code: "ABCD-EFGH"})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(sealed), "ABCD-EFGH")
+
+	t.Run("Retry returns the original receipt", func(t *testing.T) {
+		retry, _ := service.NewBallotIdempotency(key, body)
+		assert.Equal(t, idempotency.Digest(7), retry.Digest(7))
+		code, err := retry.Open(7, sealed)
+		assert.NoError(t, err)
+		assert.Equal(t, "ABCD-EFGH", code)
+	})
+
+	t.Run("Same key with a different ballot", func(t *testing.T) {
+		other, _ := service.NewBallotIdempotency(key, []byte(`{"1":{"11":1}}`))
+		_, err := other.Open(7, sealed)
+		assert.ErrorIs(t, err, service.ErrIdempotencyKeyReused)
+	})
+
+	t.Run("Bound to the credential", func(t *testing.T) {
+		assert.NotEqual(t, idempotency.Digest(7), idempotency.Digest(8))
+		_, err := idempotency.Open(8, sealed)
+		assert.Error(t, err)
+	})
+}
+
+// ballotFixture 送出選票測試用的投票、問題與路由
+type ballotFixture struct {
+	t        *testing.T
+	vote     model.Vote
+	question model.Question
+	router   *gin.Engine
+}
+
+// ballotResult 送出選票的回應
+type ballotResult struct {
+	code     int
+	receipt  string
+	replayed bool
+}
+
+// newBallotFixture 建立開放中的投票。需要已執行遷移的資料庫，以 DB_CONFIG 指定，未設定時略過測試。
+func newBallotFixture(t *testing.T, allowRevote bool) *ballotFixture {
+	if os.Getenv("DB_CONFIG") == "" {
+		t.Skip("DB_CONFIG is not set")
+	}
+	if _, err := database.Initialize(os.Getenv("DB_CONFIG")); err != nil {
+		t.Skip("database unavailable: " + err.Error())
+	}
+	if len(middleware.SecretKey) == 0 {
+		middleware.SecretKey = []byte("concurrency-test")
+		middleware.RefreshSecretKey = []byte("concurrency-test")
+	}
+	if os.Getenv("APP_HMAC_KEY") == "" {
+		t.Setenv("APP_HMAC_KEY", "concurrency-test")
+	}
+	db := database.SqlSession
+
+	owner := model.User{}
+	if err := db.Take(&owner).Error; err != nil {
+		t.Skip("no user to own the test vote")
+	}
+	now := time.Now()
+	vote := model.Vote{
+		Uuid:        uuid.New(),
+		Title:       "concurrency test",
+		StartTime:   now.Add(-time.Hour),
+		EndTime:     now.Add(time.Hour),
+		UserID:      owner.ID,
+		Status:      enum.Open,
+		AllowRevote: allowRevote,
+	}
+	assert.NoError(t, db.Create(&vote).Error)
+	t.Cleanup(func() {
+		db.Where("vote_id = ?", vote.Uuid).Delete(&model.BallotReceipt{})
+		db.Delete(&vote)
+	})
+	question := model.Question{
+		VoteID:     vote.Uuid,
+		Title:      "question",
+		Method:     enum.Plurality,
+		Candidates: []model.Candidate{{Name: "a"}, {Name: "b"}},
+	}
+	assert.NoError(t, db.Create(&question).Error)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/v1/voter/ballot/create", controller.NewBallotController().CreateBallots)
+
+	return &ballotFixture{t: t, vote: vote, question: question, router: router}
+}
+
+// newVoter 建立已啟用的密碼並回傳投票者令牌
+func (f *ballotFixture) newVoter() string {
+	password := model.Password{
+		VoteID:   f.vote.Uuid,
+		Password: "-",
+		Digest:   uuid.NewString(),
+		Status:   true,
+		State:    enum.CredentialActivated,
+	}
+	assert.NoError(f.t, database.SqlSession.Create(&password).Error)
+	token, _, err := middleware.GenVoterToken(password.ID, f.vote.Uuid, false, time.Now())
+	assert.NoError(f.t, err)
+
+	return token
+}
+
+// submit 以投票者令牌對第 candidate 位候選人投票
+func (f *ballotFixture) submit(token string, key string, candidate int) ballotResult {
+	body, _ := json.Marshal(model.BallotSelections{f.question.ID: {f.question.Candidates[candidate].ID: 1}})
+	req, _ := http.NewRequest(http.MethodPost, "/v1/voter/ballot/create", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.AddCookie(&http.Cookie{Name: "voter-token", Value: token})
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	w := httptest.NewRecorder()
+	f.router.ServeHTTP(w, req)
+
+	var response map[string]any
+	json.Unmarshal(w.Body.Bytes(), &response)
+	receipt, _ := response["receipt"].(string)
+	return ballotResult{w.Code, receipt, w.Header().Get("Idempotent-Replayed") == "true"}
+}
+
+// participations 密碼的已投票紀錄數
+func (f *ballotFixture) participations(token string) int64 {
+	claims, _ := middleware.ParseVoterToken(token)
+	var count int64
+	database.SqlSession.Model(&model.Participation{}).Where("password_id = ?", claims.ID).Count(&count)
+	return count
+}
+
+// hammer 同時送出 n 個請求
+func hammer(n int, send func(i int) ballotResult) []ballotResult {
+	results := make([]ballotResult, n)
+	var start, done sync.WaitGroup
+	start.Add(1)
+	for i := range results {
+		done.Add(1)
+		go func(i int) {
+			defer done.Done()
+			start.Wait()
+			results[i] = send(i)
+		}(i)
+	}
+	start.Done()
+	done.Wait()
+	return results
+}
+
+// TestConcurrentBallots 同一組密碼同時送出多張選票，只能寫入一次
+func TestConcurrentBallots(t *testing.T) {
+	f := newBallotFixture(t, false)
+
+	t.Run("Without Idempotency-Key only one submission succeeds", func(t *testing.T) {
+		token := f.newVoter()
+		results := hammer(20, func(int) ballotResult { return f.submit(token, "", 0) })
+
+		succeeded := 0
+		for _, r := range results {
+			if r.code == http.StatusOK {
+				succeeded++
+				continue
+			}
+			assert.Equal(t, http.StatusBadRequest, r.code)
+		}
+		assert.Equal(t, 1, succeeded)
+		assert.Equal(t, int64(1), f.participations(token))
+	})
+
+	t.Run("Retries with the same Idempotency-Key return the original receipt", func(t *testing.T) {
+		token := f.newVoter()
+		key := uuid.NewString()
+		results := hammer(20, func(int) ballotResult { return f.submit(token, key, 1) })
+
+		replayed := 0
+		for _, r := range results {
+			assert.Equal(t, http.StatusOK, r.code)
+			assert.Equal(t, results[0].receipt, r.receipt)
+			if r.replayed {
+				replayed++
+			}
+		}
+		assert.NotEmpty(t, results[0].receipt)
+		assert.Equal(t, 19, replayed)
+		assert.Equal(t, int64(1), f.participations(token))
+
+		var receipts int64
+		database.SqlSession.Model(&model.BallotReceipt{}).Where("vote_id = ?", f.vote.Uuid).Count(&receipts)
+		assert.Equal(t, int64(2), receipts)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, f.submit(token, key, 0).code)
+		assert.Equal(t, http.StatusBadRequest, f.submit(token, uuid.NewString(), 1).code)
+	})
+
+	t.Run("Receipts and ledger entries only keep the hour", func(t *testing.T) {
+		var receipts []model.BallotReceipt
+		database.SqlSession.Where("vote_id = ?", f.vote.Uuid).Find(&receipts)
+		var entries []model.LedgerEntry
+		database.SqlSession.Where("vote_id = ?", f.vote.Uuid).Find(&entries)
+		assert.NotEmpty(t, receipts)
+		assert.NotEmpty(t, entries)
+
+		for _, receipt := range receipts {
+			assert.True(t, receipt.CreatedAt.Equal(receipt.CreatedAt.Truncate(time.Hour)))
+		}
+		for _, entry := range entries {
+			assert.True(t, entry.CreatedAt.Equal(entry.CreatedAt.Truncate(time.Hour)))
+		}
+	})
+}
+
+// TestAllowRevoteSetting 不需資料庫：是否開放重新投票必須隨投票載入，開始投票後不能變更
+func TestAllowRevoteSetting(t *testing.T) {
+	useMemoryDatabase(t, &model.Vote{})
+
+	now := time.Now()
+	vote := model.Vote{
+		Uuid:        uuid.New(),
+		Title:       "Revote",
+		StartTime:   now.Add(-time.Hour),
+		EndTime:     now.Add(time.Hour),
+		Status:      enum.Open,
+		AllowRevote: true,
+	}
+	assert.NoError(t, database.SqlSession.Create(&vote).Error)
+
+	loaded, err := service.NewVoteService().GetVote(vote.Uuid)
+	assert.NoError(t, err)
+	assert.True(t, loaded.AllowRevote)
+
+	update := model.VoteUpdate{Title: vote.Title, StartTime: vote.StartTime, EndTime: vote.EndTime}
+	keep, change := true, false
+	update.AllowRevote = &keep
+	_, err = service.NewVoteService().UpdateVote(vote.Uuid, update)
+	assert.NoError(t, err)
+	update.AllowRevote = &change
+	_, err = service.NewVoteService().UpdateVote(vote.Uuid, update)
+	assert.ErrorIs(t, err, service.ErrAllowRevoteLocked)
+
+	loaded, err = service.NewVoteService().GetVote(vote.Uuid)
+	assert.NoError(t, err)
+	assert.True(t, loaded.AllowRevote)
+}
+
+// TestConcurrentRevotes 開放重新投票時，同時送出的選票依序互相取代，只留下一張有效回條
+func TestConcurrentRevotes(t *testing.T) {
+	f := newBallotFixture(t, true)
+	token := f.newVoter()
+
+	results := hammer(10, func(i int) ballotResult { return f.submit(token, "", i%2) })
+	for _, r := range results {
+		assert.Equal(t, http.StatusOK, r.code)
+	}
+	assert.Equal(t, int64(1), f.participations(token))
+
+	stats, err := service.NewPasswordService().GetCredentialAuditStats(f.vote.Uuid)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Voted)
+	assert.Equal(t, int64(10), stats.Submissions)
+	assert.Equal(t, int64(9), stats.Superseded)
+
+	ballots, err := service.NewTallyService().SelectBallots(f.vote.Uuid)
+	assert.NoError(t, err)
+	assert.Len(t, ballots, 1)
+	// 每次取代都寫入雜湊鏈
+	report, err := service.NewLedgerService().Verify(f.vote.Uuid)
+	assert.NoError(t, err)
+	assert.True(t, report.Valid, "%+v", report.Issue)
+}
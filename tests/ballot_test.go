@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+	"vote/app/enum"
+	"vote/app/model"
+	"vote/app/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateBallot(t *testing.T) {
+	voteId := uuid.New()
+	questions := map[uint64]model.Question{
+		1: {
+			ID:            1,
+			VoteID:        voteId,
+			Method:        enum.Approval,
+			MinSelections: 2,
+			MaxSelections: 2,
+			Candidates:    []model.Candidate{{ID: 10}, {ID: 11}, {ID: 12}},
+		},
+		2: {
+			ID:         2,
+			VoteID:     voteId,
+			Method:     enum.Plurality,
+			Candidates: []model.Candidate{{ID: 20}, {ID: 21}},
+		},
+		3: {ID: 3, VoteID: uuid.New(), Candidates: []model.Candidate{{ID: 30}}},
+	}
+
+	t.Run("Accepts a valid ballot", func(t *testing.T) {
+		err := service.ValidateBallot(voteId, questions, model.BallotSelections{
+			1: {10: 1, 12: 1},
+			2: {21: 1},
+		})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Blank questions skip the minimum", func(t *testing.T) {
+		err := service.ValidateBallot(voteId, questions, model.BallotSelections{1: {}})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Rejects the whole ballot with every field error", func(t *testing.T) {
+		err := service.ValidateBallot(voteId, questions, model.BallotSelections{
+			1: {10: 1, 11: 1, 12: 1},
+			2: {20: 1, 21: 1, 99: 1},
+			3: {30: 1},
+		})
+
+		var ballotErr *service.BallotError
+		assert.ErrorAs(t, err, &ballotErr)
+		assert.Equal(t, []service.BallotFieldError{
+			{QuestionID: 1, Message: "at most 2 selections allowed"},
+			{QuestionID: 2, CandidateID: 99, Message: "candidate does not belong to this question"},
+			{QuestionID: 3, Message: "question not found in this vote"},
+		}, ballotErr.Errors)
+	})
+
+	t.Run("Under-voted question", func(t *testing.T) {
+		err := service.ValidateBallot(voteId, questions, model.BallotSelections{1: {10: 1}})
+
+		assert.EqualError(t, err, "question 1: at least 2 selections required")
+	})
+}
+
+func TestCheckBallotWindow(t *testing.T) {
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	end := start.Add(8 * time.Hour)
+	vote := model.Vote{StartTime: start, EndTime: end, GracePeriod: 300, Status: enum.Open}
+
+	assert.ErrorIs(t, service.CheckVoteOpen(vote, start.Add(-time.Minute)), service.ErrVoteNotOpen)
+	assert.NoError(t, service.CheckVoteOpen(vote, start))
+	assert.ErrorIs(t, service.CheckVoteOpen(vote, end), service.ErrVoteClosed)
+
+	// 結束前登入，寬限期內仍可送出
+	assert.NoError(t, service.CheckBallotWindow(vote, end.Add(-time.Minute), end.Add(time.Minute)))
+	assert.ErrorIs(t, service.CheckBallotWindow(vote, end.Add(-time.Minute), end.Add(5*time.Minute)), service.ErrVoteClosed)
+	// 結束後才登入的不適用寬限期
+	assert.ErrorIs(t, service.CheckBallotWindow(vote, end, end.Add(time.Minute)), service.ErrVoteClosed)
+	assert.ErrorIs(t, service.CheckBallotWindow(vote, start, start.Add(-time.Second)), service.ErrVoteNotOpen)
+
+	// 狀態優先於時間
+	vote.Status = enum.Scheduled
+	assert.ErrorIs(t, service.CheckVoteOpen(vote, start.Add(time.Hour)), service.ErrVoteNotOpen)
+	vote.Status = enum.Closed
+	assert.ErrorIs(t, service.CheckVoteOpen(vote, start.Add(time.Hour)), service.ErrVoteClosed)
+	assert.NoError(t, service.CheckBallotWindow(vote, end.Add(-time.Minute), end.Add(time.Minute)))
+	vote.Status = enum.Tallied
+	assert.ErrorIs(t, service.CheckBallotWindow(vote, end.Add(-time.Minute), end.Add(time.Minute)), service.ErrVoteClosed)
+
+	// 提前結束時寬限期由實際結束的時間起算
+	closedAt := end.Add(-2 * time.Hour)
+	vote.Status = enum.Closed
+	vote.ClosedAt = &closedAt
+	assert.NoError(t, service.CheckBallotWindow(vote, closedAt.Add(-time.Minute), closedAt.Add(time.Minute)))
+	assert.ErrorIs(t, service.CheckBallotWindow(vote, closedAt.Add(-time.Minute), closedAt.Add(5*time.Minute)), service.ErrVoteClosed)
+	assert.ErrorIs(t, service.CheckBallotWindow(vote, closedAt.Add(time.Minute), closedAt.Add(2*time.Minute)), service.ErrVoteClosed)
+	assert.ErrorIs(t, service.CheckBallotWindow(vote, end.Add(-time.Minute), end.Add(time.Minute)), service.ErrVoteClosed)
+	// 排程晚於 EndTime 才結束時仍以 EndTime 起算
+	closedAt = end.Add(10 * time.Minute)
+	assert.ErrorIs(t, service.CheckBallotWindow(vote, end.Add(-time.Minute), end.Add(6*time.Minute)), service.ErrVoteClosed)
+}
+
+func TestBallotReceiptCode(t *testing.T) {
+	voteId := uuid.New()
+	nonce := []byte("server-nonce")
+	selections := model.BallotSelections{
+		2: {20: 1, 21: 0},
+		1: {10: 2, 11: 1},
+	}
+	reordered := model.BallotSelections{
+		1: {11: 1, 10: 2},
+		2: {21: 0, 20: 1},
+	}
+
+	code := service.BallotReceiptCode(voteId, selections, nonce)
+	assert.Len(t, code, 64)
+	assert.Equal(t, code, service.BallotReceiptCode(voteId, reordered, nonce))
+	assert.NotEqual(t, code, service.BallotReceiptCode(voteId, selections, []byte("other-nonce")))
+	assert.NotEqual(t, code, service.BallotReceiptCode(voteId, model.BallotSelections{1: {10: 1, 11: 2}, 2: {20: 1}}, nonce))
+}
+
+func TestBallotHasNoCredential(t *testing.T) {
+	// 選票與雜湊鏈的內容都不能包含密碼
+	data, err := json.Marshal(model.Ballot{ID: 1, QuestionID: 2})
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "password")
+
+	canonical := service.CanonicalBallot(uuid.New(), model.Ballot{ID: 1, QuestionID: 2})
+	assert.NotContains(t, canonical, "password")
+}
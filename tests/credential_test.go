@@ -0,0 +1,40 @@
+package tests
+
+import (
+	"testing"
+	"vote/app/enum"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCredentialStateTransitions(t *testing.T) {
+	allowed := []struct{ from, to enum.CredentialState }{
+		{enum.CredentialIssued, enum.CredentialActivated},
+		{enum.CredentialActivated, enum.CredentialIssued},
+		{enum.CredentialActivated, enum.CredentialUsed},
+		{enum.CredentialActivated, enum.CredentialRevoked},
+		{enum.CredentialIssued, enum.CredentialExpired},
+		{enum.CredentialExpired, enum.CredentialRevoked},
+	}
+	for _, transition := range allowed {
+		assert.True(t, transition.from.CanTransitionTo(transition.to), "%s -> %s", transition.from, transition.to)
+	}
+
+	// 已投票與已撤銷的密碼不能再變更，未啟用的密碼不能投票
+	denied := []struct{ from, to enum.CredentialState }{
+		{enum.CredentialIssued, enum.CredentialUsed},
+		{enum.CredentialUsed, enum.CredentialRevoked},
+		{enum.CredentialUsed, enum.CredentialActivated},
+		{enum.CredentialRevoked, enum.CredentialActivated},
+		{enum.CredentialExpired, enum.CredentialActivated},
+	}
+	for _, transition := range denied {
+		assert.False(t, transition.from.CanTransitionTo(transition.to), "%s -> %s", transition.from, transition.to)
+	}
+
+	assert.True(t, enum.CredentialActivated.CanLogin())
+	assert.True(t, enum.CredentialUsed.CanLogin())
+	assert.False(t, enum.CredentialIssued.CanLogin())
+	assert.False(t, enum.CredentialRevoked.CanLogin())
+	assert.False(t, enum.CredentialExpired.CanLogin())
+}
@@ -0,0 +1,47 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"vote/app/database"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// useMemoryDatabase 以記憶體中的 SQLite 取代 database.SqlSession 並建立資料表，測試結束後還原。
+// 不需要 DB_CONFIG，適合只用到基本查詢的服務；SQLite 沒有列鎖，併發的行為仍需以 PostgreSQL 測試。
+func useMemoryDatabase(t *testing.T, models ...any) {
+	t.Helper()
+
+	db, err := gorm.Open(sqlite.Open("file:"+t.Name()+"?mode=memory&cache=shared"), &gorm.Config{
+		Logger: logger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 建表時去掉 PostgreSQL 專用的預設值，測試資料需自行指定 UUID
+	err = db.Callback().Raw().Before("gorm:raw").Register("tests:sqlite_defaults", func(tx *gorm.DB) {
+		sql := strings.ReplaceAll(tx.Statement.SQL.String(), " DEFAULT uuid_generate_v4()", "")
+		tx.Statement.SQL.Reset()
+		tx.Statement.SQL.WriteString(sql)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(models...); err != nil {
+		t.Fatal(err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := database.SqlSession
+	database.SqlSession = db
+	t.Cleanup(func() {
+		database.SqlSession = previous
+		sqlDB.Close()
+	})
+}
@@ -0,0 +1,240 @@
+package tests
+
+import (
+	"math/big"
+	"testing"
+	"vote/app/database"
+	"vote/app/elgamal"
+	"vote/app/enum"
+	"vote/app/model"
+	"vote/app/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElGamalGroup(t *testing.T) {
+	assert.True(t, elgamal.P.ProbablyPrime(20))
+	assert.True(t, elgamal.Q.ProbablyPrime(20))
+	assert.Equal(t, 0, new(big.Int).Exp(elgamal.G, elgamal.Q, elgamal.P).Cmp(big.NewInt(1)))
+
+	secret, public, err := elgamal.GenerateKey(nil)
+	assert.NoError(t, err)
+	ct, _, err := elgamal.Encrypt(public, 3, nil)
+	assert.NoError(t, err)
+	m, err := elgamal.Decrypt(secret, ct, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), m)
+}
+
+func TestEncryptedTally(t *testing.T) {
+	voteId := uuid.New()
+	const threshold = 2
+
+	// 受託人上傳傳輸公鑰
+	trustees := make([]model.Trustee, 3)
+	transportSecrets := make(map[int]*big.Int)
+	for i := range trustees {
+		secret, public, err := elgamal.GenerateKey(nil)
+		assert.NoError(t, err)
+		trustees[i] = model.Trustee{VoteID: voteId, Number: i + 1, TransportKey: elgamal.NewInt(public)}
+		transportSecrets[i+1] = secret
+	}
+
+	// 每位受託人發出分片
+	var shares []model.TrusteeShare
+	for i := range trustees {
+		deal, err := service.DealShares(voteId, trustees[i].Number, threshold, trustees, nil)
+		assert.NoError(t, err)
+		trustees[i].Commitments = deal.Commitments
+		for to, sealed := range deal.Shares {
+			shares = append(shares, model.TrusteeShare{VoteID: voteId, FromNumber: trustees[i].Number, ToNumber: to, Sealed: sealed})
+		}
+	}
+	keyShares := make(map[int]*big.Int)
+	for _, trustee := range trustees {
+		share, err := service.OpenTrusteeShare(voteId, trustee.Number, transportSecrets[trustee.Number], trustees, shares)
+		assert.NoError(t, err)
+		keyShares[trustee.Number] = share
+	}
+
+	var dealers [][]*elgamal.Int
+	for _, trustee := range trustees {
+		dealers = append(dealers, trustee.Commitments)
+	}
+	publicKey := elgamal.JointPublicKey(dealers)
+	vote := model.Vote{Uuid: voteId, Encrypted: true, Threshold: threshold, PublicKey: elgamal.NewInt(publicKey)}
+
+	question := model.Question{
+		ID:            1,
+		VoteID:        voteId,
+		Method:        enum.Approval,
+		MaxSelections: 2,
+		Candidates:    []model.Candidate{{ID: 10}, {ID: 11}, {ID: 12}},
+	}
+	questions := map[uint64]model.Question{1: question}
+
+	t.Run("Rejects a share that does not match the commitments", func(t *testing.T) {
+		// 把 2 號發給 1 號的分片換成發給 3 號的
+		var forThree *elgamal.SealedShare
+		for _, share := range shares {
+			if share.FromNumber == 2 && share.ToNumber == 3 {
+				forThree = share.Sealed
+			}
+		}
+		tampered := append([]model.TrusteeShare(nil), shares...)
+		for i := range tampered {
+			if tampered[i].FromNumber == 2 && tampered[i].ToNumber == 1 {
+				tampered[i].Sealed = forThree
+			}
+		}
+		_, err := service.OpenTrusteeShare(voteId, 1, transportSecrets[1], trustees, tampered)
+		assert.Error(t, err)
+	})
+
+	choices := [][]uint64{{10, 11}, {11}, {11, 12}, {}}
+	var ballots []model.Ballot
+	for i, chosen := range choices {
+		voter := uint64(i + 1)
+		encrypted, err := service.EncryptQuestion(publicKey, voteId, voter, question, chosen, nil)
+		assert.NoError(t, err)
+		assert.NoError(t, service.ValidateEncryptedBallot(vote, voter, questions, model.EncryptedBallot{Questions: []model.EncryptedQuestion{*encrypted}}))
+
+		ballot := model.Ballot{QuestionID: question.ID, Proof: encrypted.Proof}
+		for _, selection := range encrypted.Selections {
+			ballot.BallotSelects = append(ballot.BallotSelects, model.BallotSelect{CandidateID: selection.CandidateID, Ciphertext: selection.Ciphertext, Proof: selection.Proof})
+		}
+		ballots = append(ballots, ballot)
+	}
+
+	t.Run("Rejects ballots outside the allowed range", func(t *testing.T) {
+		tooMany, err := service.EncryptQuestion(publicKey, voteId, 1, model.Question{
+			ID: 1, VoteID: voteId, Method: enum.Approval, Candidates: question.Candidates,
+		}, []uint64{10, 11, 12}, nil)
+		assert.NoError(t, err)
+		assert.IsType(t, &service.BallotError{}, service.ValidateEncryptedBallot(vote, 1, questions, model.EncryptedBallot{Questions: []model.EncryptedQuestion{*tooMany}}))
+
+		// 把兩位候選人的密文對調，證明便不再成立
+		swapped, err := service.EncryptQuestion(publicKey, voteId, 1, question, []uint64{10}, nil)
+		assert.NoError(t, err)
+		swapped.Selections[0].Ciphertext, swapped.Selections[1].Ciphertext = swapped.Selections[1].Ciphertext, swapped.Selections[0].Ciphertext
+		assert.IsType(t, &service.BallotError{}, service.ValidateEncryptedBallot(vote, 1, questions, model.EncryptedBallot{Questions: []model.EncryptedQuestion{*swapped}}))
+
+		missing, err := service.EncryptQuestion(publicKey, voteId, 1, question, []uint64{10}, nil)
+		assert.NoError(t, err)
+		missing.Selections = missing.Selections[:2]
+		assert.IsType(t, &service.BallotError{}, service.ValidateEncryptedBallot(vote, 1, questions, model.EncryptedBallot{Questions: []model.EncryptedQuestion{*missing}}))
+	})
+
+	tally := service.AggregateTally([]model.Question{question}, ballots)
+	assert.Len(t, tally, 3)
+
+	partialsOf := func(number int) []model.PartialDecryption {
+		uploads, err := service.PartialDecryptions(voteId, number, keyShares[number], tally, nil)
+		assert.NoError(t, err)
+
+		partials := make([]model.PartialDecryption, 0, len(uploads))
+		for i, upload := range uploads {
+			assert.True(t, service.VerifyPartialDecryption(voteId, trustees, number, tally[i], upload))
+			partials = append(partials, model.PartialDecryption{
+				VoteID: voteId, TrusteeNumber: number, QuestionID: upload.QuestionID, CandidateID: upload.CandidateID,
+				Share: upload.Share, Proof: upload.Proof,
+			})
+		}
+		return partials
+	}
+
+	t.Run("Rejects a partial decryption from the wrong trustee", func(t *testing.T) {
+		uploads, err := service.PartialDecryptions(voteId, 3, keyShares[3], tally, nil)
+		assert.NoError(t, err)
+		assert.False(t, service.VerifyPartialDecryption(voteId, trustees, 2, tally[0], uploads[0]))
+	})
+
+	third := partialsOf(3)
+	_, err := service.CombineTally(threshold, tally, third)
+	assert.ErrorIs(t, err, service.ErrDecryptionPending)
+
+	counts, err := service.CombineTally(threshold, tally, append(partialsOf(1), third...))
+	assert.NoError(t, err)
+	assert.Equal(t, map[uint64]int64{10: 1, 11: 3, 12: 1}, counts[1])
+}
+
+func TestGetEncryptedVote(t *testing.T) {
+	useMemoryDatabase(t, &model.Vote{}, &model.Trustee{}, &model.TrusteeShare{})
+
+	_, public, err := elgamal.GenerateKey(nil)
+	assert.NoError(t, err)
+	vote := model.Vote{
+		Uuid:      uuid.New(),
+		Title:     "Encrypted",
+		Status:    enum.Draft,
+		Encrypted: true,
+		Threshold: 2,
+		PublicKey: elgamal.NewInt(public),
+	}
+	assert.NoError(t, database.SqlSession.Create(&vote).Error)
+
+	// 加密設定必須隨投票載入，否則選票與開票會走明文流程
+	loaded, err := service.NewVoteService().GetVote(vote.Uuid)
+	assert.NoError(t, err)
+	assert.True(t, loaded.Encrypted)
+	assert.Equal(t, 2, loaded.Threshold)
+	if assert.NotNil(t, loaded.PublicKey) {
+		assert.Equal(t, 0, loaded.PublicKey.Big().Cmp(public))
+	}
+
+	ceremony, err := service.NewTrusteeService().GetCeremony(model.Trustee{VoteID: vote.Uuid, Number: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ceremony.Threshold)
+	assert.Equal(t, vote.PublicKey.String(), ceremony.PublicKey.String())
+}
+
+// TestCopiedEncryptedBallot 不需資料庫：抄錄他人的密文與證明送出的選票會被拒絕
+func TestCopiedEncryptedBallot(t *testing.T) {
+	useMemoryDatabase(t, &model.Vote{}, &model.Question{}, &model.Candidate{}, &model.BallotReceipt{}, &model.Ballot{}, &model.BallotSelect{})
+
+	_, public, err := elgamal.GenerateKey(nil)
+	assert.NoError(t, err)
+	vote := model.Vote{
+		Uuid:      uuid.New(),
+		Title:     "Encrypted",
+		Status:    enum.Open,
+		Encrypted: true,
+		Threshold: 1,
+		PublicKey: elgamal.NewInt(public),
+	}
+	assert.NoError(t, database.SqlSession.Create(&vote).Error)
+	question := model.Question{VoteID: vote.Uuid, Title: "Chair", Method: enum.Approval, Candidates: []model.Candidate{{Name: "A"}, {Name: "B"}}}
+	assert.NoError(t, database.SqlSession.Create(&question).Error)
+	questions := map[uint64]model.Question{question.ID: question}
+
+	// 1 號密碼的選票已計入
+	encrypted, err := service.EncryptQuestion(public, vote.Uuid, 1, question, []uint64{question.Candidates[0].ID}, nil)
+	assert.NoError(t, err)
+	original := model.EncryptedBallot{Questions: []model.EncryptedQuestion{*encrypted}}
+	assert.NoError(t, service.ValidateEncryptedBallot(vote, 1, questions, original))
+	receipt := model.BallotReceipt{VoteID: vote.Uuid, Code: "original"}
+	assert.NoError(t, database.SqlSession.Create(&receipt).Error)
+	stored := model.Ballot{QuestionID: question.ID, ReceiptID: &receipt.ID, Proof: encrypted.Proof}
+	for _, selection := range encrypted.Selections {
+		stored.BallotSelects = append(stored.BallotSelects, model.BallotSelect{CandidateID: selection.CandidateID, Ciphertext: selection.Ciphertext, Proof: selection.Proof})
+	}
+	assert.NoError(t, database.SqlSession.Create(&stored).Error)
+
+	t.Run("Proofs are bound to the submitting credential", func(t *testing.T) {
+		assert.IsType(t, &service.BallotError{}, service.ValidateEncryptedBallot(vote, 2, questions, original))
+
+		_, err := service.NewBallotService().CreateEncryptedBallots(2, vote, original, nil)
+		assert.IsType(t, &service.BallotError{}, err)
+	})
+
+	t.Run("Ciphertexts already counted are rejected", func(t *testing.T) {
+		_, err := service.NewBallotService().CreateEncryptedBallots(1, vote, original, nil)
+		var ballotErr *service.BallotError
+		if assert.ErrorAs(t, err, &ballotErr) {
+			assert.Len(t, ballotErr.Errors, len(question.Candidates))
+			assert.Equal(t, question.ID, ballotErr.Errors[0].QuestionID)
+			assert.Contains(t, ballotErr.Errors[0].Message, "already submitted")
+		}
+	})
+}
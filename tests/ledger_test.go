@@ -0,0 +1,121 @@
+package tests
+
+import (
+	"testing"
+	"vote/app/model"
+	"vote/app/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// buildLedger 依序為選票建立雜湊鏈
+func buildLedger(voteId uuid.UUID, ballots []model.Ballot) []model.LedgerEntry {
+	entries := make([]model.LedgerEntry, len(ballots))
+	prevHash := service.LedgerGenesis
+	for i, ballot := range ballots {
+		sequence := uint64(i) + 1
+		entries[i] = model.LedgerEntry{
+			VoteID:   voteId,
+			Sequence: sequence,
+			BallotID: ballot.ID,
+			PrevHash: prevHash,
+			Hash:     service.LedgerHash(prevHash, sequence, service.CanonicalBallot(voteId, ballot)),
+		}
+		prevHash = entries[i].Hash
+	}
+
+	return entries
+}
+
+func TestVerifyLedgerChain(t *testing.T) {
+	voteId := uuid.New()
+	ballots := []model.Ballot{
+		{ID: 1, QuestionID: 10, BallotSelects: []model.BallotSelect{{ID: 1, BallotID: 1, CandidateID: 100, Value: 1}}},
+		{ID: 2, QuestionID: 10, BallotSelects: []model.BallotSelect{{ID: 3, BallotID: 2, CandidateID: 101, Value: 1}, {ID: 2, BallotID: 2, CandidateID: 100, Value: 0}}},
+		{ID: 3, QuestionID: 10, BallotSelects: []model.BallotSelect{{ID: 4, BallotID: 3, CandidateID: 101, Value: 1}}},
+	}
+	entries := buildLedger(voteId, ballots)
+	current := func() map[uint64]model.Ballot {
+		byId := map[uint64]model.Ballot{}
+		for _, ballot := range ballots {
+			byId[ballot.ID] = ballot
+		}
+		return byId
+	}
+
+	assert.Nil(t, service.VerifyLedgerChain(voteId, entries, current()))
+
+	// 修改選票標記
+	tampered := current()
+	ballot := tampered[2]
+	ballot.BallotSelects = []model.BallotSelect{{ID: 3, BallotID: 2, CandidateID: 100, Value: 1}, {ID: 2, BallotID: 2, CandidateID: 101, Value: 0}}
+	tampered[2] = ballot
+	issue := service.VerifyLedgerChain(voteId, entries, tampered)
+	assert.Equal(t, "content", issue.Kind)
+	assert.Equal(t, uint64(2), issue.Sequence)
+
+	// 刪除選票
+	deleted := current()
+	delete(deleted, 3)
+	issue = service.VerifyLedgerChain(voteId, entries, deleted)
+	assert.Equal(t, "missing_ballot", issue.Kind)
+	assert.Equal(t, uint64(3), issue.Sequence)
+
+	// 刪除雜湊鏈中的紀錄
+	issue = service.VerifyLedgerChain(voteId, []model.LedgerEntry{entries[0], entries[2]}, current())
+	assert.Equal(t, "missing_entry", issue.Kind)
+	assert.Equal(t, uint64(2), issue.Sequence)
+
+	// 繞過雜湊鏈寫入的選票
+	extra := current()
+	extra[4] = model.Ballot{ID: 4, QuestionID: 10}
+	issue = service.VerifyLedgerChain(voteId, entries, extra)
+	assert.Equal(t, "unchained_ballot", issue.Kind)
+	assert.Equal(t, uint64(4), issue.BallotID)
+}
+
+func TestVerifyLedgerSupersession(t *testing.T) {
+	voteId := uuid.New()
+	first, second := uint64(1), uint64(2)
+	// 回條 1 的選票被回條 2 取代，回條 2 的選票被回條 3 取代
+	ballots := []model.Ballot{
+		{ID: 1, QuestionID: 10, ReceiptID: &first, BallotSelects: []model.BallotSelect{{ID: 1, BallotID: 1, CandidateID: 100, Value: 1}}},
+		{ID: 2, QuestionID: 10, ReceiptID: &second, SupersedesID: &first, BallotSelects: []model.BallotSelect{{ID: 2, BallotID: 2, CandidateID: 101, Value: 1}}},
+		{ID: 3, QuestionID: 10, SupersedesID: &second, BallotSelects: []model.BallotSelect{{ID: 3, BallotID: 3, CandidateID: 100, Value: 1}}},
+	}
+	entries := buildLedger(voteId, ballots)
+	byId := map[uint64]model.Ballot{}
+	for _, ballot := range ballots {
+		byId[ballot.ID] = ballot
+	}
+
+	assert.Nil(t, service.VerifyLedgerChain(voteId, entries, byId))
+	assert.Nil(t, service.VerifyLedgerSupersession(byId, []uint64{1, 2}))
+
+	// 取代的回條包含在選票內容中
+	assert.Contains(t, service.CanonicalBallot(voteId, ballots[1]), ";supersedes=1")
+	assert.NotContains(t, service.CanonicalBallot(voteId, ballots[0]), "supersedes")
+
+	// 直接標記回條為已取代，排除其選票
+	issue := service.VerifyLedgerSupersession(byId, []uint64{1, 2, 3})
+	assert.Equal(t, "supersede", issue.Kind)
+	assert.Contains(t, issue.Message, "receipt 3")
+
+	// 取消取代標記，讓被取代的選票重新計入
+	issue = service.VerifyLedgerSupersession(byId, []uint64{1})
+	assert.Equal(t, "supersede", issue.Kind)
+	assert.Equal(t, uint64(3), issue.BallotID)
+
+	// 竄改選票記錄的取代回條
+	tampered := map[uint64]model.Ballot{}
+	for id, ballot := range byId {
+		tampered[id] = ballot
+	}
+	ballot := tampered[3]
+	ballot.SupersedesID = &first
+	tampered[3] = ballot
+	issue = service.VerifyLedgerChain(voteId, entries, tampered)
+	assert.Equal(t, "content", issue.Kind)
+	assert.Equal(t, uint64(3), issue.Sequence)
+}
@@ -0,0 +1,138 @@
+package tests
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/model"
+	"vote/app/service"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLiveHub(t *testing.T) {
+	// Redis 連線失敗會寫入日誌，改寫到暫存目錄
+	t.Setenv("LOG_DIR", t.TempDir())
+
+	// Redis 無法連線時仍會推送給本機的訂閱者
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	defer client.Close()
+
+	voteId, otherId := uuid.New(), uuid.New()
+	var snapshots, others atomic.Int64
+	hub := service.NewLiveHub(client)
+	hub.Throttle = 50 * time.Millisecond
+	hub.Snapshot = func(id uuid.UUID) (*model.LiveResult, error) {
+		if id != voteId {
+			return &model.LiveResult{VoteID: id, Voted: others.Add(1)}, nil
+		}
+		return &model.LiveResult{VoteID: id, Voted: snapshots.Add(1)}, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	updates, err := hub.Subscribe(ctx, voteId)
+	assert.NoError(t, err)
+
+	receive := func() *model.LiveResult {
+		select {
+		case result := <-updates:
+			return result
+		case <-time.After(2 * time.Second):
+			t.Fatal("no live result received")
+			return nil
+		}
+	}
+
+	t.Run("Subscribers receive the current snapshot", func(t *testing.T) {
+		result := receive()
+		assert.Equal(t, voteId, result.VoteID)
+		assert.EqualValues(t, 1, result.Voted)
+	})
+
+	t.Run("Notifications within the throttle are coalesced", func(t *testing.T) {
+		for i := 0; i < 5; i++ {
+			hub.Notify(voteId)
+		}
+		hub.Notify(otherId)
+
+		result := receive()
+		assert.EqualValues(t, 2, result.Voted)
+		time.Sleep(3 * hub.Throttle)
+		// 另一個投票的通知也會統計，但不會推送給這個訂閱者
+		assert.EqualValues(t, 2, snapshots.Load())
+		assert.EqualValues(t, 1, others.Load())
+		assert.Len(t, updates, 0)
+	})
+
+	t.Run("Cancelling the subscription closes the channel", func(t *testing.T) {
+		cancel()
+		select {
+		case _, ok := <-updates:
+			assert.False(t, ok)
+		case <-time.After(2 * time.Second):
+			t.Fatal("channel was not closed")
+		}
+	})
+}
+
+// TestLiveSnapshot 不需資料庫：開放即時結果的投票統計候選人目前的票數，被取代的選票不計入
+func TestLiveSnapshot(t *testing.T) {
+	useMemoryDatabase(t, &model.Vote{}, &model.Password{}, &model.Participation{}, &model.Question{},
+		&model.Candidate{}, &model.BallotReceipt{}, &model.Ballot{}, &model.BallotSelect{})
+
+	now := time.Now()
+	vote := model.Vote{
+		Uuid:        uuid.New(),
+		Title:       "Live",
+		StartTime:   now.Add(-time.Hour),
+		EndTime:     now.Add(time.Hour),
+		Status:      enum.Open,
+		LiveResults: true,
+	}
+	assert.NoError(t, database.SqlSession.Create(&vote).Error)
+	question := model.Question{VoteID: vote.Uuid, Title: "Chair", Method: enum.Plurality, Candidates: []model.Candidate{{Name: "A"}, {Name: "B"}}}
+	assert.NoError(t, database.SqlSession.Create(&question).Error)
+	for i, state := range []enum.CredentialState{enum.CredentialUsed, enum.CredentialIssued, enum.CredentialRevoked} {
+		password := model.Password{VoteID: vote.Uuid, Password: "secret", Digest: uuid.NewString(), State: state}
+		assert.NoError(t, database.SqlSession.Create(&password).Error)
+		if i == 0 {
+			assert.NoError(t, database.SqlSession.Create(&model.Participation{PasswordID: password.ID, VoteID: vote.Uuid, VotedAt: now}).Error)
+		}
+	}
+
+	// 第一張選票已被重新投票取代
+	superseded := model.BallotReceipt{VoteID: vote.Uuid, Code: "superseded", Superseded: true}
+	current := model.BallotReceipt{VoteID: vote.Uuid, Code: "current"}
+	assert.NoError(t, database.SqlSession.Create(&superseded).Error)
+	assert.NoError(t, database.SqlSession.Create(&current).Error)
+	for _, ballot := range []model.Ballot{
+		{QuestionID: question.ID, ReceiptID: &superseded.ID, BallotSelects: []model.BallotSelect{{CandidateID: question.Candidates[0].ID, Value: 1}}},
+		{QuestionID: question.ID, ReceiptID: &current.ID, BallotSelects: []model.BallotSelect{{CandidateID: question.Candidates[1].ID, Value: 1}}},
+	} {
+		assert.NoError(t, database.SqlSession.Create(&ballot).Error)
+	}
+
+	result, err := service.NewLiveService().Snapshot(vote.Uuid)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, result.Eligible)
+	assert.EqualValues(t, 1, result.Voted)
+	if assert.Len(t, result.Questions, 1) {
+		assert.EqualValues(t, 1, result.Questions[0].Ballots)
+		assert.Equal(t, []model.LiveCount{
+			{CandidateID: question.Candidates[0].ID, Votes: 0},
+			{CandidateID: question.Candidates[1].ID, Votes: 1},
+		}, result.Questions[0].Candidates)
+	}
+
+	// 關閉即時結果後只統計投票率
+	assert.NoError(t, database.SqlSession.Model(&vote).Update("live_results", false).Error)
+	result, err = service.NewLiveService().Snapshot(vote.Uuid)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, result.Voted)
+	assert.Empty(t, result.Questions)
+}
@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"mime/multipart"
+	netmail "net/mail"
+	"strings"
+	"testing"
+	"time"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/mail"
+	"vote/app/model"
+	"vote/app/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMailTemplates(t *testing.T) {
+	renderer, err := mail.NewRenderer("../i18n")
+	assert.NoError(t, err)
+
+	transport := &mail.MemoryTransport{}
+	mailer := &mail.Mailer{
+		From:      "Vote <noreply@example.com>",
+		Language:  "en",
+		Renderer:  renderer,
+		Transport: transport,
+	}
+
+	data := mail.InvitationData{
+		Name:      "<Alice>",
+		VoteTitle: "Board election",
+		StartTime: time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC),
+		EndTime:   time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC),
+		Password:  "s3cret",
+		LoginURL:  "https://example.com/voter/login?a=1&b=2",
+	}
+	assert.NoError(t, mailer.SendTemplate("alice@example.com", mail.Invitation, "", data))
+	assert.NoError(t, mailer.SendTemplate("alice@example.com", mail.Invitation, "zh", data))
+
+	messages := transport.Messages()
+	assert.Len(t, messages, 2)
+	assert.Equal(t, "[Board election] Your voting credential", messages[0].Subject)
+	assert.Equal(t, "[Board election] 您的投票密碼", messages[1].Subject)
+	assert.Contains(t, messages[0].Text, "Dear <Alice>,")
+	assert.Contains(t, messages[0].HTML, "Dear &lt;Alice&gt;,")
+	assert.Contains(t, messages[0].HTML, `href="https://example.com/voter/login?a=1&amp;b=2"`)
+	assert.Contains(t, messages[1].Text, "您的密碼：s3cret")
+
+	// 輸出的郵件需可被標準函式庫解析出兩個 multipart 區段
+	raw, err := messages[1].Bytes()
+	assert.NoError(t, err)
+	parsed, err := netmail.ReadMessage(bytes.NewReader(raw))
+	assert.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	assert.NoError(t, err)
+	assert.Equal(t, "[Board election] 您的投票密碼", subject)
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	assert.NoError(t, err)
+	assert.Equal(t, "multipart/alternative", mediaType)
+
+	reader := multipart.NewReader(parsed.Body, params["boundary"])
+	var types []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		types = append(types, strings.Split(part.Header.Get("Content-Type"), ";")[0])
+	}
+	assert.Equal(t, []string{"text/plain", "text/html"}, types)
+
+	// 收件者含換行時拒絕寄送
+	err = mailer.Send(mail.Message{To: []string{"a@example.com\r\nBcc: b@example.com"}, Subject: "x", Text: "x"})
+	assert.Error(t, err)
+}
+
+func TestMailWorkerBackoff(t *testing.T) {
+	worker := &service.MailWorker{BaseBackoff: 30 * time.Second, MaxBackoff: 5 * time.Minute}
+
+	assert.Equal(t, 30*time.Second, worker.Backoff(1))
+	assert.Equal(t, time.Minute, worker.Backoff(2))
+	assert.Equal(t, 4*time.Minute, worker.Backoff(4))
+	assert.Equal(t, 5*time.Minute, worker.Backoff(5))
+	assert.Equal(t, 5*time.Minute, worker.Backoff(50))
+}
+
+func TestResendCredentialQueuesMail(t *testing.T) {
+	useMemoryDatabase(t, &model.Voter{}, &model.MailJob{})
+
+	vote := model.Vote{Uuid: uuid.New()}
+	voter := model.Voter{VoteID: vote.Uuid, Name: "Alice", Email: "alice@example.com", PasswordID: 1, DeliveryStatus: enum.DeliverySent}
+	assert.NoError(t, database.SqlSession.Create(&voter).Error)
+
+	// 重新寄送只排入佇列，由郵件工作者寄送
+	resent, queued, err := service.NewVoterRollService().ResendCredential(vote, voter.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, voter.ID, resent.ID)
+	assert.Equal(t, 1, queued)
+
+	// 佇列中已有密碼郵件時不重複排入
+	_, queued, err = service.NewVoterRollService().ResendCredential(vote, voter.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, queued)
+
+	var jobs []model.MailJob
+	assert.NoError(t, database.SqlSession.Find(&jobs).Error)
+	assert.Len(t, jobs, 1)
+	assert.Equal(t, mail.Invitation, jobs[0].Template)
+	assert.Equal(t, enum.MailQueued, jobs[0].Status)
+
+	_, _, err = service.NewVoterRollService().ResendCredential(model.Vote{Uuid: uuid.New()}, voter.ID)
+	assert.Error(t, err)
+}
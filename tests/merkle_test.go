@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+	"vote/app/model"
+	"vote/app/service"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func merkleBallots(n int) []model.Ballot {
+	ballots := make([]model.Ballot, n)
+	for i := range ballots {
+		ballots[i] = model.Ballot{
+			ID:         uint64(i + 1),
+			QuestionID: 1,
+			BallotSelects: []model.BallotSelect{
+				{CandidateID: uint64(i%3 + 1), Value: 1},
+			},
+		}
+	}
+
+	return ballots
+}
+
+// merkleCode 第 i 張選票的回條代碼
+func merkleCode(i int) string {
+	return fmt.Sprintf("%064x", i+1)
+}
+
+func TestMerkleProofs(t *testing.T) {
+	voteId := uuid.New()
+
+	for _, n := range []int{1, 2, 3, 5, 8, 13} {
+		leaves := make([]common.Hash, n)
+		for i, ballot := range merkleBallots(n) {
+			leaves[i] = service.MerkleLeaf(voteId, merkleCode(i), ballot)
+		}
+		tree := service.NewMerkleTree(leaves)
+		root := tree.Root().Hex()
+
+		for i := 0; i < n; i++ {
+			proof := tree.Proof(i)
+			assert.True(t, service.VerifyMerkleProof(proof, root), "n=%d leaf=%d", n, i)
+
+			// 竄改葉節點後證明失效
+			tampered := proof
+			tampered.Leaf = service.MerkleLeaf(voteId, merkleCode(i), model.Ballot{ID: 999, QuestionID: 1}).Hex()
+			assert.False(t, service.VerifyMerkleProof(tampered, root), "n=%d leaf=%d", n, i)
+		}
+	}
+
+	// 單一葉節點的樹根即為葉節點
+	leaf := service.MerkleLeaf(voteId, merkleCode(0), merkleBallots(1)[0])
+	assert.Equal(t, leaf, service.NewMerkleTree([]common.Hash{leaf}).Root())
+	assert.Equal(t, common.Hash{}, service.NewMerkleTree(nil).Root())
+}
+
+func TestMerkleBundleSignature(t *testing.T) {
+	voteId := uuid.New()
+	key, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+
+	ballots := merkleBallots(4)
+	leaves := make([]common.Hash, 4)
+	for i, ballot := range ballots {
+		leaves[i] = service.MerkleLeaf(voteId, merkleCode(i), ballot)
+	}
+	tree := service.NewMerkleTree(leaves)
+	proofOf := func(tree *service.MerkleTree, index int, ballot model.Ballot) model.MerkleProof {
+		proof := tree.Proof(index)
+		proof.BallotHash = service.MerkleBallotHash(voteId, ballot).Hex()
+		return proof
+	}
+	root := tree.Root().Hex()
+
+	signature, err := service.SignMerkleRoot(key, service.MerkleRootMessage(voteId, root, 4))
+	assert.NoError(t, err)
+
+	signer := crypto.PubkeyToAddress(key.PublicKey).Hex()
+	bundle := model.MerkleProofBundle{
+		MerkleRoot: model.MerkleRoot{VoteID: voteId, Root: root, LeafCount: 4, Signature: signature, Signer: signer},
+		Proofs:     []model.MerkleProof{proofOf(tree, 2, ballots[2])},
+	}
+	code := merkleCode(2)
+	assert.NoError(t, service.VerifyMerkleBundle(bundle, code, ""))
+	assert.NoError(t, service.VerifyMerkleBundle(bundle, strings.ToUpper(code), signer))
+	assert.Error(t, service.VerifyMerkleBundle(bundle, code, crypto.PubkeyToAddress(other.PublicKey).Hex()))
+	assert.Error(t, service.VerifyMerkleBundle(bundle, "", ""))
+
+	// 其他回條的證明雖然在樹中，但無法以這張回條的代碼重新計算葉節點
+	assert.Error(t, service.VerifyMerkleBundle(bundle, merkleCode(1), ""))
+	borrowed := bundle
+	borrowed.Proofs = []model.MerkleProof{proofOf(tree, 1, ballots[1])}
+	assert.NoError(t, service.VerifyMerkleBundle(borrowed, merkleCode(1), ""))
+	assert.Error(t, service.VerifyMerkleBundle(borrowed, code, ""))
+	// 改用自己回條的選票雜湊也無法通過
+	borrowed.Proofs[0].BallotHash = service.MerkleBallotHash(voteId, ballots[2]).Hex()
+	assert.Error(t, service.VerifyMerkleBundle(borrowed, code, ""))
+
+	// 修改選票數會使簽章失效
+	changed := bundle
+	changed.LeafCount = 3
+	assert.Error(t, service.VerifyMerkleBundle(changed, code, ""))
+
+	// 其他投票的證明無法通過
+	foreign := bundle
+	foreign.Proofs = []model.MerkleProof{proofOf(service.NewMerkleTree([]common.Hash{leaves[0], leaves[2], leaves[3]}), 1, ballots[2])}
+	assert.Error(t, service.VerifyMerkleBundle(foreign, code, ""))
+}
+
+func TestAnchorMerkleRoot(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	address := crypto.PubkeyToAddress(key.PublicKey)
+
+	backend := simulated.NewBackend(types.GenesisAlloc{
+		address: {Balance: new(big.Int).Mul(big.NewInt(10), big.NewInt(params.Ether))},
+	})
+	defer backend.Close()
+
+	voteId := uuid.New()
+	root := service.MerkleLeaf(voteId, merkleCode(0), merkleBallots(1)[0]).Hex()
+
+	txHash, err := service.AnchorMerkleRoot(context.Background(), backend.Client(), key, voteId, root)
+	assert.NoError(t, err)
+	backend.Commit()
+
+	receipt, err := backend.Client().TransactionReceipt(context.Background(), txHash)
+	assert.NoError(t, err)
+	assert.Equal(t, types.ReceiptStatusSuccessful, receipt.Status)
+
+	tx, _, err := backend.Client().TransactionByHash(context.Background(), txHash)
+	assert.NoError(t, err)
+	assert.Equal(t, service.MerkleAnchorData(voteId, root), tx.Data())
+	assert.Equal(t, address, *tx.To())
+}
@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/model"
+	"vote/app/service"
+	"vote/app/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+)
+
+func TestPasswordDigest(t *testing.T) {
+	t.Setenv("APP_HMAC_KEY", "test-key")
+	passwordUtil := &utils.Password{}
+
+	first, err := passwordUtil.Digest("vote-a", "abc123")
+	assert.NoError(t, err)
+	second, _ := passwordUtil.Digest("vote-a", "abc123")
+	other, _ := passwordUtil.Digest("vote-b", "abc123")
+
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first, other)
+	assert.Len(t, first, 64)
+
+	t.Setenv("APP_HMAC_KEY", "")
+	_, err = passwordUtil.Digest("vote-a", "abc123")
+	assert.Error(t, err)
+}
+
+func TestVoterLoginURL(t *testing.T) {
+	vote := model.Vote{Uuid: uuid.MustParse("00000000-0000-0000-0000-000000000001")}
+
+	t.Setenv("APP_VOTER_LOGIN_URL", "")
+	t.Setenv("HOST", "https://vote.example.com/")
+	assert.Equal(t, "https://vote.example.com/voter/login?password=a+b%26c&vote_id=00000000-0000-0000-0000-000000000001", service.VoterLoginURL(vote, "a b&c"))
+
+	t.Setenv("APP_VOTER_LOGIN_URL", "https://vote.example.com/#/login?lang=zh")
+	assert.Equal(t, "https://vote.example.com/#/login?lang=zh&password=abc&vote_id=00000000-0000-0000-0000-000000000001", service.VoterLoginURL(vote, "abc"))
+}
+
+// usePasswordKeys 設定密碼加密與摘要使用的金鑰
+func usePasswordKeys(t *testing.T) {
+	t.Setenv("APP_HMAC_KEY", "test-key")
+	t.Setenv("APP_ENCRYPT_KEY", "0123456789abcdef0123456789abcdef")
+}
+
+// exportedPasswords 匯出投票的密碼，回傳除標題外每一列的密碼欄位
+func exportedPasswords(t *testing.T, voteId uuid.UUID, format string) []string {
+	var buffer bytes.Buffer
+	err := service.NewPasswordService().ExportPasswords(&buffer, voteId, model.PasswordDownload{Format: format, Status: "all"})
+	assert.NoError(t, err)
+
+	var rows [][]string
+	if format == "xlsx" {
+		file, err := excelize.OpenReader(&buffer)
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		defer file.Close()
+		rows, err = file.GetRows(file.GetSheetName(0))
+		assert.NoError(t, err)
+	} else {
+		rows, err = csv.NewReader(&buffer).ReadAll()
+		assert.NoError(t, err)
+	}
+
+	passwords := make([]string, 0, len(rows))
+	for _, row := range rows[1:] {
+		passwords = append(passwords, row[1])
+	}
+	return passwords
+}
+
+// TestPasswordImportExport 不需資料庫：匯入的密碼可原樣匯出，錯誤的列列入報告
+func TestPasswordImportExport(t *testing.T) {
+	usePasswordKeys(t)
+	useMemoryDatabase(t, &model.Password{}, &model.Participation{})
+	voteId := uuid.New()
+
+	input := strings.Join([]string{
+		"password",
+		"alpha123",
+		"bravo456,extra column",
+		"short",
+		"alpha123",
+		"=1+1+cmd",
+		"+cmd|calc!A0",
+		"-2+3+cmd",
+		"@SUM(A1)",
+		"charlie789",
+	}, "\n")
+	report, err := service.NewPasswordService().ImportPasswords(voteId, strings.NewReader(input), true)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Imported)
+	rows := make([]int, 0, len(report.Errors))
+	for _, importErr := range report.Errors {
+		rows = append(rows, importErr.Row)
+	}
+	assert.Equal(t, []int{4, 5, 6, 7, 8, 9}, rows)
+	assert.Contains(t, report.Errors[2].Message, "may not start with")
+
+	// 再次匯入時已存在的密碼不會重複寫入
+	report, err = service.NewPasswordService().ImportPasswords(voteId, strings.NewReader("alpha123\ndelta012"), false)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Imported)
+	assert.Equal(t, "password already exists in this vote", report.Errors[0].Message)
+
+	expected := []string{"alpha123", "bravo456", "charlie789", "delta012"}
+	assert.ElementsMatch(t, expected, exportedPasswords(t, voteId, "csv"))
+	assert.ElementsMatch(t, expected, exportedPasswords(t, voteId, "xlsx"))
+
+	var states []enum.CredentialState
+	assert.NoError(t, database.SqlSession.Model(&model.Password{}).Where("vote_id = ?", voteId).Order("id ASC").Pluck("state", &states).Error)
+	assert.Equal(t, []enum.CredentialState{enum.CredentialActivated, enum.CredentialActivated, enum.CredentialActivated, enum.CredentialIssued}, states)
+}
+
+// TestPasswordExportNeutralizesFormulas 不需資料庫：匯入檢查之前寫入的公式密碼在 CSV 中不會被執行
+func TestPasswordExportNeutralizesFormulas(t *testing.T) {
+	usePasswordKeys(t)
+	useMemoryDatabase(t, &model.Password{}, &model.Participation{})
+	voteId := uuid.New()
+
+	passwordUtil := &utils.Password{}
+	for _, plain := range []string{"=1+1", "+1", "-1", "@A1", "safe123"} {
+		encrypted, err := passwordUtil.Encrypt(plain)
+		assert.NoError(t, err)
+		digest, err := passwordUtil.Digest(voteId.String(), plain)
+		assert.NoError(t, err)
+		assert.NoError(t, database.SqlSession.Create(&model.Password{VoteID: voteId, Password: encrypted, Digest: digest, State: enum.CredentialIssued}).Error)
+	}
+
+	assert.Equal(t, []string{"'=1+1", "'+1", "'-1", "'@A1", "safe123"}, exportedPasswords(t, voteId, "csv"))
+	// XLSX 以字串儲存，不會被當成公式
+	assert.Equal(t, []string{"=1+1", "+1", "-1", "@A1", "safe123"}, exportedPasswords(t, voteId, "xlsx"))
+}
@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"testing"
+	"time"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/model"
+	"vote/app/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReminderOffsets(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	vote := model.Vote{StartTime: start, EndTime: start.Add(72 * time.Hour)}
+
+	offsets, err := service.ParseReminderOffsets(vote, []string{"1h", "24h", "90m"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{86400, 5400, 3600}, offsets)
+
+	invalid := [][]string{
+		{"soon"},
+		{"30s"},
+		{"-1h"},
+		{"1h", "60m"},
+		{"72h"},
+	}
+	for _, values := range invalid {
+		_, err := service.ParseReminderOffsets(vote, values)
+		assert.Error(t, err, values)
+	}
+}
+
+// TestRunReminderRecipients 不需資料庫：提醒只寄給密碼仍可使用且尚未投票的投票者，且只執行一次
+func TestRunReminderRecipients(t *testing.T) {
+	useMemoryDatabase(t, &model.VoteReminder{}, &model.Password{}, &model.Participation{}, &model.Voter{}, &model.MailJob{})
+
+	voteId := uuid.New()
+	now := time.Now()
+	states := []enum.CredentialState{enum.CredentialIssued, enum.CredentialActivated, enum.CredentialUsed, enum.CredentialRevoked, enum.CredentialExpired}
+	var expected []uint64
+	for i, state := range states {
+		password := model.Password{VoteID: voteId, Password: "secret", Digest: uuid.NewString(), State: state}
+		assert.NoError(t, database.SqlSession.Create(&password).Error)
+		if state == enum.CredentialUsed {
+			assert.NoError(t, database.SqlSession.Create(&model.Participation{PasswordID: password.ID, VoteID: voteId, VotedAt: now}).Error)
+		}
+		voter := model.Voter{VoteID: voteId, Name: string(state), Email: string(state) + "@example.com", PasswordID: password.ID}
+		assert.NoError(t, database.SqlSession.Create(&voter).Error)
+		if i < 2 {
+			expected = append(expected, voter.ID)
+		}
+	}
+	reminder := model.VoteReminder{VoteID: voteId, Offset: 3600}
+	assert.NoError(t, database.SqlSession.Create(&reminder).Error)
+
+	ran, ok, err := service.NewReminderService().RunReminder(reminder, now)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, ran.Recipients)
+	assert.Equal(t, 3, ran.Skipped)
+
+	var recipients []uint64
+	assert.NoError(t, database.SqlSession.Model(&model.MailJob{}).Order("voter_id ASC").Pluck("voter_id", &recipients).Error)
+	assert.Equal(t, expected, recipients)
+
+	// 已執行的提醒不會再次排入
+	_, ok, err = service.NewReminderService().RunReminder(reminder, now)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+}
+
+// TestDueReminders 不需資料庫：只回傳開放中投票已到寄送時間且尚未執行的提醒
+func TestDueReminders(t *testing.T) {
+	useMemoryDatabase(t, &model.Vote{}, &model.VoteReminder{})
+
+	now := time.Now()
+	votes := []model.Vote{
+		{Uuid: uuid.New(), Title: "Due", StartTime: now.Add(-time.Hour), EndTime: now.Add(30 * time.Minute), Status: enum.Open},
+		{Uuid: uuid.New(), Title: "Later", StartTime: now.Add(-time.Hour), EndTime: now.Add(2 * time.Hour), Status: enum.Open},
+		{Uuid: uuid.New(), Title: "Closed", StartTime: now.Add(-time.Hour), EndTime: now.Add(30 * time.Minute), Status: enum.Closed},
+	}
+	for _, vote := range votes {
+		assert.NoError(t, database.SqlSession.Create(&vote).Error)
+	}
+	reminders := []model.VoteReminder{
+		{VoteID: votes[0].Uuid, Offset: 3600},
+		{VoteID: votes[0].Uuid, Offset: 7200, RunAt: &now},
+		{VoteID: votes[1].Uuid, Offset: 3600},
+		{VoteID: votes[2].Uuid, Offset: 3600},
+	}
+	assert.NoError(t, database.SqlSession.Create(&reminders).Error)
+
+	result, err := service.NewReminderService().DueReminders(now)
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	assert.Equal(t, reminders[0].ID, result[0].ID)
+}
@@ -0,0 +1,139 @@
+package tests
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/model"
+	"vote/app/service"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// useSchedulerDatabase 建立排程執行時會用到的資料表，開票時簽署 Merkle 樹根但不送上鏈
+func useSchedulerDatabase(t *testing.T) {
+	t.Helper()
+
+	useMemoryDatabase(t, &model.Vote{}, &model.Question{}, &model.Candidate{}, &model.VoteTransition{}, &model.SchedulerLease{},
+		&model.Ballot{}, &model.BallotSelect{}, &model.BallotReceipt{}, &model.Participation{}, &model.VoteResult{}, &model.MerkleRoot{},
+		&model.VoteReminder{}, &model.Password{}, &model.Voter{}, &model.MailJob{}, &model.CredentialAudit{})
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	t.Setenv("SMART_CONTRACT_PRIVATE_KEY", hex.EncodeToString(crypto.FromECDSA(key)))
+	t.Setenv("ETH_RPC_URL", "")
+}
+
+// scheduledVote 建立一個有問題的已排程投票
+func scheduledVote(t *testing.T, start time.Time, end time.Time) model.Vote {
+	t.Helper()
+
+	vote := model.Vote{Uuid: uuid.New(), Title: "Scheduled", StartTime: start, EndTime: end, Status: enum.Scheduled}
+	assert.NoError(t, database.SqlSession.Create(&vote).Error)
+	question := model.Question{VoteID: vote.Uuid, Title: "Question", Method: enum.Plurality, Seats: 1}
+	assert.NoError(t, database.SqlSession.Create(&question).Error)
+	assert.NoError(t, database.SqlSession.Create(&model.Candidate{QuestionID: question.ID, Name: "Candidate"}).Error)
+
+	return vote
+}
+
+func voteStatus(t *testing.T, voteId uuid.UUID) enum.VoteStatus {
+	t.Helper()
+
+	vote, err := service.NewVoteService().GetVote(voteId)
+	assert.NoError(t, err)
+
+	return vote.Status
+}
+
+// TestSchedulerLease 不需資料庫：租約有效期間只有持有者會處理投票，過期後才由其他服務接手
+func TestSchedulerLease(t *testing.T) {
+	useSchedulerDatabase(t)
+
+	now := time.Now()
+	first := service.NewVoteScheduler()
+	second := service.NewVoteScheduler()
+	assert.NoError(t, first.Tick(now))
+
+	vote := scheduledVote(t, now.Add(-time.Minute), now.Add(time.Hour))
+	assert.NoError(t, second.Tick(now.Add(time.Second)))
+	assert.Equal(t, enum.Scheduled, voteStatus(t, vote.Uuid))
+
+	var lease model.SchedulerLease
+	assert.NoError(t, database.SqlSession.First(&lease).Error)
+	holder := lease.Holder
+
+	// 持有者延長租約並處理投票
+	assert.NoError(t, first.Tick(now.Add(2*time.Second)))
+	assert.Equal(t, enum.Open, voteStatus(t, vote.Uuid))
+
+	// 租約過期後由另一個服務接手，原持有者在新租約有效期間不再執行
+	expired := now.Add(2*time.Second + first.LeaseTTL + time.Second)
+	assert.NoError(t, second.Tick(expired))
+	assert.NoError(t, database.SqlSession.First(&lease).Error)
+	assert.NotEqual(t, holder, lease.Holder)
+
+	assert.NoError(t, first.Tick(expired.Add(time.Second)))
+	assert.NoError(t, database.SqlSession.First(&lease).Error)
+	assert.NotEqual(t, holder, lease.Holder)
+}
+
+// TestSchedulerIdempotent 不需資料庫：重複執行排程不會重複轉換狀態
+func TestSchedulerIdempotent(t *testing.T) {
+	useSchedulerDatabase(t)
+
+	now := time.Now()
+	vote := scheduledVote(t, now.Add(-time.Minute), now.Add(time.Hour))
+	scheduler := service.NewVoteScheduler()
+	var fired []enum.VoteStatus
+	scheduler.OnTransition(func(vote model.Vote, from enum.VoteStatus, to enum.VoteStatus) {
+		fired = append(fired, to)
+	})
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, scheduler.Tick(now.Add(time.Duration(i)*time.Second)))
+	}
+	assert.Equal(t, enum.Open, voteStatus(t, vote.Uuid))
+	assert.Equal(t, []enum.VoteStatus{enum.Open}, fired)
+
+	transitions, err := service.NewVoteService().GetVoteTransitions(vote.Uuid)
+	assert.NoError(t, err)
+	assert.Len(t, transitions, 1)
+}
+
+// TestSchedulerCatchUp 不需資料庫：服務停機期間開始與結束時間都已經過的投票，在下一次排程中依序開放、結束並開票
+func TestSchedulerCatchUp(t *testing.T) {
+	useSchedulerDatabase(t)
+
+	now := time.Now()
+	missed := scheduledVote(t, now.Add(-2*time.Hour), now.Add(-time.Hour))
+	// 寬限期尚未結束的投票只結束，不開票
+	grace := scheduledVote(t, now.Add(-2*time.Hour), now.Add(-time.Minute))
+	assert.NoError(t, database.SqlSession.Model(&grace).Update("grace_period", 300).Error)
+
+	scheduler := service.NewVoteScheduler()
+	var fired []enum.VoteStatus
+	scheduler.OnTransition(func(vote model.Vote, from enum.VoteStatus, to enum.VoteStatus) {
+		if vote.Uuid == missed.Uuid {
+			fired = append(fired, to)
+		}
+	})
+
+	assert.NoError(t, scheduler.Tick(now))
+	assert.Equal(t, enum.Tallied, voteStatus(t, missed.Uuid))
+	assert.Equal(t, []enum.VoteStatus{enum.Open, enum.Closed, enum.Tallied}, fired)
+	assert.Equal(t, enum.Closed, voteStatus(t, grace.Uuid))
+
+	transitions, err := service.NewVoteService().GetVoteTransitions(missed.Uuid)
+	assert.NoError(t, err)
+	assert.Len(t, transitions, 3)
+
+	// 寬限期過後的排程補上開票
+	ended := now.Add(-10 * time.Minute)
+	assert.NoError(t, database.SqlSession.Model(&grace).Updates(map[string]any{"end_time": ended, "closed_at": ended}).Error)
+	assert.NoError(t, scheduler.Tick(now))
+	assert.Equal(t, enum.Tallied, voteStatus(t, grace.Uuid))
+}
@@ -0,0 +1,350 @@
+package tests
+
+import (
+	"encoding/hex"
+	"testing"
+	"time"
+	"vote/app/database"
+	"vote/app/enum"
+	"vote/app/model"
+	"vote/app/service"
+
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func selectBallot(candidateIds ...uint64) model.Ballot {
+	ballot := model.Ballot{QuestionID: 1}
+	for _, id := range candidateIds {
+		ballot.BallotSelects = append(ballot.BallotSelects, model.BallotSelect{CandidateID: id})
+	}
+	return ballot
+}
+
+func TestCountQuestion(t *testing.T) {
+	question := model.Question{
+		ID:    1,
+		Title: "Chair",
+		Candidates: []model.Candidate{
+			{ID: 10, Name: "Alice"},
+			{ID: 11, Name: "Bob"},
+			{ID: 12, Name: "Carol"},
+		},
+	}
+
+	t.Run("Counts votes, blanks and abstentions", func(t *testing.T) {
+		ballots := []model.Ballot{
+			selectBallot(10),
+			selectBallot(10),
+			selectBallot(11),
+			selectBallot(),
+			// 不屬於此問題的候選人不計入
+			selectBallot(99),
+		}
+
+		result := service.CountQuestion(question, ballots, 6, service.TieBreaker{})
+
+		assert.Equal(t, int64(5), result.TotalBallots)
+		assert.Equal(t, int64(2), result.BlankBallots)
+		assert.Equal(t, int64(3), result.ValidBallots)
+		assert.Equal(t, int64(1), result.Abstentions)
+		assert.Equal(t, []uint64{10}, result.Winners)
+		assert.Equal(t, int64(2), result.Candidates[0].Votes)
+		assert.Equal(t, 66.67, result.Candidates[0].Percentage)
+		assert.Equal(t, int64(1), result.Candidates[1].Votes)
+		assert.Equal(t, int64(0), result.Candidates[2].Votes)
+	})
+
+	t.Run("Ties list every leader", func(t *testing.T) {
+		ballots := []model.Ballot{selectBallot(11), selectBallot(12)}
+
+		result := service.CountQuestion(question, ballots, 2, service.TieBreaker{})
+
+		assert.Equal(t, []uint64{11, 12}, result.Winners)
+		assert.Equal(t, 50.0, result.Candidates[1].Percentage)
+	})
+
+	t.Run("No ballots", func(t *testing.T) {
+		result := service.CountQuestion(question, nil, 0, service.TieBreaker{})
+
+		assert.Empty(t, result.Winners)
+		assert.Equal(t, 0.0, result.Candidates[0].Percentage)
+	})
+}
+
+func rankedBallot(candidateIds ...uint64) model.Ballot {
+	ballot := model.Ballot{QuestionID: 2}
+	for i, id := range candidateIds {
+		ballot.BallotSelects = append(ballot.BallotSelects, model.BallotSelect{CandidateID: id, Rank: i + 1})
+	}
+	return ballot
+}
+
+func TestCountInstantRunoff(t *testing.T) {
+	question := model.Question{
+		ID:     2,
+		Title:  "Board",
+		Method: enum.IRV,
+		Candidates: []model.Candidate{
+			{ID: 1, Name: "Alice"},
+			{ID: 2, Name: "Bob"},
+			{ID: 3, Name: "Carol"},
+		},
+	}
+
+	t.Run("Eliminates the lowest candidate and transfers ballots", func(t *testing.T) {
+		var ballots []model.Ballot
+		for i := 0; i < 4; i++ {
+			ballots = append(ballots, rankedBallot(1, 2))
+		}
+		for i := 0; i < 3; i++ {
+			ballots = append(ballots, rankedBallot(2, 1))
+		}
+		ballots = append(ballots, rankedBallot(3, 2), rankedBallot(3, 2), rankedBallot(3))
+
+		result := service.CountQuestion(question, ballots, 10, service.TieBreaker{})
+
+		assert.Equal(t, []uint64{2}, result.Winners)
+		assert.Equal(t, int64(4), result.Candidates[0].Votes)
+		assert.Len(t, result.Rounds, 2)
+		assert.Equal(t, []uint64{3}, result.Rounds[0].Eliminated)
+		assert.Equal(t, []model.RoundTransfer{
+			{From: 3, To: 2, Votes: 2},
+			{From: 3, To: 0, Votes: 1},
+		}, result.Rounds[0].Transfers)
+		assert.Equal(t, []model.RoundCount{
+			{CandidateID: 1, Votes: 4},
+			{CandidateID: 2, Votes: 5},
+		}, result.Rounds[1].Counts)
+		assert.Equal(t, 1.0, result.Rounds[1].Exhausted)
+		assert.Equal(t, []uint64{2}, result.Rounds[1].Elected)
+	})
+
+	t.Run("Majority in the first round", func(t *testing.T) {
+		ballots := []model.Ballot{rankedBallot(1), rankedBallot(1, 3), rankedBallot(2)}
+
+		result := service.CountQuestion(question, ballots, 3, service.TieBreaker{})
+
+		assert.Equal(t, []uint64{1}, result.Winners)
+		assert.Len(t, result.Rounds, 1)
+	})
+
+	t.Run("Blank ranked ballots", func(t *testing.T) {
+		result := service.CountQuestion(question, []model.Ballot{rankedBallot()}, 1, service.TieBreaker{})
+
+		assert.Equal(t, int64(1), result.BlankBallots)
+		assert.Empty(t, result.Winners)
+	})
+}
+
+func TestCountSingleTransferable(t *testing.T) {
+	question := model.Question{
+		ID:     3,
+		Title:  "Committee",
+		Method: enum.STV,
+		Seats:  2,
+		Candidates: []model.Candidate{
+			{ID: 1, Name: "Alice"},
+			{ID: 2, Name: "Bob"},
+			{ID: 3, Name: "Carol"},
+		},
+	}
+
+	var ballots []model.Ballot
+	for i := 0; i < 6; i++ {
+		ballots = append(ballots, rankedBallot(1, 2))
+	}
+	ballots = append(ballots, rankedBallot(2), rankedBallot(2))
+	for i := 0; i < 3; i++ {
+		ballots = append(ballots, rankedBallot(3))
+	}
+
+	result := service.CountQuestion(question, ballots, 11, service.TieBreaker{})
+
+	assert.Equal(t, 4.0, result.Quota)
+	assert.Equal(t, []uint64{1, 2}, result.Winners)
+	assert.Len(t, result.Rounds, 2)
+	assert.Equal(t, []uint64{1}, result.Rounds[0].Elected)
+	assert.Equal(t, []model.RoundTransfer{{From: 1, To: 2, Votes: 2}}, result.Rounds[0].Transfers)
+	assert.Equal(t, []model.RoundCount{
+		{CandidateID: 1, Votes: 4},
+		{CandidateID: 2, Votes: 4},
+		{CandidateID: 3, Votes: 3},
+	}, result.Rounds[1].Counts)
+	assert.Equal(t, []uint64{2}, result.Rounds[1].Elected)
+}
+
+func TestTieBreaker(t *testing.T) {
+	history := []map[uint64]float64{
+		{1: 1, 2: 2},
+		{1: 3, 2: 2},
+	}
+
+	assert.Equal(t, uint64(2), service.TieBreaker{Rule: enum.Backward}.Break([]uint64{1, 2}, history, true))
+	assert.Equal(t, uint64(1), service.TieBreaker{Rule: enum.Forward}.Break([]uint64{1, 2}, history, true))
+	assert.Equal(t, uint64(2), service.TieBreaker{Rule: enum.CandidateOrder}.Break([]uint64{1, 2}, history, true))
+	assert.Equal(t, uint64(1), service.TieBreaker{Rule: enum.CandidateOrder}.Break([]uint64{1, 2}, history, false))
+
+	seeded := service.TieBreaker{Rule: enum.Seeded, Seed: "00000000-0000-0000-0000-000000000001"}
+	chosen := seeded.Break([]uint64{1, 2, 3}, history, true)
+	assert.Equal(t, chosen, seeded.Break([]uint64{3, 2, 1}, history, true))
+}
+
+func TestCountCondorcet(t *testing.T) {
+	question := model.Question{
+		ID:     4,
+		Title:  "Schulze",
+		Method: enum.Condorcet,
+		Candidates: []model.Candidate{
+			{ID: 1, Name: "A"},
+			{ID: 2, Name: "B"},
+			{ID: 3, Name: "C"},
+			{ID: 4, Name: "D"},
+			{ID: 5, Name: "E"},
+		},
+	}
+
+	t.Run("Falls back to Schulze without a Condorcet winner", func(t *testing.T) {
+		groups := []struct {
+			count int
+			order []uint64
+		}{
+			{5, []uint64{1, 3, 2, 5, 4}},
+			{5, []uint64{1, 4, 5, 3, 2}},
+			{8, []uint64{2, 5, 4, 1, 3}},
+			{3, []uint64{3, 1, 2, 5, 4}},
+			{7, []uint64{3, 1, 5, 2, 4}},
+			{2, []uint64{3, 2, 1, 4, 5}},
+			{7, []uint64{4, 3, 5, 2, 1}},
+			{8, []uint64{5, 2, 1, 4, 3}},
+		}
+		var ballots []model.Ballot
+		for _, group := range groups {
+			for i := 0; i < group.count; i++ {
+				ballots = append(ballots, rankedBallot(group.order...))
+			}
+		}
+
+		result := service.CountQuestion(question, ballots, 45, service.TieBreaker{})
+
+		assert.Nil(t, result.CondorcetWinner)
+		assert.Equal(t, []uint64{5, 1, 3, 2, 4}, result.Ranking)
+		assert.Equal(t, []uint64{5}, result.Winners)
+		// A 對 B 為 20 票
+		assert.Equal(t, model.PairwiseCount{CandidateID: 2, Votes: 20}, result.Pairwise[0].Against[0])
+	})
+
+	t.Run("Condorcet winner", func(t *testing.T) {
+		ballots := []model.Ballot{
+			rankedBallot(2, 1),
+			rankedBallot(2, 3),
+			rankedBallot(1, 2),
+			rankedBallot(3),
+		}
+
+		result := service.CountQuestion(question, ballots, 4, service.TieBreaker{})
+
+		assert.NotNil(t, result.CondorcetWinner)
+		assert.Equal(t, uint64(2), *result.CondorcetWinner)
+		assert.Equal(t, []uint64{2}, result.Winners)
+	})
+}
+
+func TestCountPointMethods(t *testing.T) {
+	candidates := []model.Candidate{
+		{ID: 1, Name: "Alice"},
+		{ID: 2, Name: "Bob"},
+		{ID: 3, Name: "Carol"},
+	}
+
+	t.Run("Approval counts every approved candidate", func(t *testing.T) {
+		question := model.Question{ID: 1, Method: enum.Approval, Candidates: candidates}
+		ballots := []model.Ballot{selectBallot(1, 2), selectBallot(2, 3), selectBallot(2)}
+
+		result := service.CountQuestion(question, ballots, 3, service.TieBreaker{})
+
+		assert.Equal(t, []uint64{2}, result.Winners)
+		assert.Equal(t, int64(3), result.Candidates[1].Votes)
+		assert.Equal(t, 100.0, result.Candidates[1].Percentage)
+	})
+
+	t.Run("Borda awards n-1 points to the first preference", func(t *testing.T) {
+		question := model.Question{ID: 2, Method: enum.Borda, Candidates: candidates}
+		ballots := []model.Ballot{
+			rankedBallot(1, 2, 3),
+			rankedBallot(1, 3, 2),
+			rankedBallot(2, 3, 1),
+			rankedBallot(3),
+			rankedBallot(3, 2),
+		}
+
+		result := service.CountQuestion(question, ballots, 5, service.TieBreaker{})
+
+		assert.Equal(t, int64(4), result.Candidates[0].Votes)
+		assert.Equal(t, int64(4), result.Candidates[1].Votes)
+		assert.Equal(t, int64(6), result.Candidates[2].Votes)
+		assert.Equal(t, []uint64{3}, result.Winners)
+		assert.Equal(t, 42.86, result.Candidates[2].Percentage)
+	})
+
+	t.Run("Score sums values and averages over valid ballots", func(t *testing.T) {
+		question := model.Question{ID: 1, Method: enum.Score, MaxScore: 5, Candidates: candidates}
+		scoreBallot := func(values ...int) model.Ballot {
+			ballot := model.Ballot{QuestionID: 1}
+			for i, value := range values {
+				ballot.BallotSelects = append(ballot.BallotSelects, model.BallotSelect{CandidateID: uint64(i + 1), Value: value})
+			}
+			return ballot
+		}
+		ballots := []model.Ballot{scoreBallot(5, 2, 0), scoreBallot(3, 4, 1), scoreBallot(0, 5, 0)}
+
+		result := service.CountQuestion(question, ballots, 3, service.TieBreaker{})
+
+		assert.Equal(t, int64(8), result.Candidates[0].Votes)
+		assert.Equal(t, int64(11), result.Candidates[1].Votes)
+		assert.Equal(t, 3.67, result.Candidates[1].Average)
+		assert.Equal(t, []uint64{2}, result.Winners)
+	})
+}
+
+func TestTallyVote(t *testing.T) {
+	useMemoryDatabase(t, &model.Vote{}, &model.Question{}, &model.Candidate{}, &model.Ballot{}, &model.BallotSelect{},
+		&model.BallotReceipt{}, &model.Participation{}, &model.VoteResult{}, &model.VoteTransition{}, &model.MerkleRoot{})
+	key, err := crypto.GenerateKey()
+	assert.NoError(t, err)
+	t.Setenv("SMART_CONTRACT_PRIVATE_KEY", hex.EncodeToString(crypto.FromECDSA(key)))
+	t.Setenv("ETH_RPC_URL", "")
+
+	// 提前結束的投票在寬限期內仍接受選票，不能開票
+	closedAt := time.Now()
+	vote := model.Vote{
+		Uuid:        uuid.New(),
+		Title:       "Early close",
+		StartTime:   closedAt.Add(-time.Hour),
+		EndTime:     closedAt.Add(time.Hour),
+		Status:      enum.Closed,
+		GracePeriod: 300,
+		ClosedAt:    &closedAt,
+	}
+	assert.NoError(t, database.SqlSession.Create(&vote).Error)
+
+	_, err = service.NewTallyService().TallyVote(vote.Uuid, nil)
+	var transitionErr *service.TransitionError
+	assert.ErrorAs(t, err, &transitionErr)
+
+	// 寬限期過後開票並轉為已開票
+	closedAt = closedAt.Add(-10 * time.Minute)
+	assert.NoError(t, database.SqlSession.Model(&vote).Update("closed_at", closedAt).Error)
+	_, err = service.NewTallyService().TallyVote(vote.Uuid, nil)
+	assert.NoError(t, err)
+	tallied, err := service.NewVoteService().GetVote(vote.Uuid)
+	assert.NoError(t, err)
+	assert.Equal(t, enum.Tallied, tallied.Status)
+
+	// 開票時一併公布簽署的 Merkle 樹根
+	var root model.MerkleRoot
+	assert.NoError(t, database.SqlSession.Where("vote_id = ?", vote.Uuid).First(&root).Error)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey).Hex(), root.Signer)
+	assert.Equal(t, 0, root.LeafCount)
+}
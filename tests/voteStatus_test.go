@@ -0,0 +1,42 @@
+package tests
+
+import (
+	"encoding/json"
+	"testing"
+	"vote/app/enum"
+	"vote/app/model"
+	"vote/app/service"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVoteStatus(t *testing.T) {
+	t.Run("Allowed transitions", func(t *testing.T) {
+		assert.True(t, enum.Draft.CanTransitionTo(enum.Scheduled))
+		assert.True(t, enum.Scheduled.CanTransitionTo(enum.Draft))
+		assert.True(t, enum.Closed.CanTransitionTo(enum.Tallied))
+		assert.True(t, enum.Tallied.CanTransitionTo(enum.Published))
+		assert.False(t, enum.Draft.CanTransitionTo(enum.Open))
+		assert.False(t, enum.Closed.CanTransitionTo(enum.Published))
+		assert.False(t, enum.Open.CanTransitionTo(enum.Scheduled))
+		assert.False(t, enum.Archived.CanTransitionTo(enum.Draft))
+	})
+
+	t.Run("Questions are locked once open", func(t *testing.T) {
+		assert.NoError(t, service.CheckVoteEditable(model.Vote{Status: enum.Scheduled}))
+		assert.ErrorIs(t, service.CheckVoteEditable(model.Vote{Status: enum.Open}), service.ErrVoteLocked)
+	})
+
+	t.Run("JSON accepts names and legacy integers", func(t *testing.T) {
+		var form model.VoteTransitionCreate
+		assert.NoError(t, json.Unmarshal([]byte(`{"status":"published"}`), &form))
+		assert.Equal(t, enum.Published, *form.Status)
+		assert.NoError(t, json.Unmarshal([]byte(`{"status":2}`), &form))
+		assert.Equal(t, enum.Open, *form.Status)
+		assert.Error(t, json.Unmarshal([]byte(`{"status":"running"}`), &form))
+
+		data, err := json.Marshal(model.Vote{Status: enum.Tallied})
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"status":"tallied"`)
+	})
+}